@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tcarcao/craft/internal/analysis"
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runAnalyze handles "craft analyze [-env <name>] [-format table|json|html] [-output <file>] [thresholds] <file>"
+func runAnalyze(args []string) error {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	format := flags.String("format", "table", "Output format: table, json or html")
+	output := flags.String("output", "", "Write the report to a file instead of standard output")
+	chatty := flags.Int("chatty", analysis.DefaultOptions().ChattyThreshold, "Distinct sync calls between two services that make them chatty")
+	failOnCycles := flags.Bool("fail-on-cycles", false, "Exit with status 1 when services depend on each other in a cycle")
+	failOnChatty := flags.Bool("fail-on-chatty", false, "Exit with status 1 when a service pair is chatty")
+	maxChain := flags.Int("max-chain", 0, "Exit with status 1 when a use case chains more sync calls (0 disables)")
+	maxInstability := flags.Float64("max-instability", 0, "Exit with status 1 when a depended-on service is more unstable (0 disables)")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: craft analyze [-env <name>] [-format table|json|html] [-output <file>] [-fail-on-cycles] [-max-chain <n>] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	report, err := proc.AnalyzeFile(flags.Arg(0), analysis.Options{ChattyThreshold: *chatty})
+	if err != nil {
+		return fmt.Errorf("failed to analyze file: %v", err)
+	}
+
+	var content []byte
+	switch *format {
+	case "table":
+		content = []byte(report.Table())
+	case "json":
+		if content, err = report.JSON(); err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		content = append(content, '\n')
+	case "html":
+		title := fmt.Sprintf("Architecture analysis: %s", filepath.Base(flags.Arg(0)))
+		if content, err = report.HTML(title); err != nil {
+			return fmt.Errorf("failed to render report: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported analyze format %q", *format)
+	}
+
+	if *output != "" {
+		if err := os.WriteFile(*output, content, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+	} else {
+		os.Stdout.Write(content)
+	}
+
+	breaches := report.Check(analysis.Thresholds{
+		FailOnCycles:   *failOnCycles,
+		FailOnChatty:   *failOnChatty,
+		MaxChainHops:   *maxChain,
+		MaxInstability: *maxInstability,
+	})
+	if len(breaches) > 0 {
+		for _, breach := range breaches {
+			fmt.Fprintln(os.Stderr, "threshold exceeded:", breach)
+		}
+		os.Exit(1)
+	}
+	return nil
+}
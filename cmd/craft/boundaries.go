@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/analysis"
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runBoundaries handles "craft boundaries [-env <name>] [-format table|json] [-sync-weight <w>] [-async-weight <w>] <file>"
+func runBoundaries(args []string) error {
+	defaults := analysis.DefaultBoundaryOptions()
+	flags := flag.NewFlagSet("boundaries", flag.ExitOnError)
+	format := flags.String("format", "table", "Output format: table or json")
+	syncWeight := flags.Float64("sync-weight", defaults.SyncWeight, "Weight of each sync call between two domains")
+	asyncWeight := flags.Float64("async-weight", defaults.AsyncWeight, "Weight of each event a domain consumes from another")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: craft boundaries [-env <name>] [-format table|json] [-sync-weight <w>] [-async-weight <w>] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	report, err := proc.RecommendBoundaries(flags.Arg(0), analysis.BoundaryOptions{SyncWeight: *syncWeight, AsyncWeight: *asyncWeight})
+	if err != nil {
+		return fmt.Errorf("failed to analyze file: %v", err)
+	}
+
+	switch *format {
+	case "table":
+		fmt.Print(report.Table())
+	case "json":
+		content, err := report.JSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		fmt.Println(string(content))
+	default:
+		return fmt.Errorf("unsupported boundaries format %q", *format)
+	}
+	return nil
+}
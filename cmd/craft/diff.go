@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/processor"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+// runDiff handles "craft diff [-env <name>] [-format text|json|diagram] [-output <dir>] <old-file> <new-file>"
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format: text, json or diagram")
+	outputDir := flags.String("output", "diff", "Output directory for diagram output")
+	imageFormat := flags.String("image", "png", "Diagram image format: png, svg, pdf or puml")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		fmt.Println("Usage: craft diff [-env <name>] [-format text|json|diagram] [-output <dir>] <old-file> <new-file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	modelDiff, err := proc.DiffFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("failed to diff files: %v", err)
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(modelDiff.Text())
+	case "json":
+		content, err := modelDiff.JSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode diff: %v", err)
+		}
+		fmt.Println(string(content))
+	case "diagram":
+		if err := proc.GenerateDiffDiagrams(modelDiff, *outputDir, visualizer.SupportedFormat(*imageFormat)); err != nil {
+			return err
+		}
+		fmt.Println("Successfully generated diff diagrams in:", *outputDir)
+	default:
+		return fmt.Errorf("unsupported diff format %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runExport handles "craft export <format> [-env <name>] [-tag <tags>] [-filter <expression>] -input <craft-file> -output <output-dir>"
+func runExport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: craft export openapi [-env <name>] [-tag <tags>] [-filter <expression>] -input <craft-file> -output <output-dir>")
+	}
+
+	format := args[0]
+	flags := flag.NewFlagSet("export "+format, flag.ExitOnError)
+	inputFile := flags.String("input", "", "Input Craft file path")
+	outputDir := flags.String("output", "", "Output directory for exported files")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	tags := flags.String("tag", "", "Comma-separated tags the export is narrowed to, e.g. pci (default: whole model)")
+	filterExpr := flags.String("filter", "", "Filter expression the export is narrowed with, e.g. owner=team-payments (default: whole model)")
+	flags.Parse(args[1:])
+
+	if *inputFile == "" || *outputDir == "" {
+		fmt.Printf("Usage: craft export %s [-env <name>] [-tag <tags>] [-filter <expression>] -input <craft-file> -output <output-dir>\n", format)
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	f, err := parseFilter(*filterExpr, *tags)
+	if err != nil {
+		return err
+	}
+	proc.SetEnvironment(*env)
+	proc.SetFilter(f)
+
+	switch format {
+	case "openapi":
+		if err := proc.ExportOpenAPI(*inputFile, *outputDir); err != nil {
+			return fmt.Errorf("failed to export OpenAPI: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+
+	fmt.Println("Successfully exported", format, "files to:", *outputDir)
+	return nil
+}
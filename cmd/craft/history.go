@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/tcarcao/craft/internal/history"
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runHistory handles "craft history [-since <rev>] [-repo <dir>] [-format text|json] [paths...]"
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	since := flags.String("since", "", "Revision to start from (tag, branch or commit); defaults to the first commit")
+	repoDir := flags.String("repo", ".", "Path to the git repository")
+	format := flags.String("format", "text", "Output format: text or json")
+	flags.Parse(args)
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+
+	entries, err := proc.History(*repoDir, *since, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(history.FormatText(entries))
+	case "json":
+		content, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode history: %v", err)
+		}
+		fmt.Println(string(content))
+	default:
+		return fmt.Errorf("unsupported history format %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/processor"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+// runImpact handles "craft impact [-env <name>] [-filter <expression>] -domain <name> [-format text|json|diagram] [-output <dir>] <file>"
+func runImpact(args []string) error {
+	flags := flag.NewFlagSet("impact", flag.ExitOnError)
+	domain := flags.String("domain", "", "Domain whose change is analysed")
+	format := flags.String("format", "text", "Output format: text, json or diagram")
+	outputDir := flags.String("output", "impact", "Output directory for diagram output")
+	imageFormat := flags.String("image", "png", "Diagram image format: png, svg, pdf or puml")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	filterExpr := flags.String("filter", "", "Filter expression the analysed model is narrowed with, e.g. owner=team-payments (default: whole model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *domain == "" {
+		fmt.Println("Usage: craft impact [-env <name>] [-filter <expression>] -domain <name> [-format text|json|diagram] [-output <dir>] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	f, err := parseFilter(*filterExpr, "")
+	if err != nil {
+		return err
+	}
+	proc.SetEnvironment(*env)
+	proc.SetFilter(f)
+
+	report, err := proc.ImpactOf(flags.Arg(0), *domain)
+	if err != nil {
+		return fmt.Errorf("failed to analyze impact: %v", err)
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(report.Text())
+	case "json":
+		content, err := report.JSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode impact: %v", err)
+		}
+		fmt.Println(string(content))
+	case "diagram":
+		if err := proc.GenerateImpactDiagrams(report, *outputDir, visualizer.SupportedFormat(*imageFormat)); err != nil {
+			return err
+		}
+		fmt.Println("Successfully generated impact diagrams in:", *outputDir)
+	default:
+		return fmt.Errorf("unsupported impact format %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/importer"
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runImport handles "craft import openapi|asyncapi <spec> [-output <craft-file>]"
+func runImport(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: craft import openapi|asyncapi <spec> [-output <craft-file>]")
+	}
+
+	format := importer.SpecFormat(args[0])
+	if format != importer.SpecOpenAPI && format != importer.SpecAsyncAPI {
+		return fmt.Errorf("unsupported spec format %q", args[0])
+	}
+	specPath := args[1]
+
+	flags := flag.NewFlagSet("import "+args[0], flag.ExitOnError)
+	outputFile := flags.String("output", "", "Output Craft file (defaults to stdout)")
+	flags.Parse(args[2:])
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+
+	source, err := proc.ImportSpec(format, specPath)
+	if err != nil {
+		return err
+	}
+
+	if *outputFile == "" {
+		fmt.Print(source)
+		return nil
+	}
+
+	if err := os.WriteFile(*outputFile, []byte(source), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *outputFile, err)
+	}
+	fmt.Println("Successfully imported", specPath, "into:", *outputFile)
+	return nil
+}
+
+// runFmt handles "craft fmt [-w] <craft-file>"
+func runFmt(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "Write result to the source file instead of stdout")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: craft fmt [-w] <craft-file>")
+	}
+	inputFile := flags.Arg(0)
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+
+	source, err := proc.FormatFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	if !*write {
+		fmt.Print(source)
+		return nil
+	}
+	return os.WriteFile(inputFile, []byte(source), 0644)
+}
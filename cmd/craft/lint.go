@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/linter"
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runLint handles "craft lint [-env <name>] [-rules <file>] [-format text|json] <file>"
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	rulesFiles := flags.String("rules", "", "Comma-separated Craft files with additional rules blocks")
+	format := flags.String("format", "text", "Output format: text or json")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: craft lint [-env <name>] [-rules <file>] [-format text|json] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	rulesPaths := splitList(*rulesFiles)
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	diagnostics, err := proc.LintFile(flags.Arg(0), rulesPaths)
+	if err != nil {
+		return fmt.Errorf("failed to lint file: %v", err)
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(linter.FormatText(diagnostics))
+	case "json":
+		content, err := linter.FormatJSON(diagnostics)
+		if err != nil {
+			return fmt.Errorf("failed to encode diagnostics: %v", err)
+		}
+		fmt.Println(string(content))
+	default:
+		return fmt.Errorf("unsupported lint format %q", *format)
+	}
+
+	// A non-zero exit lets CI fail the build on rule violations
+	if len(diagnostics) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -7,18 +7,33 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
+	"github.com/tcarcao/craft/internal/filter"
+	"github.com/tcarcao/craft/internal/parser"
 	"github.com/tcarcao/craft/internal/processor"
 )
 
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runSubcommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	inputFile := flag.String("input", "", "Input Craft file path")
 	outputDir := flag.String("output", "", "Output directory for generated diagrams")
+	env := flag.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	tags := flag.String("tag", "", "Comma-separated tags the diagrams are narrowed to, e.g. pci (default: whole model)")
+	useCases := flag.String("use-case", "", `Comma-separated use cases the diagrams are restricted to, "Name#scenario" for one scenario (default: every use case)`)
+	perUseCase := flag.Bool("per-use-case", false, "Generate a C4 and a domain diagram per use case instead of the whole-model set")
+	filterExpr := flag.String("filter", "", `Filter expression the diagrams are narrowed with, e.g. 'owner=team-payments usecase="Money Transfer"' (default: whole model)`)
 
 	flag.Parse()
 
 	if *inputFile == "" || *outputDir == "" {
-		fmt.Println("Usage: craft -input <craft-file> -output <output-dir>")
+		fmt.Println("Usage: craft [-env <name>] [-tag <tags>] [-filter <expression>] [-use-case <names>] [-per-use-case] -input <craft-file> -output <output-dir>")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -27,8 +42,19 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create processor: %v", err)
 	}
+	f, err := parseFilter(*filterExpr, *tags)
+	if err != nil {
+		log.Fatal(err)
+	}
+	proc.SetEnvironment(*env)
+	proc.SetFilter(f)
+	proc.SetUseCases(useCaseSelections(*useCases))
 
-	if err := proc.ProcessFile(*inputFile, *outputDir); err != nil {
+	process := proc.ProcessFile
+	if *perUseCase {
+		process = proc.ProcessFilePerUseCase
+	}
+	if err := process(*inputFile, *outputDir); err != nil {
 		log.Fatalf("Failed to process file: %v", err)
 	}
 
@@ -39,6 +65,38 @@ func main() {
 	fmt.Println("Successfully generated architecture diagrams in:", *outputDir)
 }
 
+// runSubcommand dispatches "craft <command> ..." invocations
+func runSubcommand(name string, args []string) error {
+	switch name {
+	case "export":
+		return runExport(args)
+	case "import":
+		return runImport(args)
+	case "fmt":
+		return runFmt(args)
+	case "diff":
+		return runDiff(args)
+	case "history":
+		return runHistory(args)
+	case "lint":
+		return runLint(args)
+	case "analyze":
+		return runAnalyze(args)
+	case "boundaries":
+		return runBoundaries(args)
+	case "impact":
+		return runImpact(args)
+	case "trace":
+		return runTrace(args)
+	case "simulate":
+		return runSimulate(args)
+	case "report":
+		return runReport(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
 func generateDiagrams(outputDir string) error {
 	plantumlFiles, err := filepath.Glob(filepath.Join(outputDir, "*.puml"))
 	if err != nil {
@@ -73,3 +131,33 @@ func runCommand(name string, args ...string) error {
 	}
 	return nil
 }
+
+// parseFilter reads a -filter expression, adding the tags of a -tag flag as tag conditions
+func parseFilter(expression, tags string) (*filter.Filter, error) {
+	f, err := filter.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %v", err)
+	}
+	f.Tags = append(f.Tags, splitList(tags)...)
+	return f, nil
+}
+
+// useCaseSelections reads a -use-case flag value
+func useCaseSelections(value string) []parser.UseCaseSelection {
+	selections := make([]parser.UseCaseSelection, 0)
+	for _, item := range splitList(value) {
+		selections = append(selections, parser.ParseUseCaseSelection(item))
+	}
+	return selections
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tcarcao/craft/internal/processor"
+)
+
+// runReport dispatches "craft report <kind> ..." invocations
+func runReport(args []string) error {
+	if len(args) == 0 {
+		fmt.Println("Usage: craft report crud [-env <name>] [-format markdown|csv|html] [-output <file>] <file>")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "crud":
+		return runCRUDReport(args[1:])
+	default:
+		return fmt.Errorf("unknown report %q", args[0])
+	}
+}
+
+// runCRUDReport handles "craft report crud [-env <name>] [-format markdown|csv|html] [-output <file>] <file>"
+func runCRUDReport(args []string) error {
+	flags := flag.NewFlagSet("report crud", flag.ExitOnError)
+	format := flags.String("format", "markdown", "Output format: markdown, csv or html")
+	output := flags.String("output", "", "Write the report to a file instead of standard output")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: craft report crud [-env <name>] [-format markdown|csv|html] [-output <file>] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	report, err := proc.CRUDReport(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to build report: %v", err)
+	}
+
+	var content []byte
+	switch *format {
+	case "markdown":
+		content = []byte(report.Markdown())
+	case "csv":
+		if content, err = report.CSV(); err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+	case "html":
+		title := fmt.Sprintf("Data access: %s", filepath.Base(flags.Arg(0)))
+		if content, err = report.HTML(title); err != nil {
+			return fmt.Errorf("failed to render report: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported report format %q", *format)
+	}
+
+	if *output != "" {
+		if err := os.WriteFile(*output, content, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+		return nil
+	}
+	os.Stdout.Write(content)
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tcarcao/craft/internal/processor"
+	"github.com/tcarcao/craft/internal/simulation"
+)
+
+// runSimulate handles "craft simulate [-env <name>] [-format text|json|html] [-output <file>] [-percentile p99] [-fail-on-slo] <file>"
+func runSimulate(args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format: text, json or html")
+	output := flags.String("output", "", "Write the report to a file instead of standard output")
+	percentile := flags.String("percentile", simulation.DefaultOptions().Percentile, "Latency modifier to simulate with, e.g. p50 or p99")
+	failOnSLO := flags.Bool("fail-on-slo", false, "Exit with status 1 when a use case exceeds its SLO")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: craft simulate [-env <name>] [-format text|json|html] [-output <file>] [-percentile p99] [-fail-on-slo] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	proc.SetEnvironment(*env)
+
+	report, err := proc.SimulateFile(flags.Arg(0), simulation.Options{Percentile: *percentile})
+	if err != nil {
+		return fmt.Errorf("failed to simulate file: %v", err)
+	}
+
+	var content []byte
+	switch *format {
+	case "text":
+		content = []byte(report.Text())
+	case "json":
+		if content, err = report.JSON(); err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		content = append(content, '\n')
+	case "html":
+		title := fmt.Sprintf("Latency simulation: %s", filepath.Base(flags.Arg(0)))
+		if content, err = report.HTML(title); err != nil {
+			return fmt.Errorf("failed to render report: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported simulate format %q", *format)
+	}
+
+	if *output != "" {
+		if err := os.WriteFile(*output, content, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %v", err)
+		}
+	} else {
+		os.Stdout.Write(content)
+	}
+
+	if *failOnSLO {
+		exceeded := report.Exceeded()
+		for _, useCase := range exceeded {
+			fmt.Fprintf(os.Stderr, "SLO exceeded: %s takes %gms, budget %gms\n", useCase.Name, useCase.Critical, useCase.SLO)
+		}
+		if len(exceeded) > 0 {
+			os.Exit(1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tcarcao/craft/internal/processor"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+// runTrace handles "craft trace [-env <name>] [-filter <expression>] -use-case <name> [-scenario <n>] [-format text|json|diagram] [-output <file>] <file>"
+func runTrace(args []string) error {
+	flags := flag.NewFlagSet("trace", flag.ExitOnError)
+	useCase := flags.String("use-case", "", "Use case the trace starts from")
+	scenario := flags.Int("scenario", -1, "Index of the scenario to start from (default: the first externally triggered one)")
+	format := flags.String("format", "text", "Output format: text, json or diagram")
+	output := flags.String("output", "", "Diagram file for diagram output (default: trace.<image>)")
+	imageFormat := flags.String("image", "png", "Diagram image format: png, svg, pdf or puml")
+	env := flags.String("env", "", "Environment to resolve the model for (default: top-level model)")
+	filterExpr := flags.String("filter", "", "Filter expression the traced model is narrowed with, e.g. actor=Customer (default: whole model)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *useCase == "" {
+		fmt.Println("Usage: craft trace [-env <name>] [-filter <expression>] -use-case <name> [-scenario <n>] [-format text|json|diagram] [-output <file>] <file>")
+		flags.PrintDefaults()
+		os.Exit(1)
+	}
+
+	proc, err := processor.New()
+	if err != nil {
+		return fmt.Errorf("failed to create processor: %v", err)
+	}
+	f, err := parseFilter(*filterExpr, "")
+	if err != nil {
+		return err
+	}
+	proc.SetEnvironment(*env)
+	proc.SetFilter(f)
+
+	t, err := proc.TraceFile(flags.Arg(0), *useCase, *scenario)
+	if err != nil {
+		return fmt.Errorf("failed to trace use case: %v", err)
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(t.Text())
+	case "json":
+		content, err := t.JSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode trace: %v", err)
+		}
+		fmt.Println(string(content))
+	case "diagram":
+		outputPath := *output
+		if outputPath == "" {
+			outputPath = "trace." + *imageFormat
+		}
+		if err := proc.GenerateTraceDiagram(t, outputPath, visualizer.SupportedFormat(*imageFormat)); err != nil {
+			return err
+		}
+		fmt.Println("Successfully generated trace diagram:", outputPath)
+	default:
+		return fmt.Errorf("unsupported trace format %q", *format)
+	}
+
+	return nil
+}
@@ -11,7 +11,11 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/tcarcao/craft/internal/filter"
+	"github.com/tcarcao/craft/internal/impact"
 	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/saga"
+	"github.com/tcarcao/craft/internal/trace"
 	"github.com/tcarcao/craft/internal/visualizer"
 )
 
@@ -157,23 +161,51 @@ func (s *Server) respondWithError(w http.ResponseWriter, err error, input string
 
 // Domain-specific preview request
 type DomainPreviewRequest struct {
-	DSL        string `json:"dsl"`
-	DomainMode string `json:"domainMode,omitempty"` // detailed, architecture
+	DSL        string                    `json:"dsl"`
+	DomainMode string                    `json:"domainMode,omitempty"` // detailed, architecture, context_map, exposure
+	Env        string                    `json:"env,omitempty"`        // environment the model is resolved for, top-level model if empty
+	Tags       []string                  `json:"tags,omitempty"`       // tags the model is narrowed to, whole model if empty
+	Filter     string                    `json:"filter,omitempty"`     // filter expression the model is narrowed with, e.g. owner=team-payments
+	UseCases   []parser.UseCaseSelection `json:"useCases,omitempty"`   // use cases and scenarios the diagram is restricted to, all if empty
 }
 
 // C4-specific preview request
 type C4PreviewRequest struct {
-	DSL            string     `json:"dsl"`
-	FocusInfo      *FocusInfo `json:"focusInfo,omitempty"`
-	BoundariesMode string     `json:"boundariesMode,omitempty"`
-	ShowDatabases  *bool      `json:"showDatabases,omitempty"`
+	DSL            string                    `json:"dsl"`
+	FocusInfo      *FocusInfo                `json:"focusInfo,omitempty"`
+	BoundariesMode string                    `json:"boundariesMode,omitempty"`
+	ShowDatabases  *bool                     `json:"showDatabases,omitempty"`
+	Level          string                    `json:"level,omitempty"`    // container (default) or deployment
+	Arch           string                    `json:"arch,omitempty"`     // arch block drawn at deployment level, first one if empty
+	Env            string                    `json:"env,omitempty"`      // environment the model is resolved for, top-level model if empty
+	Tags           []string                  `json:"tags,omitempty"`     // tags the model is narrowed to, whole model if empty
+	Filter         string                    `json:"filter,omitempty"`   // filter expression the model is narrowed with, e.g. owner=team-payments
+	UseCases       []parser.UseCaseSelection `json:"useCases,omitempty"` // use cases and scenarios the diagram is restricted to, all if empty
 }
 
 type FocusInfo struct {
 	FocusedServiceNames   []string `json:"focusedServiceNames"`
 	FocusedSubDomainNames []string `json:"focusedSubDomainNames"`
+	FocusedDomainNames    []string `json:"focusedDomainNames"` // Parent domains, standing for all their subdomains
 	HasFocusedServices    bool     `json:"hasFocusedServices"`
 	HasFocusedSubDomains  bool     `json:"hasFocusedSubDomains"`
+	HasFocusedDomains     bool     `json:"hasFocusedDomains"`
+}
+
+// hasFocus reports whether any focus level is selected
+func (f *FocusInfo) hasFocus() bool {
+	return f != nil && (f.HasFocusedServices || f.HasFocusedSubDomains || f.HasFocusedDomains)
+}
+
+// subDomainNames returns the focused subdomains plus every subdomain of the focused parent domains
+func (f *FocusInfo) subDomainNames(model *parser.DSLModel) []string {
+	names := append([]string(nil), f.FocusedSubDomainNames...)
+	if f.HasFocusedDomains {
+		for _, domain := range f.FocusedDomainNames {
+			names = append(names, model.SubDomainsOf(domain)...)
+		}
+	}
+	return names
 }
 
 type PreviewResponse struct {
@@ -182,22 +214,78 @@ type PreviewResponse struct {
 	Data    string `json:"data,omitempty"` // base64 encoded diagram
 }
 
+// Impact analysis request
+type ImpactRequest struct {
+	DSL    string `json:"dsl"`
+	Domain string `json:"domain"`
+	Env    string `json:"env,omitempty"`    // environment the model is resolved for, top-level model if empty
+	Filter string `json:"filter,omitempty"` // filter expression the model is narrowed with, e.g. owner=team-payments
+}
+
+type ImpactResponse struct {
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Report  *impact.Report `json:"report,omitempty"`
+	Data    string         `json:"data,omitempty"` // base64 encoded C4 diagram of the blast radius
+}
+
+// Event trace request; Scenario defaults to the use case's first externally triggered scenario
+type TraceRequest struct {
+	DSL      string `json:"dsl"`
+	UseCase  string `json:"useCase"`
+	Scenario *int   `json:"scenario,omitempty"`
+	Env      string `json:"env,omitempty"`    // environment the model is resolved for, top-level model if empty
+	Filter   string `json:"filter,omitempty"` // filter expression the model is narrowed with, e.g. actor=Customer
+}
+
+type TraceResponse struct {
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+	Trace   *trace.Trace `json:"trace,omitempty"`
+	Data    string       `json:"data,omitempty"` // base64 encoded sequence diagram of the trace
+}
+
+// Saga diagram request; Saga defaults to the model's first saga
+type SagaRequest struct {
+	DSL  string `json:"dsl"`
+	Saga string `json:"saga,omitempty"`
+	Env  string `json:"env,omitempty"` // environment the model is resolved for, top-level model if empty
+}
+
+type SagaResponse struct {
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+	Saga    *parser.Saga `json:"saga,omitempty"`
+	Issues  []saga.Issue `json:"issues,omitempty"` // steps changing state without a compensation
+	Data    string       `json:"data,omitempty"`   // base64 encoded saga diagram
+}
+
 // Domain-specific download request
 type DomainDownloadRequest struct {
-	DSL        string `json:"dsl"`
-	DomainMode string `json:"domainMode,omitempty"` // detailed, architecture
-	Format     string `json:"format"`               // png, svg, pdf, puml
-	Filename   string `json:"filename,omitempty"`
+	DSL        string                    `json:"dsl"`
+	DomainMode string                    `json:"domainMode,omitempty"` // detailed, architecture, context_map, exposure
+	Format     string                    `json:"format"`               // png, svg, pdf, puml
+	Filename   string                    `json:"filename,omitempty"`
+	Env        string                    `json:"env,omitempty"`      // environment the model is resolved for, top-level model if empty
+	Tags       []string                  `json:"tags,omitempty"`     // tags the model is narrowed to, whole model if empty
+	Filter     string                    `json:"filter,omitempty"`   // filter expression the model is narrowed with, e.g. owner=team-payments
+	UseCases   []parser.UseCaseSelection `json:"useCases,omitempty"` // use cases and scenarios the diagram is restricted to, all if empty
 }
 
 // C4-specific download request
 type C4DownloadRequest struct {
-	DSL            string     `json:"dsl"`
-	FocusInfo      *FocusInfo `json:"focusInfo,omitempty"`
-	BoundariesMode string     `json:"boundariesMode,omitempty"`
-	ShowDatabases  *bool      `json:"showDatabases,omitempty"`
-	Format         string     `json:"format"` // png, svg, pdf, puml
-	Filename       string     `json:"filename,omitempty"`
+	DSL            string                    `json:"dsl"`
+	FocusInfo      *FocusInfo                `json:"focusInfo,omitempty"`
+	BoundariesMode string                    `json:"boundariesMode,omitempty"`
+	ShowDatabases  *bool                     `json:"showDatabases,omitempty"`
+	Level          string                    `json:"level,omitempty"` // container (default) or deployment
+	Arch           string                    `json:"arch,omitempty"`  // arch block drawn at deployment level, first one if empty
+	Format         string                    `json:"format"`          // png, svg, pdf, puml
+	Filename       string                    `json:"filename,omitempty"`
+	Env            string                    `json:"env,omitempty"`      // environment the model is resolved for, top-level model if empty
+	Tags           []string                  `json:"tags,omitempty"`     // tags the model is narrowed to, whole model if empty
+	Filter         string                    `json:"filter,omitempty"`   // filter expression the model is narrowed with, e.g. owner=team-payments
+	UseCases       []parser.UseCaseSelection `json:"useCases,omitempty"` // use cases and scenarios the diagram is restricted to, all if empty
 }
 
 func (s *Server) handlePreviewDomain() http.HandlerFunc {
@@ -217,10 +305,32 @@ func (s *Server) handlePreviewDomain() http.HandlerFunc {
 			return
 		}
 
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		model = model.WithTags(req.Tags...)
+		model, err = applyFilter(model, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+		model, err = model.WithUseCases(req.UseCases...)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Use case error: %v", err))
+			return
+		}
+
 		// Parse domain mode, default to "detailed" if not provided or invalid
 		domainMode := visualizer.DomainModeDetailed
-		if req.DomainMode == string(visualizer.DomainModeArchitecture) {
+		switch req.DomainMode {
+		case string(visualizer.DomainModeArchitecture):
 			domainMode = visualizer.DomainModeArchitecture
+		case string(visualizer.DomainModeContextMap):
+			domainMode = visualizer.DomainModeContextMap
+		case string(visualizer.DomainModeExposure):
+			domainMode = visualizer.DomainModeExposure
 		}
 
 		// Generate Model diagram with mode
@@ -258,8 +368,48 @@ func (s *Server) handlePreviewC4() http.HandlerFunc {
 			return
 		}
 
+		arch, err = arch.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		arch = arch.WithTags(req.Tags...)
+		arch, err = applyFilter(arch, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+		arch, err = arch.WithUseCases(req.UseCases...)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Use case error: %v", err))
+			return
+		}
+
 		fmt.Println(req.FocusInfo)
 
+		// Deployment level draws an arch block instead of the container view
+		level, architecture := c4Level(r, req.Level, req.Arch)
+		if level == string(visualizer.C4Deployment) {
+			if architecture != "" && visualizer.FindArchitecture(arch, architecture) == nil {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("unknown architecture %s", architecture))
+				return
+			}
+			diagram, _, err := s.viz.GenerateC4DeploymentWithFormat(arch, architecture, visualizer.FormatPNG)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Diagram generation failed: %v", err))
+				return
+			}
+
+			response := PreviewResponse{
+				Success: true,
+				Data:    base64.StdEncoding.EncodeToString(diagram),
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
 		// Parse boundaries mode, default to "boundaries" if not provided or invalid
 		boundariesMode := visualizer.C4ModeBoundaries
 		if req.BoundariesMode == string(visualizer.C4ModeTransparent) {
@@ -274,8 +424,8 @@ func (s *Server) handlePreviewC4() http.HandlerFunc {
 
 		// Generate C4 diagram with focus information, boundaries mode, and database visibility
 		var diagram []byte
-		if req.FocusInfo != nil && (req.FocusInfo.HasFocusedServices || req.FocusInfo.HasFocusedSubDomains) {
-			diagram, err = s.viz.GenerateC4WithFocusAndSubDomains(arch, req.FocusInfo.FocusedServiceNames, req.FocusInfo.FocusedSubDomainNames, boundariesMode, showDatabases)
+		if req.FocusInfo.hasFocus() {
+			diagram, err = s.viz.GenerateC4WithFocusAndSubDomains(arch, req.FocusInfo.FocusedServiceNames, req.FocusInfo.subDomainNames(arch), boundariesMode, showDatabases)
 		} else {
 			diagram, err = s.viz.GenerateC4(arch, boundariesMode, showDatabases)
 		}
@@ -296,6 +446,183 @@ func (s *Server) handlePreviewC4() http.HandlerFunc {
 	}
 }
 
+func (s *Server) handleImpact() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ImpactRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request format")
+			return
+		}
+
+		// Parse DSL
+		p := parser.NewParser()
+
+		model, err := p.ParseString(req.DSL)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parse error: %v", err))
+			return
+		}
+
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		model, err = applyFilter(model, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+
+		report, err := impact.Analyze(model, req.Domain)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Impact analysis failed: %v", err))
+			return
+		}
+
+		// Generate C4 diagram focused on the affected services, with the blast radius highlighted
+		diagram, _, err := s.viz.GenerateC4WithFocusHighlightsAndFormat(model, report.Services, report.Highlights(), visualizer.C4ModeBoundaries, true, visualizer.FormatPNG)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Diagram generation failed: %v", err))
+			return
+		}
+
+		// Encode and respond
+		response := ImpactResponse{
+			Success: true,
+			Report:  report,
+			Data:    base64.StdEncoding.EncodeToString(diagram),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (s *Server) handleTrace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req TraceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request format")
+			return
+		}
+
+		// Parse DSL
+		p := parser.NewParser()
+
+		model, err := p.ParseString(req.DSL)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parse error: %v", err))
+			return
+		}
+
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		model, err = applyFilter(model, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+
+		scenario := -1
+		if req.Scenario != nil {
+			scenario = *req.Scenario
+		}
+
+		t, err := trace.Follow(model, req.UseCase, scenario)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Trace failed: %v", err))
+			return
+		}
+
+		diagram, _, err := s.viz.GenerateTraceDiagramWithFormat(t, visualizer.FormatPNG)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Diagram generation failed: %v", err))
+			return
+		}
+
+		// Encode and respond
+		response := TraceResponse{
+			Success: true,
+			Trace:   t,
+			Data:    base64.StdEncoding.EncodeToString(diagram),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (s *Server) handleSaga() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req SagaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request format")
+			return
+		}
+
+		// Parse DSL
+		p := parser.NewParser()
+
+		model, err := p.ParseString(req.DSL)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Parse error: %v", err))
+			return
+		}
+
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+
+		selected, err := findSaga(model, req.Saga)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Steps missing a compensation, as the linter reports them
+		issues := saga.Validate(&parser.DSLModel{Sagas: []parser.Saga{*selected}})
+
+		diagram, _, err := s.viz.GenerateSagaDiagramWithFormat(*selected, visualizer.FormatPNG)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Diagram generation failed: %v", err))
+			return
+		}
+
+		// Encode and respond
+		response := SagaResponse{
+			Success: true,
+			Saga:    selected,
+			Issues:  issues,
+			Data:    base64.StdEncoding.EncodeToString(diagram),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// findSaga returns the saga of the model with the given name, or its first saga when name is empty
+func findSaga(model *parser.DSLModel, name string) (*parser.Saga, error) {
+	if len(model.Sagas) == 0 {
+		return nil, fmt.Errorf("the model declares no saga")
+	}
+	if name == "" {
+		return &model.Sagas[0], nil
+	}
+	for i := range model.Sagas {
+		if model.Sagas[i].Name == name {
+			return &model.Sagas[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown saga %s", name)
+}
+
 func respondWithError(w http.ResponseWriter, code int, message string) {
 	log.Printf("[%d] %s", code, message)
 	response := PreviewResponse{
@@ -323,6 +650,23 @@ func (s *Server) handleDownloadDomainDiagram() http.HandlerFunc {
 			return
 		}
 
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		model = model.WithTags(req.Tags...)
+		model, err = applyFilter(model, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+		model, err = model.WithUseCases(req.UseCases...)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Use case error: %v", err))
+			return
+		}
+
 		// Convert format string to SupportedFormat
 		var format visualizer.SupportedFormat
 		switch req.Format {
@@ -340,8 +684,13 @@ func (s *Server) handleDownloadDomainDiagram() http.HandlerFunc {
 
 		// Parse domain mode, default to "detailed" if not provided or invalid
 		domainMode := visualizer.DomainModeDetailed
-		if req.DomainMode == string(visualizer.DomainModeArchitecture) {
+		switch req.DomainMode {
+		case string(visualizer.DomainModeArchitecture):
 			domainMode = visualizer.DomainModeArchitecture
+		case string(visualizer.DomainModeContextMap):
+			domainMode = visualizer.DomainModeContextMap
+		case string(visualizer.DomainModeExposure):
+			domainMode = visualizer.DomainModeExposure
 		}
 
 		diagram, contentType, err := s.viz.GenerateDomainDiagramWithModeAndFormat(model, domainMode, format)
@@ -352,9 +701,14 @@ func (s *Server) handleDownloadDomainDiagram() http.HandlerFunc {
 
 		// Set filename based on mode
 		var defaultFilename string
-		if domainMode == visualizer.DomainModeArchitecture {
+		switch domainMode {
+		case visualizer.DomainModeArchitecture:
 			defaultFilename = "architecture-diagram"
-		} else {
+		case visualizer.DomainModeContextMap:
+			defaultFilename = "context-map"
+		case visualizer.DomainModeExposure:
+			defaultFilename = "exposure-diagram"
+		default:
 			defaultFilename = "domain-diagram"
 		}
 
@@ -391,6 +745,23 @@ func (s *Server) handleDownloadC4Diagram() http.HandlerFunc {
 			return
 		}
 
+		model, err = model.ForEnvironment(req.Env)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Environment error: %v", err))
+			return
+		}
+		model = model.WithTags(req.Tags...)
+		model, err = applyFilter(model, req.Filter)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Filter error: %v", err))
+			return
+		}
+		model, err = model.WithUseCases(req.UseCases...)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Use case error: %v", err))
+			return
+		}
+
 		// Convert format string to SupportedFormat
 		var format visualizer.SupportedFormat
 		switch req.Format {
@@ -421,8 +792,17 @@ func (s *Server) handleDownloadC4Diagram() http.HandlerFunc {
 		// Generate C4 diagram with focus and format
 		var diagram []byte
 		var contentType string
-		if req.FocusInfo != nil && (req.FocusInfo.HasFocusedServices || req.FocusInfo.HasFocusedSubDomains) {
-			diagram, contentType, err = s.viz.GenerateC4WithFocusSubDomainsAndFormat(model, req.FocusInfo.FocusedServiceNames, req.FocusInfo.FocusedSubDomainNames, boundariesMode, showDatabases, format)
+		defaultFilename := "c4-diagram"
+		level, architecture := c4Level(r, req.Level, req.Arch)
+		if level == string(visualizer.C4Deployment) {
+			if architecture != "" && visualizer.FindArchitecture(model, architecture) == nil {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("unknown architecture %s", architecture))
+				return
+			}
+			defaultFilename = "deployment-diagram"
+			diagram, contentType, err = s.viz.GenerateC4DeploymentWithFormat(model, architecture, format)
+		} else if req.FocusInfo.hasFocus() {
+			diagram, contentType, err = s.viz.GenerateC4WithFocusSubDomainsAndFormat(model, req.FocusInfo.FocusedServiceNames, req.FocusInfo.subDomainNames(model), boundariesMode, showDatabases, format)
 		} else {
 			diagram, contentType, err = s.viz.GenerateC4WithFormat(model, boundariesMode, showDatabases, format)
 		}
@@ -439,7 +819,7 @@ func (s *Server) handleDownloadC4Diagram() http.HandlerFunc {
 			if format == visualizer.FormatPUML {
 				extension = "puml"
 			}
-			filename = fmt.Sprintf("c4-diagram.%s", extension)
+			filename = fmt.Sprintf("%s.%s", defaultFilename, extension)
 		}
 
 		// Set response headers
@@ -449,6 +829,27 @@ func (s *Server) handleDownloadC4Diagram() http.HandlerFunc {
 	}
 }
 
+// applyFilter narrows a model with a filter expression, returning it unchanged when the expression is empty
+func applyFilter(model *parser.DSLModel, expression string) (*parser.DSLModel, error) {
+	f, err := filter.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return f.Apply(model)
+}
+
+// c4Level returns the requested C4 level and arch block, falling back to the level and arch query
+// parameters when the request body leaves them empty
+func c4Level(r *http.Request, level, architecture string) (string, string) {
+	if level == "" {
+		level = r.URL.Query().Get("level")
+	}
+	if architecture == "" {
+		architecture = r.URL.Query().Get("arch")
+	}
+	return level, architecture
+}
+
 func main() {
 	server, err := NewServer()
 	if err != nil {
@@ -465,6 +866,9 @@ func main() {
 
 	r.HandleFunc("/preview/domain", server.handlePreviewDomain()).Methods("POST")
 	r.HandleFunc("/preview/c4", server.handlePreviewC4()).Methods("POST")
+	r.HandleFunc("/impact", server.handleImpact()).Methods("POST")
+	r.HandleFunc("/trace", server.handleTrace()).Methods("POST")
+	r.HandleFunc("/saga", server.handleSaga()).Methods("POST")
 
 	r.HandleFunc("/download/domain", server.handleDownloadDomainDiagram()).Methods("POST")
 	r.HandleFunc("/download/c4", server.handleDownloadC4Diagram()).Methods("POST")
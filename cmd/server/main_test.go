@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+const shop = `domains {
+  Sales {
+    Orders
+    Carts
+  }
+}
+
+services {
+  ShopService {
+    domains: Orders, Carts
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Carts to load cart
+    Orders asks Billing to charge card
+}
+`
+
+func TestFocusInfo_FocusedDomainNames(t *testing.T) {
+	body, err := json.Marshal(map[string]any{
+		"dsl": shop,
+		"focusInfo": map[string]any{
+			"focusedDomainNames": []string{"Sales"},
+			"hasFocusedDomains":  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+
+	var req C4PreviewRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	model, err := parser.ParseDSLToModel(req.DSL)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	if !req.FocusInfo.hasFocus() {
+		t.Fatal("Expected a focused parent domain to count as focus")
+	}
+	names := req.FocusInfo.subDomainNames(model)
+	if !reflect.DeepEqual(names, []string{"Orders", "Carts"}) {
+		t.Errorf("Expected the subdomains of Sales, got %v", names)
+	}
+
+	diagram := visualizer.GenerateC4ContainerDiagramWithFocusAndSubDomains(model, visualizer.C4ModeBoundaries, req.FocusInfo.FocusedServiceNames, names, true)
+	for _, expected := range []string{
+		`System_Boundary(ShopService_boundary, "ShopService")`,
+		`Container(Orders, "Orders", "Application", "Orders domain logic")`,
+		`Container(Carts, "Carts", "Application", "Carts domain logic")`,
+		`Enterprise_Boundary(BillingService_boundary, "BillingService")`,
+	} {
+		if !strings.Contains(diagram, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, diagram)
+		}
+	}
+}
+
+func TestFocusInfo_DomainsNeedTheirFlag(t *testing.T) {
+	model, err := parser.ParseDSLToModel(shop)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	focus := &FocusInfo{FocusedSubDomainNames: []string{"Billing"}, HasFocusedSubDomains: true, FocusedDomainNames: []string{"Sales"}}
+	if names := focus.subDomainNames(model); !reflect.DeepEqual(names, []string{"Billing"}) {
+		t.Errorf("Expected parent domains to be ignored without hasFocusedDomains, got %v", names)
+	}
+
+	focus.HasFocusedDomains = true
+	if names := focus.subDomainNames(model); !reflect.DeepEqual(names, []string{"Billing", "Orders", "Carts"}) {
+		t.Errorf("Expected the focused subdomain and those of Sales, got %v", names)
+	}
+}
+
+func TestFindSaga(t *testing.T) {
+	model, err := parser.ParseDSLToModel(`saga "Checkout" {
+  step Orders reserves stock
+    compensate with Orders releases stock
+}
+
+saga "Refund" {
+  step Billing refunds card [retriable]
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	if found, err := findSaga(model, ""); err != nil || found.Name != "Checkout" {
+		t.Errorf("Expected the first saga by default, got %+v, %v", found, err)
+	}
+	if found, err := findSaga(model, "Refund"); err != nil || found.Name != "Refund" {
+		t.Errorf("Expected the Refund saga, got %+v, %v", found, err)
+	}
+	if _, err := findSaga(model, "Returns"); err == nil || err.Error() != "unknown saga Returns" {
+		t.Errorf("Expected an unknown saga error, got %v", err)
+	}
+	if _, err := findSaga(&parser.DSLModel{}, ""); err == nil {
+		t.Error("Expected an error for a model without sagas")
+	}
+}
@@ -47,7 +47,11 @@ export default defineConfig({
           { text: 'Services', link: '/language/services' },
           { text: 'Use Cases', link: '/language/use-cases' },
           { text: 'Architecture', link: '/language/architecture' },
-          { text: 'Exposures', link: '/language/exposures' }
+          { text: 'Exposures', link: '/language/exposures' },
+          { text: 'Environments', link: '/language/environments' },
+          { text: 'Rules', link: '/language/rules' },
+          { text: 'Sagas', link: '/language/sagas' },
+          { text: 'Metadata', link: '/language/metadata' }
         ]
       },
       {
@@ -8,6 +8,7 @@ toolchain go1.23.1
 require (
 	github.com/antlr4-go/antlr/v4 v4.13.1
 	github.com/gorilla/mux v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/exp v0.0.0-20241108190413-2d47ceb2692f // indirect
@@ -0,0 +1,403 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Options tune what the analysis reports
+type Options struct {
+	ChattyThreshold int // Distinct sync operations from one service to another before the pair is chatty
+}
+
+// DefaultOptions returns the options used by the command line
+func DefaultOptions() Options {
+	return Options{ChattyThreshold: 3}
+}
+
+// Coupling holds the coupling metrics of a service or domain.
+// Afferent counts the elements depending on it, Efferent the elements it depends on, through
+// sync calls or consumed events; Instability is Efferent / (Afferent + Efferent).
+type Coupling struct {
+	Name        string  `json:"name"`
+	Afferent    int     `json:"afferent"`
+	Efferent    int     `json:"efferent"`
+	Instability float64 `json:"instability"`
+}
+
+// ChattyPair is a service calling another through many distinct sync operations
+type ChattyPair struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Calls      int      `json:"calls"`
+	Operations []string `json:"operations"`
+}
+
+// CallChain is the longest chain of sync calls in a use case
+type CallChain struct {
+	UseCase string   `json:"use_case"`
+	Domains []string `json:"domains"`
+	Hops    int      `json:"hops"`
+}
+
+// ExternalDependency is a declared external system and the services and domains calling it.
+// Calls to external systems do not count towards coupling.
+type ExternalDependency struct {
+	Name     string   `json:"name"`
+	Protocol string   `json:"protocol,omitempty"`
+	Owner    string   `json:"owner,omitempty"`
+	Services []string `json:"services"`
+	Domains  []string `json:"domains"`
+	Calls    int      `json:"calls"` // Distinct operations called
+}
+
+// Report is the result of analysing a model
+type Report struct {
+	Cycles          [][]string           `json:"cycles"` // Services depending on each other through sync calls
+	ServiceCoupling []Coupling           `json:"service_coupling"`
+	DomainCoupling  []Coupling           `json:"domain_coupling"`
+	ChattyPairs     []ChattyPair         `json:"chatty_pairs"`
+	LongestChains   []CallChain          `json:"longest_chains"`
+	External        []ExternalDependency `json:"external_dependencies"`
+}
+
+// Analyze computes the dependency report of a model
+func Analyze(model *parser.DSLModel, options Options) *Report {
+	modelGraph := graph.Build(model)
+
+	report := &Report{
+		Cycles:        serviceCycles(modelGraph),
+		ChattyPairs:   chattyPairs(modelGraph, options.ChattyThreshold),
+		LongestChains: longestChains(model, modelGraph),
+		External:      externalDependencies(model, modelGraph),
+	}
+
+	domainDependencies := dependencies(modelGraph)
+	report.DomainCoupling = coupling(domainNames(modelGraph), domainDependencies)
+
+	serviceDependencies := make(map[[2]string]bool)
+	for pair := range domainDependencies {
+		from, to := modelGraph.ServiceOf(pair[0]), modelGraph.ServiceOf(pair[1])
+		if from != "" && to != "" && from != to {
+			serviceDependencies[[2]string{from, to}] = true
+		}
+	}
+	report.ServiceCoupling = coupling(nodeNames(modelGraph, graph.NodeService), serviceDependencies)
+
+	return report
+}
+
+// serviceCycles returns the groups of services with a sync dependency cycle between them
+func serviceCycles(modelGraph *graph.Graph) [][]string {
+	cycles := make([][]string, 0)
+	for _, component := range modelGraph.ServiceView(graph.EdgeSync).Cycles(graph.EdgeSync) {
+		services := make([]string, 0, len(component))
+		for _, id := range component {
+			services = append(services, id.Name())
+		}
+		cycles = append(cycles, services)
+	}
+	return cycles
+}
+
+// dependencies returns the domain dependencies: sync callers on callees and event consumers on publishers
+func dependencies(modelGraph *graph.Graph) map[[2]string]bool {
+	pairs := make(map[[2]string]bool)
+
+	for _, call := range modelGraph.Edges(graph.EdgeSync) {
+		if call.From != call.To && call.From.Kind() == graph.NodeDomain && call.To.Kind() == graph.NodeDomain {
+			pairs[[2]string{call.From.Name(), call.To.Name()}] = true
+		}
+	}
+
+	for _, consume := range modelGraph.Edges(graph.EdgeConsume) {
+		consumer := consume.To.Name()
+		for _, publisher := range modelGraph.Publishers(consume.From.Name()) {
+			if publisher != consumer {
+				pairs[[2]string{consumer, publisher}] = true
+			}
+		}
+	}
+
+	return pairs
+}
+
+// externalDependencies lists every declared external system with its callers, in declaration order
+func externalDependencies(model *parser.DSLModel, modelGraph *graph.Graph) []ExternalDependency {
+	dependencies := make([]ExternalDependency, 0, len(model.ExternalSystems))
+	for _, system := range model.ExternalSystems {
+		dependency := ExternalDependency{
+			Name:     system.Name,
+			Protocol: system.Protocol,
+			Owner:    system.Owner,
+			Services: make([]string, 0),
+			Domains:  make([]string, 0),
+		}
+
+		operations := make([]string, 0)
+		for _, call := range modelGraph.In(graph.ID(graph.NodeExternal, system.Name), graph.EdgeSync) {
+			domain := call.From.Name()
+			if !slices.Contains(dependency.Domains, domain) {
+				dependency.Domains = append(dependency.Domains, domain)
+			}
+			if service := modelGraph.ServiceOf(domain); service != "" && !slices.Contains(dependency.Services, service) {
+				dependency.Services = append(dependency.Services, service)
+			}
+			if operation := domain + ": " + call.Label; !slices.Contains(operations, operation) {
+				operations = append(operations, operation)
+			}
+		}
+		sort.Strings(dependency.Services)
+		sort.Strings(dependency.Domains)
+		dependency.Calls = len(operations)
+
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
+
+// coupling computes the metrics of each named element from dependency pairs
+func coupling(names []string, pairs map[[2]string]bool) []Coupling {
+	afferent := make(map[string]int)
+	efferent := make(map[string]int)
+	for pair := range pairs {
+		efferent[pair[0]]++
+		afferent[pair[1]]++
+	}
+
+	metrics := make([]Coupling, 0, len(names))
+	for _, name := range names {
+		metric := Coupling{Name: name, Afferent: afferent[name], Efferent: efferent[name]}
+		if total := metric.Afferent + metric.Efferent; total > 0 {
+			metric.Instability = float64(metric.Efferent) / float64(total)
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// chattyPairs reports service pairs with at least threshold distinct sync operations, busiest first
+func chattyPairs(modelGraph *graph.Graph, threshold int) []ChattyPair {
+	operations := make(map[[2]string][]string)
+	for _, call := range modelGraph.Edges(graph.EdgeSync) {
+		from, to := modelGraph.ServiceOf(call.From.Name()), modelGraph.ServiceOf(call.To.Name())
+		if from == "" || to == "" || from == to {
+			continue
+		}
+
+		key := [2]string{from, to}
+		operation := fmt.Sprintf("%s -> %s: %s", call.From.Name(), call.To.Name(), call.Label)
+		if !slices.Contains(operations[key], operation) {
+			operations[key] = append(operations[key], operation)
+		}
+	}
+
+	pairs := make([]ChattyPair, 0)
+	for key, ops := range operations {
+		if threshold > 0 && len(ops) >= threshold {
+			sort.Strings(ops)
+			pairs = append(pairs, ChattyPair{From: key[0], To: key[1], Calls: len(ops), Operations: ops})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Calls != pairs[j].Calls {
+			return pairs[i].Calls > pairs[j].Calls
+		}
+		if pairs[i].From != pairs[j].From {
+			return pairs[i].From < pairs[j].From
+		}
+		return pairs[i].To < pairs[j].To
+	})
+	return pairs
+}
+
+// longestChains names the longest chain of sync calls of each use case, in model order. Chains are
+// followed within a scenario only, since an event ends the synchronous flow that published it.
+func longestChains(model *parser.DSLModel, modelGraph *graph.Graph) []CallChain {
+	chains := make([]CallChain, 0)
+	for _, useCase := range model.UseCases {
+		var path []graph.NodeID
+		for i := range useCase.Scenarios {
+			calls := modelGraph.Subgraph(func(edge graph.Edge) bool {
+				return edge.Kind == graph.EdgeSync && edge.UseCase == useCase.Name && edge.Scenario == i
+			})
+			if chain := scenarioChain(calls.Edges()); len(chain) > len(path) {
+				path = chain
+			}
+		}
+		if path == nil {
+			continue
+		}
+
+		domains := make([]string, 0, len(path))
+		for _, id := range path {
+			domains = append(domains, id.Name())
+		}
+		chains = append(chains, CallChain{UseCase: useCase.Name, Domains: domains, Hops: len(path) - 1})
+	}
+	return chains
+}
+
+// scenarioChain returns the longest chain of the sync calls of one scenario, taken in caller order: a
+// call extends the chains reaching its caller through earlier calls, and never closes a loop
+func scenarioChain(calls []graph.Edge) []graph.NodeID {
+	var longest []graph.NodeID
+	reached := make(map[graph.NodeID][]graph.NodeID)
+	for _, call := range calls {
+		chain, ok := reached[call.From]
+		if !ok {
+			chain = []graph.NodeID{call.From}
+		}
+		if slices.Contains(chain, call.To) {
+			continue
+		}
+
+		chain = append(slices.Clone(chain), call.To)
+		if len(chain) > len(reached[call.To]) {
+			reached[call.To] = chain
+		}
+		if len(chain) > len(longest) {
+			longest = chain
+		}
+	}
+	return longest
+}
+
+// Thresholds make a report fail; zero values disable a check
+type Thresholds struct {
+	FailOnCycles   bool
+	FailOnChatty   bool
+	MaxChainHops   int
+	MaxInstability float64 // Applies to services that others depend on
+}
+
+// Check returns a message per threshold the report breaches
+func (r *Report) Check(thresholds Thresholds) []string {
+	breaches := make([]string, 0)
+
+	if thresholds.FailOnCycles {
+		for _, cycle := range r.Cycles {
+			breaches = append(breaches, fmt.Sprintf("sync dependency cycle between %s", strings.Join(cycle, ", ")))
+		}
+	}
+
+	if thresholds.FailOnChatty {
+		for _, pair := range r.ChattyPairs {
+			breaches = append(breaches, fmt.Sprintf("%s makes %d distinct sync calls to %s", pair.From, pair.Calls, pair.To))
+		}
+	}
+
+	if thresholds.MaxChainHops > 0 {
+		for _, chain := range r.LongestChains {
+			if chain.Hops > thresholds.MaxChainHops {
+				breaches = append(breaches, fmt.Sprintf("use case '%s' chains %d sync calls (%s), limit is %d",
+					chain.UseCase, chain.Hops, strings.Join(chain.Domains, " -> "), thresholds.MaxChainHops))
+			}
+		}
+	}
+
+	if thresholds.MaxInstability > 0 {
+		for _, metric := range r.ServiceCoupling {
+			if metric.Afferent > 0 && metric.Instability > thresholds.MaxInstability {
+				breaches = append(breaches, fmt.Sprintf("service %s has instability %.2f with %d dependents, limit is %.2f",
+					metric.Name, metric.Instability, metric.Afferent, thresholds.MaxInstability))
+			}
+		}
+	}
+
+	return breaches
+}
+
+// JSON renders the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Table renders the report as plain text tables
+func (r *Report) Table() string {
+	var sb strings.Builder
+
+	sb.WriteString("Sync dependency cycles\n")
+	if len(r.Cycles) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, cycle := range r.Cycles {
+		sb.WriteString(fmt.Sprintf("  %s\n", strings.Join(cycle, " <-> ")))
+	}
+
+	sb.WriteString("\nService coupling\n")
+	writeCouplingTable(&sb, r.ServiceCoupling)
+
+	sb.WriteString("\nDomain coupling\n")
+	writeCouplingTable(&sb, r.DomainCoupling)
+
+	sb.WriteString("\nChatty service pairs\n")
+	if len(r.ChattyPairs) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, pair := range r.ChattyPairs {
+		sb.WriteString(fmt.Sprintf("  %s -> %s (%d calls)\n", pair.From, pair.To, pair.Calls))
+		for _, operation := range pair.Operations {
+			sb.WriteString(fmt.Sprintf("    %s\n", operation))
+		}
+	}
+
+	sb.WriteString("\nLongest sync call chains\n")
+	if len(r.LongestChains) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, chain := range r.LongestChains {
+		sb.WriteString(fmt.Sprintf("  %s (%d hops): %s\n", chain.UseCase, chain.Hops, strings.Join(chain.Domains, " -> ")))
+	}
+
+	sb.WriteString("\nExternal systems\n")
+	if len(r.External) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, dependency := range r.External {
+		callers := strings.Join(dependency.Services, ", ")
+		if callers == "" {
+			callers = "no service"
+		}
+		sb.WriteString(fmt.Sprintf("  %s (%d calls from %s)\n", dependency.Name, dependency.Calls, callers))
+	}
+
+	return sb.String()
+}
+
+func writeCouplingTable(sb *strings.Builder, metrics []Coupling) {
+	width := len("Name")
+	for _, metric := range metrics {
+		width = max(width, len(metric.Name))
+	}
+
+	sb.WriteString(fmt.Sprintf("  %-*s  %4s  %4s  %11s\n", width, "Name", "Ca", "Ce", "Instability"))
+	for _, metric := range metrics {
+		sb.WriteString(fmt.Sprintf("  %-*s  %4d  %4d  %11.2f\n", width, metric.Name, metric.Afferent, metric.Efferent, metric.Instability))
+	}
+}
+
+func domainNames(modelGraph *graph.Graph) []string {
+	names := make([]string, 0)
+	for _, node := range modelGraph.Nodes(graph.NodeDomain) {
+		// Domains only named by exposures are not part of the architecture
+		if len(node.UseCases) > 0 || modelGraph.ServiceOf(node.Name) != "" {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
+
+func nodeNames(modelGraph *graph.Graph, kind graph.NodeKind) []string {
+	names := make([]string, 0)
+	for _, node := range modelGraph.Nodes(kind) {
+		names = append(names, node.Name)
+	}
+	return names
+}
@@ -0,0 +1,288 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const storeServices = `services {
+  OrderService {
+    domains: Orders
+  }
+  BillingService {
+    domains: Billing, Invoices
+  }
+  ShippingService {
+    domains: Shipping
+  }
+}
+`
+
+const store = storeServices + `
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Billing to charge card
+    Orders asks Billing to reserve credit
+    Orders asks Invoices to draft invoice
+    Billing asks Shipping to quote delivery
+    Billing notifies "Payment Taken"
+
+  when Shipping listens "Payment Taken"
+    Shipping asks Orders to load address
+}
+`
+
+func parseModel(t *testing.T, dsl string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestAnalyze_Cycles(t *testing.T) {
+	report := Analyze(parseModel(t, store), DefaultOptions())
+
+	expected := [][]string{{"BillingService", "OrderService", "ShippingService"}}
+	if !reflect.DeepEqual(report.Cycles, expected) {
+		t.Errorf("Expected cycles %v, got %v", expected, report.Cycles)
+	}
+}
+
+func TestAnalyze_Coupling(t *testing.T) {
+	report := Analyze(parseModel(t, store), DefaultOptions())
+
+	// Orders <- Shipping, Orders -> Billing, Invoices
+	orders := findCoupling(t, report.DomainCoupling, "Orders")
+	if orders.Afferent != 1 || orders.Efferent != 2 {
+		t.Errorf("Unexpected Orders coupling: %+v", orders)
+	}
+
+	// Shipping consumes Billing's event and calls Orders: Ce 2, Ca 1 (Billing calls it)
+	shipping := findCoupling(t, report.ServiceCoupling, "ShippingService")
+	if shipping.Afferent != 1 || shipping.Efferent != 2 {
+		t.Errorf("Unexpected ShippingService coupling: %+v", shipping)
+	}
+	if shipping.Instability < 0.66 || shipping.Instability > 0.67 {
+		t.Errorf("Expected instability 2/3, got %f", shipping.Instability)
+	}
+}
+
+func TestAnalyze_ChattyPairsAndChains(t *testing.T) {
+	report := Analyze(parseModel(t, store), DefaultOptions())
+
+	if len(report.ChattyPairs) != 1 {
+		t.Fatalf("Expected 1 chatty pair, got %+v", report.ChattyPairs)
+	}
+	pair := report.ChattyPairs[0]
+	if pair.From != "OrderService" || pair.To != "BillingService" || pair.Calls != 3 {
+		t.Errorf("Unexpected chatty pair: %+v", pair)
+	}
+
+	if len(report.LongestChains) != 1 {
+		t.Fatalf("Expected 1 chain, got %+v", report.LongestChains)
+	}
+	chain := report.LongestChains[0]
+	// Shipping asks Orders in the listener scenario, after the event, so it does not extend the chain
+	if chain.Hops != 2 || !reflect.DeepEqual(chain.Domains, []string{"Orders", "Billing", "Shipping"}) {
+		t.Errorf("Unexpected chain: %+v", chain)
+	}
+}
+
+func TestAnalyze_ChainsFollowCallerOrder(t *testing.T) {
+	model := parseModel(t, storeServices+`
+use_case "Checkout" {
+  when Customer tracks parcel
+    Billing asks Shipping to quote delivery
+    Orders asks Billing to charge card
+}
+`)
+
+	report := Analyze(model, DefaultOptions())
+
+	// Billing calls Shipping before Orders calls Billing, so Orders -> Billing -> Shipping never happens
+	if len(report.LongestChains) != 1 || report.LongestChains[0].Hops != 1 {
+		t.Errorf("Expected single-hop chains, got %+v", report.LongestChains)
+	}
+}
+
+func TestAnalyze_ExternalSystems(t *testing.T) {
+	model := parseModel(t, store+`
+external_system Stripe {
+  protocol: https
+  owner: vendor
+}
+
+external_system SendGrid {}
+
+use_case "Capture" {
+  when Clerk closes day
+    Billing asks Stripe to capture
+    Invoices asks Stripe to capture
+    Billing asks Stripe to capture
+}
+`)
+
+	report := Analyze(model, DefaultOptions())
+
+	expected := []ExternalDependency{
+		{Name: "Stripe", Protocol: "https", Owner: "vendor", Services: []string{"BillingService"}, Domains: []string{"Billing", "Invoices"}, Calls: 2},
+		{Name: "SendGrid", Services: []string{}, Domains: []string{}},
+	}
+	if !reflect.DeepEqual(report.External, expected) {
+		t.Errorf("Expected external dependencies %+v, got %+v", expected, report.External)
+	}
+
+	// Calls to external systems are not domain dependencies
+	billing := findCoupling(t, report.DomainCoupling, "Billing")
+	if billing.Efferent != 1 {
+		t.Errorf("Expected Billing to depend on Shipping only, got %+v", billing)
+	}
+	for _, metric := range report.DomainCoupling {
+		if metric.Name == "Stripe" {
+			t.Errorf("Expected Stripe not to be counted as a domain")
+		}
+	}
+
+	if table := report.Table(); !strings.Contains(table, "Stripe (2 calls from BillingService)") || !strings.Contains(table, "SendGrid (0 calls from no service)") {
+		t.Errorf("Expected external systems in the table, got:\n%s", table)
+	}
+}
+
+func TestReport_CheckAndRender(t *testing.T) {
+	report := Analyze(parseModel(t, store), DefaultOptions())
+
+	if breaches := report.Check(Thresholds{}); len(breaches) != 0 {
+		t.Errorf("Expected no breaches without thresholds, got %v", breaches)
+	}
+
+	breaches := report.Check(Thresholds{FailOnCycles: true, FailOnChatty: true, MaxChainHops: 1, MaxInstability: 0.9})
+	if len(breaches) != 3 {
+		t.Errorf("Expected cycle, chatty and chain breaches, got %v", breaches)
+	}
+
+	table := report.Table()
+	for _, expected := range []string{"BillingService <-> OrderService <-> ShippingService", "OrderService -> BillingService (3 calls)", "Checkout (2 hops)"} {
+		if !strings.Contains(table, expected) {
+			t.Errorf("Expected table to contain %q, got:\n%s", expected, table)
+		}
+	}
+
+	html, err := report.HTML("Store <analysis>")
+	if err != nil {
+		t.Fatalf("Failed to render HTML: %v", err)
+	}
+	if !strings.Contains(string(html), "Store &lt;analysis&gt;") || !strings.Contains(string(html), "Orders → Billing → Shipping") {
+		t.Errorf("Unexpected HTML:\n%s", html)
+	}
+}
+
+func findCoupling(t *testing.T, metrics []Coupling, name string) Coupling {
+	t.Helper()
+	for _, metric := range metrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	t.Fatalf("No coupling metrics for %s", name)
+	return Coupling{}
+}
+
+func TestRecommendBoundaries(t *testing.T) {
+	model := parseModel(t, `services {
+  OrderService {
+    domains: Orders, Carts, Payments
+  }
+  BillingService {
+    domains: Billing, Invoices
+  }
+}
+
+use_case "Checkout" {
+  when Customer pays cart
+    Orders asks Carts to load cart
+    Orders asks Carts to close cart
+    Orders asks Billing to open account
+    Payments asks Billing to charge
+    Payments asks Billing to refund
+    Payments asks Invoices to attach receipt
+    Billing asks Invoices to draft
+    Billing notifies "Invoice Due"
+
+  when Invoices listens "Invoice Due"
+    Invoices asks Billing to mark sent
+}
+`)
+
+	report := RecommendBoundaries(model, DefaultBoundaryOptions())
+
+	expectedServices := []ProposedService{
+		{Name: "BillingService", Domains: []string{"Billing", "Invoices", "Payments"}},
+		{Name: "OrderService", Domains: []string{"Carts", "Orders"}},
+	}
+	if !reflect.DeepEqual(report.Services, expectedServices) {
+		t.Errorf("Expected services %+v, got %+v", expectedServices, report.Services)
+	}
+
+	expectedMoves := []DomainMove{{Domain: "Payments", From: "OrderService", To: "BillingService"}}
+	if !reflect.DeepEqual(report.Moves, expectedMoves) {
+		t.Errorf("Expected moves %+v, got %+v", expectedMoves, report.Moves)
+	}
+
+	if report.CurrentCrossSyncCalls != 4 || report.ProposedCrossSyncCalls != 1 {
+		t.Errorf("Expected cross-service sync calls 4 -> 1, got %d -> %d", report.CurrentCrossSyncCalls, report.ProposedCrossSyncCalls)
+	}
+	if report.ProposedModularity <= report.CurrentModularity {
+		t.Errorf("Expected modularity to improve, got %.2f -> %.2f", report.CurrentModularity, report.ProposedModularity)
+	}
+
+	table := report.Table()
+	for _, expected := range []string{"Payments: OrderService -> BillingService", "Cross-service sync calls: 4 -> 1 (-3, 75% reduction)"} {
+		if !strings.Contains(table, expected) {
+			t.Errorf("Expected table to contain %q, got:\n%s", expected, table)
+		}
+	}
+}
+
+func TestRecommendBoundaries_IgnoresExternalSystems(t *testing.T) {
+	model := parseModel(t, `external_system Stripe {}
+
+services {
+  OrderService {
+    domains: Orders
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+use_case "Pay" {
+  when Customer pays order
+    Orders asks Stripe to charge card
+    Orders asks Stripe to refund card
+    Billing asks Stripe to fetch payouts
+    Billing asks Stripe to fetch fees
+}
+`)
+
+	report := RecommendBoundaries(model, DefaultBoundaryOptions())
+
+	// Both domains calling Stripe is no reason to merge them, nor to give Stripe a service
+	expectedServices := []ProposedService{
+		{Name: "BillingService", Domains: []string{"Billing"}},
+		{Name: "OrderService", Domains: []string{"Orders"}},
+	}
+	if !reflect.DeepEqual(report.Services, expectedServices) {
+		t.Errorf("Expected services %+v, got %+v", expectedServices, report.Services)
+	}
+	if len(report.Moves) != 0 {
+		t.Errorf("Expected no moves, got %+v", report.Moves)
+	}
+	if report.CurrentCrossSyncCalls != 0 || report.ProposedCrossSyncCalls != 0 {
+		t.Errorf("Expected no cross-service sync calls, got %d -> %d", report.CurrentCrossSyncCalls, report.ProposedCrossSyncCalls)
+	}
+}
@@ -0,0 +1,343 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// BoundaryOptions weigh the domain interactions clustered into services
+type BoundaryOptions struct {
+	SyncWeight  float64 // Weight of each sync call between two domains
+	AsyncWeight float64 // Weight of each event consumed from a publishing domain
+}
+
+// DefaultBoundaryOptions returns the weights used by the command line: sync calls bind
+// domains tighter than events do
+func DefaultBoundaryOptions() BoundaryOptions {
+	return BoundaryOptions{SyncWeight: 1, AsyncWeight: 0.5}
+}
+
+// ProposedService is a group of domains the clustering would deploy together.
+// Name is the current service it matches best, "New service N" when it matches none,
+// or empty for a domain that stays outside any service.
+type ProposedService struct {
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+}
+
+// DomainMove is a domain the proposal places in another service; empty names mean no service
+type DomainMove struct {
+	Domain string `json:"domain"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// BoundaryReport compares the current services with a grouping of domains proposed
+// from their interactions in use cases
+type BoundaryReport struct {
+	Services               []ProposedService `json:"services"`
+	Moves                  []DomainMove      `json:"moves"`
+	CurrentModularity      float64           `json:"current_modularity"`
+	ProposedModularity     float64           `json:"proposed_modularity"`
+	CurrentCrossSyncCalls  int               `json:"current_cross_service_sync_calls"`
+	ProposedCrossSyncCalls int               `json:"proposed_cross_service_sync_calls"`
+}
+
+// interactions holds the symmetric weights between interacting domains
+type interactions map[string]map[string]float64
+
+func (w interactions) add(a, b string, weight float64) {
+	if a == b || weight <= 0 {
+		return
+	}
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if w[pair[0]] == nil {
+			w[pair[0]] = make(map[string]float64)
+		}
+		w[pair[0]][pair[1]] += weight
+	}
+}
+
+func (w interactions) degree(domain string) float64 {
+	total := 0.0
+	for _, weight := range w[domain] {
+		total += weight
+	}
+	return total
+}
+
+func (w interactions) total() float64 {
+	total := 0.0
+	for domain := range w {
+		total += w.degree(domain)
+	}
+	return total / 2
+}
+
+// RecommendBoundaries clusters the interacting domains by greedy modularity maximisation
+// over the weighted sync and async interactions, then compares the clusters with the
+// services blocks. Calls to external systems are left out, and domains without
+// interactions keep their current service.
+func RecommendBoundaries(model *parser.DSLModel, options BoundaryOptions) *BoundaryReport {
+	modelGraph := graph.Build(model)
+
+	weights := make(interactions)
+	for _, call := range modelGraph.Edges(graph.EdgeSync) {
+		if call.From.Kind() == graph.NodeDomain && call.To.Kind() == graph.NodeDomain {
+			weights.add(call.From.Name(), call.To.Name(), options.SyncWeight)
+		}
+	}
+	for _, consume := range modelGraph.Edges(graph.EdgeConsume) {
+		for _, publisher := range modelGraph.Publishers(consume.From.Name()) {
+			weights.add(publisher, consume.To.Name(), options.AsyncWeight)
+		}
+	}
+
+	current := make(map[string]string)
+	for _, domain := range domainNames(modelGraph) {
+		current[domain] = modelGraph.ServiceOf(domain)
+	}
+
+	clusters := cluster(weights)
+	proposed := nameClusters(clusters, current)
+
+	report := &BoundaryReport{
+		Services: make([]ProposedService, 0),
+		Moves:    make([]DomainMove, 0),
+	}
+
+	// Domains left out of the clustering stay where they are
+	for _, domain := range domainNames(modelGraph) {
+		if _, clustered := proposed[domain]; !clustered {
+			proposed[domain] = current[domain]
+		}
+	}
+
+	byService := make(map[string][]string)
+	for _, domain := range domainNames(modelGraph) {
+		service := proposed[domain]
+		if service == "" {
+			report.Services = append(report.Services, ProposedService{Domains: []string{domain}})
+		} else {
+			byService[service] = append(byService[service], domain)
+		}
+
+		if service != current[domain] {
+			report.Moves = append(report.Moves, DomainMove{Domain: domain, From: current[domain], To: service})
+		}
+	}
+	for service, domains := range byService {
+		report.Services = append(report.Services, ProposedService{Name: service, Domains: domains})
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		if report.Services[i].Name != report.Services[j].Name {
+			return report.Services[i].Name < report.Services[j].Name
+		}
+		return report.Services[i].Domains[0] < report.Services[j].Domains[0]
+	})
+
+	report.CurrentModularity = modularity(weights, current)
+	report.ProposedModularity = modularity(weights, proposed)
+	report.CurrentCrossSyncCalls = crossServiceSyncCalls(modelGraph, current)
+	report.ProposedCrossSyncCalls = crossServiceSyncCalls(modelGraph, proposed)
+
+	return report
+}
+
+// cluster merges communities of domains while a merge raises the modularity, best merge first.
+// Ties go to the communities whose first domains sort first, so the result is stable.
+func cluster(weights interactions) [][]string {
+	communities := make([][]string, 0, len(weights))
+	for domain := range weights {
+		communities = append(communities, []string{domain})
+	}
+	sort.Slice(communities, func(i, j int) bool { return communities[i][0] < communities[j][0] })
+
+	m := weights.total()
+	if m == 0 {
+		return communities
+	}
+
+	for {
+		bestI, bestJ, bestGain := -1, -1, 1e-12
+		for i := range communities {
+			for j := i + 1; j < len(communities); j++ {
+				between, degreeI, degreeJ := 0.0, 0.0, 0.0
+				for _, a := range communities[i] {
+					degreeI += weights.degree(a)
+					for _, b := range communities[j] {
+						between += weights[a][b]
+					}
+				}
+				for _, b := range communities[j] {
+					degreeJ += weights.degree(b)
+				}
+
+				if gain := between/m - degreeI*degreeJ/(2*m*m); gain > bestGain {
+					bestI, bestJ, bestGain = i, j, gain
+				}
+			}
+		}
+		if bestI < 0 {
+			break
+		}
+
+		merged := append(append([]string{}, communities[bestI]...), communities[bestJ]...)
+		sort.Strings(merged)
+		communities[bestI] = merged
+		communities = append(communities[:bestJ], communities[bestJ+1:]...)
+		sort.Slice(communities, func(i, j int) bool { return communities[i][0] < communities[j][0] })
+	}
+
+	return communities
+}
+
+// nameClusters matches each cluster to the current service sharing most of its domains, one
+// cluster per service, and returns the proposed service of every clustered domain
+func nameClusters(clusters [][]string, current map[string]string) map[string]string {
+	type candidate struct {
+		cluster int
+		service string
+		overlap int
+	}
+
+	candidates := make([]candidate, 0)
+	for i, domains := range clusters {
+		overlap := make(map[string]int)
+		for _, domain := range domains {
+			if service := current[domain]; service != "" {
+				overlap[service]++
+			}
+		}
+		for service, count := range overlap {
+			candidates = append(candidates, candidate{cluster: i, service: service, overlap: count})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].overlap != candidates[j].overlap {
+			return candidates[i].overlap > candidates[j].overlap
+		}
+		if candidates[i].service != candidates[j].service {
+			return candidates[i].service < candidates[j].service
+		}
+		return candidates[i].cluster < candidates[j].cluster
+	})
+
+	names := make(map[int]string)
+	taken := make(map[string]bool)
+	for _, c := range candidates {
+		if _, named := names[c.cluster]; !named && !taken[c.service] {
+			names[c.cluster] = c.service
+			taken[c.service] = true
+		}
+	}
+
+	proposed := make(map[string]string)
+	newServices := 0
+	for i, domains := range clusters {
+		name, named := names[i]
+		if !named && (len(domains) > 1 || current[domains[0]] != "") {
+			newServices++
+			name = fmt.Sprintf("New service %d", newServices)
+		}
+		for _, domain := range domains {
+			proposed[domain] = name
+		}
+	}
+	return proposed
+}
+
+// modularity scores how much of the interaction weight stays inside services;
+// domains outside any service count as services of their own
+func modularity(weights interactions, services map[string]string) float64 {
+	m := weights.total()
+	if m == 0 {
+		return 0
+	}
+
+	internal := make(map[string]float64)
+	degree := make(map[string]float64)
+	for a, neighbors := range weights {
+		group := serviceKey(a, services)
+		degree[group] += weights.degree(a)
+		for b, weight := range neighbors {
+			if serviceKey(b, services) == group {
+				internal[group] += weight / 2
+			}
+		}
+	}
+
+	score := 0.0
+	for group, d := range degree {
+		score += internal[group]/m - (d/(2*m))*(d/(2*m))
+	}
+	return score
+}
+
+// crossServiceSyncCalls counts the sync calls between domains of different services,
+// leaving out calls to external systems
+func crossServiceSyncCalls(modelGraph *graph.Graph, services map[string]string) int {
+	count := 0
+	for _, call := range modelGraph.Edges(graph.EdgeSync) {
+		if call.From.Kind() != graph.NodeDomain || call.To.Kind() != graph.NodeDomain {
+			continue
+		}
+		from, to := call.From.Name(), call.To.Name()
+		if from != to && serviceKey(from, services) != serviceKey(to, services) {
+			count++
+		}
+	}
+	return count
+}
+
+func serviceKey(domain string, services map[string]string) string {
+	if service := services[domain]; service != "" {
+		return "service:" + service
+	}
+	return "domain:" + domain
+}
+
+// JSON renders the boundary report as indented JSON
+func (r *BoundaryReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Table renders the boundary report as plain text
+func (r *BoundaryReport) Table() string {
+	var sb strings.Builder
+
+	sb.WriteString("Proposed services\n")
+	for _, service := range r.Services {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", serviceLabel(service.Name), strings.Join(service.Domains, ", ")))
+	}
+
+	sb.WriteString("\nDomains to move\n")
+	if len(r.Moves) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, move := range r.Moves {
+		sb.WriteString(fmt.Sprintf("  %s: %s -> %s\n", move.Domain, serviceLabel(move.From), serviceLabel(move.To)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nModularity: %.2f -> %.2f\n", r.CurrentModularity, r.ProposedModularity))
+	sb.WriteString(fmt.Sprintf("Cross-service sync calls: %d -> %d", r.CurrentCrossSyncCalls, r.ProposedCrossSyncCalls))
+	if r.CurrentCrossSyncCalls > 0 {
+		reduction := r.CurrentCrossSyncCalls - r.ProposedCrossSyncCalls
+		sb.WriteString(fmt.Sprintf(" (%+d, %.0f%% reduction)", -reduction, 100*float64(reduction)/float64(r.CurrentCrossSyncCalls)))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func serviceLabel(name string) string {
+	if name == "" {
+		return "(no service)"
+	}
+	return name
+}
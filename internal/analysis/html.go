@@ -0,0 +1,78 @@
+package analysis
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+td.num { text-align: right; }
+.warn { color: #C62828; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+
+<h2>Sync dependency cycles</h2>
+{{if .Report.Cycles}}<ul>{{range .Report.Cycles}}
+<li class="warn">{{join . " ↔ "}}</li>{{end}}
+</ul>{{else}}<p>None</p>{{end}}
+
+<h2>Service coupling</h2>
+<table>
+<tr><th>Service</th><th>Afferent (Ca)</th><th>Efferent (Ce)</th><th>Instability</th></tr>{{range .Report.ServiceCoupling}}
+<tr><td>{{.Name}}</td><td class="num">{{.Afferent}}</td><td class="num">{{.Efferent}}</td><td class="num">{{printf "%.2f" .Instability}}</td></tr>{{end}}
+</table>
+
+<h2>Domain coupling</h2>
+<table>
+<tr><th>Domain</th><th>Afferent (Ca)</th><th>Efferent (Ce)</th><th>Instability</th></tr>{{range .Report.DomainCoupling}}
+<tr><td>{{.Name}}</td><td class="num">{{.Afferent}}</td><td class="num">{{.Efferent}}</td><td class="num">{{printf "%.2f" .Instability}}</td></tr>{{end}}
+</table>
+
+<h2>Chatty service pairs</h2>
+{{if .Report.ChattyPairs}}<table>
+<tr><th>From</th><th>To</th><th>Calls</th><th>Operations</th></tr>{{range .Report.ChattyPairs}}
+<tr><td>{{.From}}</td><td>{{.To}}</td><td class="num">{{.Calls}}</td><td>{{range .Operations}}{{.}}<br>{{end}}</td></tr>{{end}}
+</table>{{else}}<p>None</p>{{end}}
+
+<h2>Longest sync call chains</h2>
+{{if .Report.LongestChains}}<table>
+<tr><th>Use case</th><th>Hops</th><th>Chain</th></tr>{{range .Report.LongestChains}}
+<tr><td>{{.UseCase}}</td><td class="num">{{.Hops}}</td><td>{{join .Domains " → "}}</td></tr>{{end}}
+</table>{{else}}<p>None</p>{{end}}
+
+<h2>External systems</h2>
+{{if .Report.External}}<table>
+<tr><th>System</th><th>Protocol</th><th>Owner</th><th>Services</th><th>Domains</th><th>Calls</th></tr>{{range .Report.External}}
+<tr><td>{{.Name}}</td><td>{{.Protocol}}</td><td>{{.Owner}}</td><td>{{join .Services ", "}}</td><td>{{join .Domains ", "}}</td><td class="num">{{.Calls}}</td></tr>{{end}}
+</table>{{else}}<p>None</p>{{end}}
+</body>
+</html>
+`))
+
+// HTML renders the report as a standalone HTML page
+func (r *Report) HTML(title string) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Title  string
+		Report *Report
+	}{Title: title, Report: r}
+
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
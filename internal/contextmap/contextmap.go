@@ -0,0 +1,214 @@
+package contextmap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Issue codes
+const (
+	CodeUnknownPattern = "unknown_context_pattern"
+	CodeUnknownContext = "unknown_context"
+)
+
+// patternAliases maps the spellings accepted in a context_map to their pattern
+var patternAliases = map[string]parser.ContextPattern{
+	"customer-supplier":  parser.ContextPatternCustomerSupplier,
+	"conformist":         parser.ContextPatternConformist,
+	"acl":                parser.ContextPatternACL,
+	"open-host-service":  parser.ContextPatternOpenHostService,
+	"ohs":                parser.ContextPatternOpenHostService,
+	"published-language": parser.ContextPatternPublishedLanguage,
+	"pl":                 parser.ContextPatternPublishedLanguage,
+	"shared-kernel":      parser.ContextPatternSharedKernel,
+	"partnership":        parser.ContextPatternPartnership,
+}
+
+// Context is a bounded context: a top-level domain and its subdomains
+type Context struct {
+	Name       string   `json:"name"`
+	SubDomains []string `json:"subDomains"`
+}
+
+// Relation is a dependency between two bounded contexts: From is downstream, To upstream.
+// Interactions come from the use cases; patterns from the context_map.
+type Relation struct {
+	From      string                  `json:"from"`
+	To        string                  `json:"to"`
+	Patterns  []parser.ContextPattern `json:"patterns,omitempty"`
+	SyncCalls int                     `json:"syncCalls"`        // Sync calls from From's domains to To's
+	Events    []string                `json:"events,omitempty"` // Events published in To and consumed in From
+	Declared  bool                    `json:"declared"`         // Listed in the context_map
+}
+
+// Map is the context map of a model
+type Map struct {
+	Contexts  []Context  `json:"contexts"`
+	Relations []Relation `json:"relations"`
+	Unmapped  []string   `json:"unmapped,omitempty"` // Domains in use cases that belong to no context
+}
+
+// Issue is a context_map entry that cannot be applied
+type Issue struct {
+	Line    int    `json:"line,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Pattern returns the pattern for a spelling used in a context_map
+func Pattern(text string) (parser.ContextPattern, bool) {
+	pattern, ok := patternAliases[strings.ToLower(text)]
+	return pattern, ok
+}
+
+// Symmetric reports whether a pattern binds both contexts equally rather than upstream and downstream
+func Symmetric(pattern parser.ContextPattern) bool {
+	return pattern == parser.ContextPatternSharedKernel || pattern == parser.ContextPatternPartnership
+}
+
+// AllSymmetric reports whether a relation has patterns and all of them are symmetric, so it has no direction
+func AllSymmetric(patterns []parser.ContextPattern) bool {
+	for _, pattern := range patterns {
+		if !Symmetric(pattern) {
+			return false
+		}
+	}
+	return len(patterns) > 0
+}
+
+// Build derives the relations between the model's bounded contexts from the interactions of its
+// domains, then annotates them with the patterns of the context_map. A consumer depends on the
+// context publishing the event it listens to.
+func Build(model *parser.DSLModel) *Map {
+	contextOf := contextIndex(model)
+	m := &Map{Contexts: make([]Context, 0), Relations: make([]Relation, 0), Unmapped: make([]string, 0)}
+	for _, domain := range model.Domains {
+		m.Contexts = append(m.Contexts, Context{Name: domain.Name, SubDomains: domain.SubDomains})
+	}
+
+	modelGraph := graph.Build(model)
+	relations := make(map[string]*Relation)
+	relation := func(from, to string) *Relation {
+		key := from + "->" + to
+		if relations[key] == nil {
+			relations[key] = &Relation{From: from, To: to}
+		}
+		return relations[key]
+	}
+
+	for _, node := range modelGraph.Nodes(graph.NodeDomain) {
+		if _, ok := contextOf[node.Name]; !ok {
+			m.Unmapped = append(m.Unmapped, node.Name)
+		}
+	}
+	sort.Strings(m.Unmapped)
+
+	for _, edge := range modelGraph.Edges(graph.EdgeSync) {
+		from, fromOK := contextOf[edge.From.Name()]
+		to, toOK := contextOf[edge.To.Name()]
+		if fromOK && toOK && from != to {
+			relation(from, to).SyncCalls++
+		}
+	}
+
+	for _, event := range modelGraph.Nodes(graph.NodeEvent) {
+		for _, publisher := range modelGraph.Publishers(event.Name) {
+			for _, consumer := range modelGraph.Consumers(event.Name) {
+				upstream, upstreamOK := contextOf[publisher]
+				downstream, downstreamOK := contextOf[consumer]
+				if !upstreamOK || !downstreamOK || upstream == downstream {
+					continue
+				}
+				if r := relation(downstream, upstream); !slices.Contains(r.Events, event.Name) {
+					r.Events = append(r.Events, event.Name)
+				}
+			}
+		}
+	}
+
+	for _, declared := range model.ContextMap {
+		patterns := make([]parser.ContextPattern, 0, len(declared.Patterns))
+		for _, text := range declared.Patterns {
+			if pattern, ok := Pattern(text); ok {
+				patterns = append(patterns, pattern)
+			}
+		}
+
+		// Symmetric patterns apply whichever way the contexts interact
+		r := relations[declared.From+"->"+declared.To]
+		if r == nil && AllSymmetric(patterns) {
+			r = relations[declared.To+"->"+declared.From]
+		}
+		if r == nil {
+			r = relation(declared.From, declared.To)
+		}
+
+		r.Declared = true
+		for _, pattern := range patterns {
+			if !slices.Contains(r.Patterns, pattern) {
+				r.Patterns = append(r.Patterns, pattern)
+			}
+		}
+	}
+
+	for _, r := range relations {
+		m.Relations = append(m.Relations, *r)
+	}
+	sort.Slice(m.Relations, func(i, j int) bool {
+		if m.Relations[i].From != m.Relations[j].From {
+			return m.Relations[i].From < m.Relations[j].From
+		}
+		return m.Relations[i].To < m.Relations[j].To
+	})
+
+	return m
+}
+
+// Validate checks that context_map entries name known contexts and patterns
+func Validate(model *parser.DSLModel) []Issue {
+	issues := make([]Issue, 0)
+	contexts := make(map[string]bool)
+	for _, domain := range model.Domains {
+		contexts[domain.Name] = true
+	}
+
+	for _, relation := range model.ContextMap {
+		for _, name := range []string{relation.From, relation.To} {
+			if !contexts[name] {
+				issues = append(issues, Issue{
+					Line:    relation.Line,
+					Code:    CodeUnknownContext,
+					Message: fmt.Sprintf("context map relation %s -> %s: %s is not a top-level domain", relation.From, relation.To, name),
+				})
+			}
+		}
+		for _, text := range relation.Patterns {
+			if _, ok := Pattern(text); !ok {
+				issues = append(issues, Issue{
+					Line:    relation.Line,
+					Code:    CodeUnknownPattern,
+					Message: fmt.Sprintf("context map relation %s -> %s: unknown pattern %s", relation.From, relation.To, text),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// contextIndex maps each top-level domain and subdomain to its bounded context
+func contextIndex(model *parser.DSLModel) map[string]string {
+	contextOf := make(map[string]string)
+	for _, domain := range model.Domains {
+		contextOf[domain.Name] = domain.Name
+		for _, subDomain := range domain.SubDomains {
+			contextOf[subDomain] = domain.Name
+		}
+	}
+	return contextOf
+}
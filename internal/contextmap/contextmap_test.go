@@ -0,0 +1,104 @@
+package contextmap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const shop = `domains {
+  Sales {
+    Orders
+    Cart
+  }
+  Billing {
+    Invoicing
+  }
+  Shipping {
+    Dispatch
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Cart asks Orders to create order
+    Orders asks Invoicing to issue invoice
+    Orders asks Invoicing to load invoice
+    Orders asks Fraud to check order
+    Orders notifies "order placed"
+
+  when Dispatch listens "order placed"
+    Dispatch books courier
+}
+`
+
+const relations = `  Sales -> Billing : customer-supplier, ACL
+  Sales -> Shipping : shared-kernel
+  Billing -> Shipping : ohs
+`
+
+// shopModel parses the shop with a context map of the given relations, starting on line 27
+func shopModel(t *testing.T, relations string) *parser.DSLModel {
+	t.Helper()
+	dsl := shop
+	if relations != "" {
+		dsl += "\ncontext_map {\n" + relations + "}\n"
+	}
+	model, err := parser.ParseDSLToModel(dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestBuild(t *testing.T) {
+	m := Build(shopModel(t, relations))
+
+	expected := []Relation{
+		{From: "Billing", To: "Shipping", Patterns: []parser.ContextPattern{parser.ContextPatternOpenHostService}, Declared: true},
+		{From: "Sales", To: "Billing", Patterns: []parser.ContextPattern{parser.ContextPatternCustomerSupplier, parser.ContextPatternACL}, SyncCalls: 2, Declared: true},
+		{From: "Shipping", To: "Sales", Patterns: []parser.ContextPattern{parser.ContextPatternSharedKernel}, Events: []string{"order placed"}, Declared: true},
+	}
+	if !reflect.DeepEqual(m.Relations, expected) {
+		t.Errorf("Unexpected relations:\nExpected %+v\nGot      %+v", expected, m.Relations)
+	}
+
+	if !reflect.DeepEqual(m.Unmapped, []string{"Fraud"}) {
+		t.Errorf("Expected Fraud to be unmapped, got %v", m.Unmapped)
+	}
+	if len(m.Contexts) != 3 || m.Contexts[0].Name != "Sales" {
+		t.Errorf("Expected contexts in model order, got %+v", m.Contexts)
+	}
+}
+
+func TestBuild_WithoutContextMap(t *testing.T) {
+	model := shopModel(t, "")
+
+	m := Build(model)
+	if len(m.Relations) != 2 {
+		t.Fatalf("Expected 2 derived relations, got %+v", m.Relations)
+	}
+	for _, relation := range m.Relations {
+		if relation.Declared || len(relation.Patterns) != 0 {
+			t.Errorf("Expected an undeclared relation, got %+v", relation)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	model := shopModel(t, relations+`  Sales -> Payments : conformist
+  Sales -> Billing : friendship
+`)
+
+	issues := Validate(model)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %+v", issues)
+	}
+	if issues[0].Line != 30 || issues[0].Code != CodeUnknownContext {
+		t.Errorf("Unexpected issue %+v", issues[0])
+	}
+	if issues[1].Line != 31 || issues[1].Code != CodeUnknownPattern {
+		t.Errorf("Unexpected issue %+v", issues[1])
+	}
+}
@@ -0,0 +1,161 @@
+package crud
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// letters maps each access mode to its CRUD letter
+var letters = map[parser.DataAccess]string{
+	parser.DataAccessCreate: "C",
+	parser.DataAccessRead:   "R",
+	parser.DataAccessWrite:  "U",
+	parser.DataAccessDelete: "D",
+}
+
+// Matrix holds the access of each row to each data store, as CRUD letters such as "CR" or "U"
+type Matrix struct {
+	Rows  []string   `json:"rows"`
+	Cells [][]string `json:"cells"` // Cells[row][column], columns following Report.DataStores
+}
+
+// Report is the data access of domains and use cases to every data store of a model
+type Report struct {
+	DataStores []string `json:"dataStores"`
+	Domains    Matrix   `json:"domains"`
+	UseCases   Matrix   `json:"useCases"`
+}
+
+// Build derives the CRUD matrices of a model from its data access actions. Columns cover every
+// data store listed by services, declared in datastores or accessed; rows cover the domains, sorted,
+// and the use cases, in model order, accessing at least one store.
+func Build(model *parser.DSLModel) *Report {
+	stores := make(map[string]bool)
+	for _, service := range model.Services {
+		for _, dataStore := range service.DataStores {
+			stores[dataStore] = true
+		}
+	}
+	for _, dataStore := range model.DataStores {
+		stores[dataStore.Name] = true
+	}
+
+	domains := make(map[string]map[string]map[string]bool)
+	useCases := make(map[string]map[string]map[string]bool)
+	useCaseOrder := make([]string, 0)
+	for _, useCase := range model.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			for _, action := range scenario.Actions {
+				letter, ok := letters[action.Access]
+				if action.Type != parser.ActionTypeDataAccess || action.DataStore == "" || !ok {
+					continue
+				}
+				stores[action.DataStore] = true
+				record(domains, action.Domain, action.DataStore, letter)
+				if useCases[useCase.Name] == nil {
+					useCaseOrder = append(useCaseOrder, useCase.Name)
+				}
+				record(useCases, useCase.Name, action.DataStore, letter)
+			}
+		}
+	}
+
+	report := &Report{DataStores: sortedKeys(stores)}
+	report.Domains = report.matrix(sortedKeys(domains), domains)
+	report.UseCases = report.matrix(useCaseOrder, useCases)
+	return report
+}
+
+// record notes that row accessed the data store with the CRUD letter
+func record(accesses map[string]map[string]map[string]bool, row, dataStore, letter string) {
+	if accesses[row] == nil {
+		accesses[row] = make(map[string]map[string]bool)
+	}
+	if accesses[row][dataStore] == nil {
+		accesses[row][dataStore] = make(map[string]bool)
+	}
+	accesses[row][dataStore][letter] = true
+}
+
+func (r *Report) matrix(rows []string, accesses map[string]map[string]map[string]bool) Matrix {
+	matrix := Matrix{Rows: rows, Cells: make([][]string, len(rows))}
+	for i, row := range rows {
+		matrix.Cells[i] = make([]string, len(r.DataStores))
+		for j, dataStore := range r.DataStores {
+			var cell strings.Builder
+			for _, letter := range []string{"C", "R", "U", "D"} {
+				if accesses[row][dataStore][letter] {
+					cell.WriteString(letter)
+				}
+			}
+			matrix.Cells[i][j] = cell.String()
+		}
+	}
+	return matrix
+}
+
+// Markdown renders the matrices as markdown tables
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("## Domains\n\n")
+	r.writeMarkdownTable(&sb, "Domain", r.Domains)
+	sb.WriteString("\n## Use cases\n\n")
+	r.writeMarkdownTable(&sb, "Use case", r.UseCases)
+	return sb.String()
+}
+
+func (r *Report) writeMarkdownTable(sb *strings.Builder, heading string, matrix Matrix) {
+	if len(matrix.Rows) == 0 {
+		sb.WriteString("No data access\n")
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", heading, strings.Join(r.DataStores, " | ")))
+	sb.WriteString("|---" + strings.Repeat("|:-:", len(r.DataStores)) + "|\n")
+	for i, row := range matrix.Rows {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", row, strings.Join(matrix.Cells[i], " | ")))
+	}
+}
+
+// CSV renders both matrices as one table whose first column tells a domain row from a use case row
+func (r *Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	records := [][]string{append([]string{"kind", "name"}, r.DataStores...)}
+	for i, row := range r.Domains.Rows {
+		records = append(records, append([]string{"domain", row}, r.Domains.Cells[i]...))
+	}
+	for i, row := range r.UseCases.Rows {
+		records = append(records, append([]string{"use_case", row}, r.UseCases.Cells[i]...))
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Access returns the CRUD letters of a domain on a data store, or an empty string
+func (r *Report) Access(domain, dataStore string) string {
+	i, j := slices.Index(r.Domains.Rows, domain), slices.Index(r.DataStores, dataStore)
+	if i < 0 || j < 0 {
+		return ""
+	}
+	return r.Domains.Cells[i][j]
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func parseModel(t *testing.T, dsl string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+const profile = `services {
+  UserService {
+    domains: Authentication, Profile
+    data-stores: user_db, archive
+  }
+}
+
+datastores {
+  session_cache {
+    type: redis
+  }
+}
+
+use_case "Sign Up" {
+  when Visitor signs up
+    Profile creates in user_db
+    Profile reads from user_db
+    Profile sends welcome email
+}
+
+use_case "Log In" {
+  when Member enters credentials
+    Authentication reads from user_db
+    Authentication creates in session_cache
+}
+
+use_case "Close Account" {
+  when Member closes account
+    Profile deletes from user_db
+    Profile writes to user_db
+    Authentication deletes from session_cache
+}
+
+use_case "Browse" {
+  when Member opens profile
+    Profile asks Authentication to check session
+}
+`
+
+func TestBuild_Matrices(t *testing.T) {
+	report := Build(parseModel(t, profile))
+
+	if expected := []string{"archive", "session_cache", "user_db"}; !reflect.DeepEqual(report.DataStores, expected) {
+		t.Errorf("Expected data stores %v, got %v", expected, report.DataStores)
+	}
+
+	expectedDomains := Matrix{
+		Rows:  []string{"Authentication", "Profile"},
+		Cells: [][]string{{"", "CD", "R"}, {"", "", "CRUD"}},
+	}
+	if !reflect.DeepEqual(report.Domains, expectedDomains) {
+		t.Errorf("Expected domain matrix %v, got %v", expectedDomains, report.Domains)
+	}
+
+	expectedUseCases := Matrix{
+		Rows:  []string{"Sign Up", "Log In", "Close Account"},
+		Cells: [][]string{{"", "", "CR"}, {"", "C", "R"}, {"", "D", "UD"}},
+	}
+	if !reflect.DeepEqual(report.UseCases, expectedUseCases) {
+		t.Errorf("Expected use case matrix %v, got %v", expectedUseCases, report.UseCases)
+	}
+
+	if got := report.Access("Profile", "user_db"); got != "CRUD" {
+		t.Errorf("Expected CRUD, got %q", got)
+	}
+	if got := report.Access("Billing", "user_db"); got != "" {
+		t.Errorf("Expected no access for an unknown domain, got %q", got)
+	}
+}
+
+func TestReport_Formats(t *testing.T) {
+	report := Build(parseModel(t, profile))
+
+	markdown := report.Markdown()
+	for _, expected := range []string{
+		"| Domain | archive | session_cache | user_db |\n|---|:-:|:-:|:-:|\n",
+		"| Profile |  |  | CRUD |\n",
+		"| Close Account |  | D | UD |\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", expected, markdown)
+		}
+	}
+
+	content, err := report.CSV()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 6 || lines[0] != "kind,name,archive,session_cache,user_db" || lines[3] != "use_case,Sign Up,,,CR" {
+		t.Errorf("Unexpected CSV:\n%s", content)
+	}
+
+	page, err := report.HTML("CRUD matrix")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(page), `<tr><td>Authentication</td><td class="access"></td><td class="access">CD</td><td class="access">R</td></tr>`) {
+		t.Errorf("Expected the Authentication row in the page, got:\n%s", page)
+	}
+}
+
+func TestBuild_NoDataAccess(t *testing.T) {
+	report := Build(parseModel(t, `services {
+  UserService {
+    data-stores: user_db
+  }
+}
+`))
+
+	if len(report.Domains.Rows) != 0 || len(report.UseCases.Rows) != 0 {
+		t.Errorf("Expected empty matrices, got %+v", report)
+	}
+	if markdown := report.Markdown(); strings.Count(markdown, "No data access") != 2 {
+		t.Errorf("Expected both matrices to report no data access, got:\n%s", markdown)
+	}
+}
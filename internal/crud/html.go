@@ -0,0 +1,60 @@
+package crud
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+td.access { text-align: center; font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range $m := .Matrices}}
+<h2>{{$m.Heading}}</h2>
+{{if $m.Matrix.Rows}}<table>
+<tr><th>{{$m.Column}}</th>{{range $.Report.DataStores}}<th>{{.}}</th>{{end}}</tr>{{range $i, $row := $m.Matrix.Rows}}
+<tr><td>{{$row}}</td>{{range index $m.Matrix.Cells $i}}<td class="access">{{.}}</td>{{end}}</tr>{{end}}
+</table>{{else}}<p>No data access</p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+type htmlMatrix struct {
+	Heading string
+	Column  string
+	Matrix  Matrix
+}
+
+// HTML renders the matrices as a standalone HTML page
+func (r *Report) HTML(title string) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Title    string
+		Report   *Report
+		Matrices []htmlMatrix
+	}{
+		Title:  title,
+		Report: r,
+		Matrices: []htmlMatrix{
+			{Heading: "Domains", Column: "Domain", Matrix: r.Domains},
+			{Heading: "Use cases", Column: "Use case", Matrix: r.UseCases},
+		},
+	}
+
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,131 @@
+package datastore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Issue codes
+const (
+	CodeSharedDataStore  = "shared_data_store"
+	CodeUnknownService   = "unknown_data_store_service"
+	CodeUnknownDataStore = "unknown_data_store"
+	CodeUnownedDataStore = "data_store_not_owned"
+)
+
+// Issue is a data store breaking the one-owner-per-store rule, a declaration that cannot be applied,
+// or a data access action on a store its domain's service cannot use
+type Issue struct {
+	DataStore string `json:"dataStore"`
+	UseCase   string `json:"useCase,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// Validate checks that every data store is used by a single service, and that shared_by names
+// known services. Stores only listed in data-stores are checked too; their issues carry no line.
+// Data access actions must name a known store that their domain's service owns or shares.
+func Validate(model *parser.DSLModel) []Issue {
+	issues := make([]Issue, 0)
+
+	services := make(map[string]bool)
+	for _, service := range model.Services {
+		services[service.Name] = true
+	}
+
+	for _, dataStore := range model.DataStores {
+		for _, service := range dataStore.SharedBy {
+			if !services[service] {
+				issues = append(issues, Issue{
+					DataStore: dataStore.Name,
+					Line:      dataStore.Line,
+					Code:      CodeUnknownService,
+					Message:   fmt.Sprintf("data store '%s' is shared by unknown service %s", dataStore.Name, service),
+				})
+			}
+		}
+	}
+
+	for _, name := range names(model) {
+		users := model.DataStoreUsers(name)
+		if len(users) < 2 {
+			continue
+		}
+
+		issue := Issue{
+			DataStore: name,
+			Code:      CodeSharedDataStore,
+			Message:   fmt.Sprintf("data store '%s' is shared by services %s; each service should own its data", name, strings.Join(users, ", ")),
+		}
+		if dataStore := model.DataStore(name); dataStore != nil {
+			issue.Line = dataStore.Line
+		}
+		issues = append(issues, issue)
+	}
+
+	return append(issues, validateAccess(model)...)
+}
+
+// validateAccess checks the data stores of data access actions. Domains outside any service
+// are only checked for unknown stores.
+func validateAccess(model *parser.DSLModel) []Issue {
+	issues := make([]Issue, 0)
+	known := names(model)
+
+	for _, useCase := range model.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			for _, action := range scenario.Actions {
+				if action.Type != parser.ActionTypeDataAccess {
+					continue
+				}
+
+				issue := Issue{DataStore: action.DataStore, UseCase: useCase.Name, Line: action.Line}
+				service := serviceOf(model, action.Domain)
+				switch {
+				case !slices.Contains(known, action.DataStore):
+					issue.Code = CodeUnknownDataStore
+					issue.Message = fmt.Sprintf("domain '%s' uses unknown data store '%s' in use case '%s'", action.Domain, action.DataStore, useCase.Name)
+				case service != "" && !slices.Contains(model.DataStoreUsers(action.DataStore), service):
+					issue.Code = CodeUnownedDataStore
+					issue.Message = fmt.Sprintf("domain '%s' uses data store '%s' in use case '%s', but its service %s neither owns nor shares it", action.Domain, action.DataStore, useCase.Name, service)
+				default:
+					continue
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	return issues
+}
+
+// serviceOf returns the service listing a domain, or its top-level domain, in its domains
+func serviceOf(model *parser.DSLModel, domain string) string {
+	parent := model.ParentDomain(domain)
+	for _, service := range model.Services {
+		if slices.Contains(service.Domains, domain) || (parent != "" && slices.Contains(service.Domains, parent)) {
+			return service.Name
+		}
+	}
+	return ""
+}
+
+// names returns the declared data stores, then those only listed in data-stores, in declaration order
+func names(model *parser.DSLModel) []string {
+	result := make([]string, 0, len(model.DataStores))
+	for _, dataStore := range model.DataStores {
+		result = append(result, dataStore.Name)
+	}
+	for _, service := range model.Services {
+		for _, name := range service.DataStores {
+			if !slices.Contains(result, name) {
+				result = append(result, name)
+			}
+		}
+	}
+	return result
+}
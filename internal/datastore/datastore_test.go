@@ -0,0 +1,83 @@
+package datastore
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestValidate(t *testing.T) {
+	model := &parser.DSLModel{
+		Services: []parser.Service{
+			{Name: "AccountService", DataStores: []string{"account_db", "session_cache"}},
+			{Name: "ReportService", DataStores: []string{"report_db", "session_cache"}},
+		},
+		DataStores: []parser.DataStore{
+			{Name: "account_db", Type: "postgres", SharedBy: []string{"AuditService"}, Line: 10},
+			{Name: "report_db", SharedBy: []string{"AccountService"}, Line: 14},
+		},
+	}
+
+	expected := []Issue{
+		{DataStore: "account_db", Line: 10, Code: CodeUnknownService, Message: "data store 'account_db' is shared by unknown service AuditService"},
+		{DataStore: "account_db", Line: 10, Code: CodeSharedDataStore, Message: "data store 'account_db' is shared by services AccountService, AuditService; each service should own its data"},
+		{DataStore: "report_db", Line: 14, Code: CodeSharedDataStore, Message: "data store 'report_db' is shared by services ReportService, AccountService; each service should own its data"},
+		{DataStore: "session_cache", Code: CodeSharedDataStore, Message: "data store 'session_cache' is shared by services AccountService, ReportService; each service should own its data"},
+	}
+	if issues := Validate(model); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Unexpected issues:\nExpected %+v\nGot      %+v", expected, issues)
+	}
+}
+
+func TestValidate_SingleOwner(t *testing.T) {
+	model := &parser.DSLModel{
+		Services: []parser.Service{
+			{Name: "AccountService", DataStores: []string{"account_db"}},
+			{Name: "ReportService", DataStores: []string{"report_db"}},
+		},
+		DataStores: []parser.DataStore{{Name: "account_db", Type: "postgres", Line: 3}},
+	}
+
+	if issues := Validate(model); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+}
+
+func TestValidate_DataAccess(t *testing.T) {
+	access := func(domain, dataStore string, line int) parser.Action {
+		return parser.Action{Type: parser.ActionTypeDataAccess, Domain: domain, DataStore: dataStore, Access: parser.DataAccessRead, Line: line}
+	}
+	model := &parser.DSLModel{
+		Domains: []parser.Domain{{Name: "Accounts", SubDomains: []string{"Balances"}}},
+		Services: []parser.Service{
+			{Name: "AccountService", Domains: []string{"Accounts"}, DataStores: []string{"account_db"}},
+			{Name: "ReportService", Domains: []string{"Reports"}, DataStores: []string{"report_db"}},
+		},
+		DataStores: []parser.DataStore{{Name: "report_db", SharedBy: []string{"AccountService"}}},
+		UseCases: []parser.UseCase{{
+			Name: "Statement",
+			Scenarios: []parser.Scenario{{
+				Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "requests", Phrase: "statement"},
+				Actions: []parser.Action{
+					access("Balances", "account_db", 5),
+					access("Balances", "report_db", 6),
+					access("Reports", "account_db", 7),
+					access("Reports", "catalog", 8),
+					access("Audit", "catalog", 9),
+					access("Audit", "account_db", 10),
+				},
+			}},
+		}},
+	}
+
+	expected := []Issue{
+		{DataStore: "report_db", Code: CodeSharedDataStore, Message: "data store 'report_db' is shared by services ReportService, AccountService; each service should own its data"},
+		{DataStore: "account_db", UseCase: "Statement", Line: 7, Code: CodeUnownedDataStore, Message: "domain 'Reports' uses data store 'account_db' in use case 'Statement', but its service ReportService neither owns nor shares it"},
+		{DataStore: "catalog", UseCase: "Statement", Line: 8, Code: CodeUnknownDataStore, Message: "domain 'Reports' uses unknown data store 'catalog' in use case 'Statement'"},
+		{DataStore: "catalog", UseCase: "Statement", Line: 9, Code: CodeUnknownDataStore, Message: "domain 'Audit' uses unknown data store 'catalog' in use case 'Statement'"},
+	}
+	if issues := Validate(model); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Unexpected issues:\nExpected %+v\nGot      %+v", expected, issues)
+	}
+}
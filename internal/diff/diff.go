@@ -0,0 +1,486 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+// ChangeKind describes what happened to an element between two model versions
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeMoved   ChangeKind = "moved"
+)
+
+// ElementType identifies the kind of model element that changed
+type ElementType string
+
+const (
+	ElementService    ElementType = "service"
+	ElementDomain     ElementType = "domain"
+	ElementDataStore  ElementType = "datastore"
+	ElementDependency ElementType = "sync_dependency"
+	ElementEvent      ElementType = "event"
+	ElementConsumer   ElementType = "consumer"
+)
+
+// elementOrder controls how changes are grouped in reports
+var elementOrder = []ElementType{ElementService, ElementDomain, ElementDataStore, ElementDependency, ElementEvent, ElementConsumer}
+
+var elementTitles = map[ElementType]string{
+	ElementService:    "Services",
+	ElementDomain:     "Domains",
+	ElementDataStore:  "Data stores",
+	ElementDependency: "Sync dependencies",
+	ElementEvent:      "Events",
+	ElementConsumer:   "Consumers",
+}
+
+var kindSymbols = map[ChangeKind]string{
+	ChangeAdded:   "+",
+	ChangeRemoved: "-",
+	ChangeMoved:   "~",
+}
+
+// Change is a single semantic difference between two models.
+// From/To hold the previous and new service of a moved domain, the caller and callee of a
+// sync dependency, and the event and consuming domain of a consumer change.
+type Change struct {
+	Kind    ChangeKind  `json:"kind"`
+	Element ElementType `json:"element"`
+	Name    string      `json:"name"`
+	Service string      `json:"service,omitempty"`
+	From    string      `json:"from,omitempty"`
+	To      string      `json:"to,omitempty"`
+}
+
+// ModelDiff holds the semantic differences between an old and a new model
+type ModelDiff struct {
+	Changes  []Change `json:"changes"`
+	oldModel *parser.DSLModel
+	newModel *parser.DSLModel
+}
+
+// Compare computes the semantic differences between two models
+func Compare(oldModel, newModel *parser.DSLModel) *ModelDiff {
+	d := &ModelDiff{
+		Changes:  make([]Change, 0),
+		oldModel: oldModel,
+		newModel: newModel,
+	}
+
+	before := newSnapshot(oldModel)
+	after := newSnapshot(newModel)
+
+	d.compareServices(before, after)
+	d.compareDomains(before, after)
+	d.compareDataStores(before, after)
+	d.compareDependencies(before, after)
+	d.compareEvents(before, after)
+	d.compareConsumers(before, after)
+
+	d.sortChanges()
+	return d
+}
+
+// IsEmpty reports whether the models are architecturally identical
+func (d *ModelDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// compareServices reports services that were added or removed
+func (d *ModelDiff) compareServices(before, after *snapshot) {
+	for name := range after.services {
+		if _, exists := before.services[name]; !exists {
+			d.add(Change{Kind: ChangeAdded, Element: ElementService, Name: name})
+		}
+	}
+	for name := range before.services {
+		if _, exists := after.services[name]; !exists {
+			d.add(Change{Kind: ChangeRemoved, Element: ElementService, Name: name})
+		}
+	}
+}
+
+// compareDomains reports domains added to, removed from or moved between services.
+// Domains that simply come and go with their service are covered by the service change.
+func (d *ModelDiff) compareDomains(before, after *snapshot) {
+	for domain, newOwner := range after.domainOwners {
+		oldOwner, existed := before.domainOwners[domain]
+		switch {
+		case !existed && before.hasService(newOwner):
+			d.add(Change{Kind: ChangeAdded, Element: ElementDomain, Name: domain, Service: newOwner})
+		case existed && oldOwner != newOwner:
+			d.add(Change{Kind: ChangeMoved, Element: ElementDomain, Name: domain, Service: newOwner, From: oldOwner, To: newOwner})
+		}
+	}
+	for domain, oldOwner := range before.domainOwners {
+		if _, exists := after.domainOwners[domain]; !exists && after.hasService(oldOwner) {
+			d.add(Change{Kind: ChangeRemoved, Element: ElementDomain, Name: domain, Service: oldOwner})
+		}
+	}
+}
+
+// compareDataStores reports datastores added to or removed from services present in both models
+func (d *ModelDiff) compareDataStores(before, after *snapshot) {
+	for name, service := range after.services {
+		oldService, existed := before.services[name]
+		if !existed {
+			continue
+		}
+		for _, dataStore := range service.DataStores {
+			if !slices.Contains(oldService.DataStores, dataStore) {
+				d.add(Change{Kind: ChangeAdded, Element: ElementDataStore, Name: dataStore, Service: name})
+			}
+		}
+		for _, dataStore := range oldService.DataStores {
+			if !slices.Contains(service.DataStores, dataStore) {
+				d.add(Change{Kind: ChangeRemoved, Element: ElementDataStore, Name: dataStore, Service: name})
+			}
+		}
+	}
+}
+
+// compareDependencies reports new and dropped sync calls between domains
+func (d *ModelDiff) compareDependencies(before, after *snapshot) {
+	for dependency := range after.dependencies {
+		if !before.dependencies[dependency] {
+			d.add(dependencyChange(ChangeAdded, dependency))
+		}
+	}
+	for dependency := range before.dependencies {
+		if !after.dependencies[dependency] {
+			d.add(dependencyChange(ChangeRemoved, dependency))
+		}
+	}
+}
+
+// compareEvents reports published events that were added or removed
+func (d *ModelDiff) compareEvents(before, after *snapshot) {
+	for event := range after.publishers {
+		if _, exists := before.publishers[event]; !exists {
+			d.add(Change{Kind: ChangeAdded, Element: ElementEvent, Name: event})
+		}
+	}
+	for event := range before.publishers {
+		if _, exists := after.publishers[event]; !exists {
+			d.add(Change{Kind: ChangeRemoved, Element: ElementEvent, Name: event})
+		}
+	}
+}
+
+// compareConsumers reports domains that started or stopped listening to an event
+func (d *ModelDiff) compareConsumers(before, after *snapshot) {
+	for event, consumers := range after.consumers {
+		for consumer := range consumers {
+			if !before.consumers[event][consumer] {
+				d.add(Change{Kind: ChangeAdded, Element: ElementConsumer, Name: event, From: event, To: consumer})
+			}
+		}
+	}
+	for event, consumers := range before.consumers {
+		for consumer := range consumers {
+			if !after.consumers[event][consumer] {
+				d.add(Change{Kind: ChangeRemoved, Element: ElementConsumer, Name: event, From: event, To: consumer})
+			}
+		}
+	}
+}
+
+func (d *ModelDiff) add(change Change) {
+	d.Changes = append(d.Changes, change)
+}
+
+// sortChanges orders changes by element type, then kind, then name
+func (d *ModelDiff) sortChanges() {
+	rank := func(element ElementType) int {
+		return slices.Index(elementOrder, element)
+	}
+	kindRank := map[ChangeKind]int{ChangeAdded: 0, ChangeRemoved: 1, ChangeMoved: 2}
+
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		a, b := d.Changes[i], d.Changes[j]
+		if a.Element != b.Element {
+			return rank(a.Element) < rank(b.Element)
+		}
+		if a.Kind != b.Kind {
+			return kindRank[a.Kind] < kindRank[b.Kind]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.To < b.To
+	})
+}
+
+func dependencyChange(kind ChangeKind, dependency [2]string) Change {
+	return Change{
+		Kind:    kind,
+		Element: ElementDependency,
+		Name:    fmt.Sprintf("%s -> %s", dependency[0], dependency[1]),
+		From:    dependency[0],
+		To:      dependency[1],
+	}
+}
+
+// =============================================================================
+// Output
+// =============================================================================
+
+// Text renders the diff as a human readable report grouped by element type
+func (d *ModelDiff) Text() string {
+	if d.IsEmpty() {
+		return "No architectural changes\n"
+	}
+
+	var sb strings.Builder
+	for _, element := range elementOrder {
+		lines := make([]string, 0)
+		for _, change := range d.Changes {
+			if change.Element == element {
+				lines = append(lines, fmt.Sprintf("  %s %s", kindSymbols[change.Kind], describe(change)))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		sb.WriteString(elementTitles[element] + ":\n")
+		sb.WriteString(strings.Join(lines, "\n"))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// describe renders the subject of a change for the text report
+func describe(change Change) string {
+	switch change.Element {
+	case ElementDomain:
+		if change.Kind == ChangeMoved {
+			return fmt.Sprintf("%s: %s -> %s", change.Name, change.From, change.To)
+		}
+		return fmt.Sprintf("%s (%s)", change.Name, change.Service)
+	case ElementDataStore:
+		return fmt.Sprintf("%s (%s)", change.Name, change.Service)
+	case ElementEvent:
+		return fmt.Sprintf("%q", change.Name)
+	case ElementConsumer:
+		return fmt.Sprintf("%q -> %s", change.Name, change.To)
+	}
+	return change.Name
+}
+
+// Sentence renders the change as a changelog sentence, e.g. "Billing now depends on Ledger (sync)"
+func (c Change) Sentence() string {
+	switch c.Element {
+	case ElementService:
+		return fmt.Sprintf("Service %s %s", c.Name, c.Kind)
+	case ElementDomain:
+		switch c.Kind {
+		case ChangeMoved:
+			return fmt.Sprintf("Domain %s moved from %s to %s", c.Name, c.From, c.To)
+		case ChangeAdded:
+			return fmt.Sprintf("Domain %s added to %s", c.Name, c.Service)
+		}
+		return fmt.Sprintf("Domain %s removed from %s", c.Name, c.Service)
+	case ElementDataStore:
+		if c.Kind == ChangeAdded {
+			return fmt.Sprintf("%s now uses datastore %s", c.Service, c.Name)
+		}
+		return fmt.Sprintf("%s no longer uses datastore %s", c.Service, c.Name)
+	case ElementDependency:
+		if c.Kind == ChangeAdded {
+			return fmt.Sprintf("%s now depends on %s (sync)", c.From, c.To)
+		}
+		return fmt.Sprintf("%s no longer depends on %s (sync)", c.From, c.To)
+	case ElementEvent:
+		return fmt.Sprintf("Event '%s' %s", c.Name, c.Kind)
+	case ElementConsumer:
+		if c.Kind == ChangeAdded {
+			return fmt.Sprintf("Event '%s' gained consumer %s", c.Name, c.To)
+		}
+		return fmt.Sprintf("Event '%s' lost consumer %s", c.Name, c.To)
+	}
+	return c.Name
+}
+
+// JSON renders the diff as indented JSON
+func (d *ModelDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Highlights maps the changes onto diagram highlights for the merged model
+func (d *ModelDiff) Highlights() *visualizer.Highlights {
+	highlights := visualizer.NewHighlights()
+	before := newSnapshot(d.oldModel)
+	after := newSnapshot(d.newModel)
+
+	for _, change := range d.Changes {
+		tag := highlightTag(change.Kind)
+		switch change.Element {
+		case ElementService:
+			highlights.Mark(visualizer.HighlightService, change.Name, tag)
+		case ElementDomain:
+			highlights.Mark(visualizer.HighlightDomain, change.Name, tag)
+		case ElementDataStore:
+			highlights.Mark(visualizer.HighlightDataStore, change.Name, tag)
+		case ElementDependency:
+			highlights.MarkRelation(change.From, change.To, tag)
+		case ElementEvent:
+			publishers := after.publishers[change.Name]
+			if change.Kind == ChangeRemoved {
+				publishers = before.publishers[change.Name]
+			}
+			for _, publisher := range publishers {
+				highlights.MarkRelation(publisher, change.Name, tag)
+			}
+		case ElementConsumer:
+			highlights.MarkRelation(change.From, change.To, tag)
+		}
+	}
+
+	return highlights
+}
+
+func highlightTag(kind ChangeKind) visualizer.HighlightTag {
+	switch kind {
+	case ChangeAdded:
+		return visualizer.HighlightAdded
+	case ChangeRemoved:
+		return visualizer.HighlightRemoved
+	}
+	return visualizer.HighlightChanged
+}
+
+// MergedModel returns a model containing the elements of both versions, so removed
+// services, domains, datastores and interactions can still be drawn next to the new ones
+func (d *ModelDiff) MergedModel() *parser.DSLModel {
+	after := newSnapshot(d.newModel)
+	removedDomains := make(map[string][]string) // surviving service -> removed domains
+	removedDataStores := make(map[string][]string)
+	removedServices := make([]string, 0)
+
+	for _, change := range d.Changes {
+		if change.Kind != ChangeRemoved {
+			continue
+		}
+		switch change.Element {
+		case ElementService:
+			removedServices = append(removedServices, change.Name)
+		case ElementDomain:
+			removedDomains[change.Service] = append(removedDomains[change.Service], change.Name)
+		case ElementDataStore:
+			removedDataStores[change.Service] = append(removedDataStores[change.Service], change.Name)
+		}
+	}
+
+	merged := &parser.DSLModel{
+		Architectures: d.newModel.Architectures,
+		Exposures:     d.newModel.Exposures,
+		Domains:       d.newModel.Domains,
+		Services:      make([]parser.Service, 0, len(d.newModel.Services)+len(removedServices)),
+		UseCases:      make([]parser.UseCase, 0, len(d.newModel.UseCases)+len(d.oldModel.UseCases)),
+		Actors:        append([]parser.Actor(nil), d.newModel.Actors...),
+	}
+
+	for _, service := range d.newModel.Services {
+		service.Domains = append(slices.Clone(service.Domains), removedDomains[service.Name]...)
+		service.DataStores = append(slices.Clone(service.DataStores), removedDataStores[service.Name]...)
+		merged.Services = append(merged.Services, service)
+	}
+
+	for _, service := range d.oldModel.Services {
+		if !slices.Contains(removedServices, service.Name) {
+			continue
+		}
+		// Domains that moved to another service are drawn in their new home only
+		domains := make([]string, 0, len(service.Domains))
+		for _, domain := range service.Domains {
+			if _, stillOwned := after.domainOwners[domain]; !stillOwned {
+				domains = append(domains, domain)
+			}
+		}
+		service.Domains = domains
+		merged.Services = append(merged.Services, service)
+	}
+
+	// Diagrams deduplicate relationships, so replaying both versions yields their union
+	merged.UseCases = append(merged.UseCases, d.newModel.UseCases...)
+	merged.UseCases = append(merged.UseCases, d.oldModel.UseCases...)
+
+	for _, actor := range d.oldModel.Actors {
+		if !slices.ContainsFunc(merged.Actors, func(a parser.Actor) bool { return a.Name == actor.Name }) {
+			merged.Actors = append(merged.Actors, actor)
+		}
+	}
+
+	return merged
+}
+
+// =============================================================================
+// Snapshot
+// =============================================================================
+
+// snapshot indexes the elements of a model that take part in the comparison
+type snapshot struct {
+	services     map[string]parser.Service
+	domainOwners map[string]string          // domain -> first service declaring it
+	dependencies map[[2]string]bool         // caller domain, callee domain
+	publishers   map[string][]string        // event -> publishing domains
+	consumers    map[string]map[string]bool // event -> listening domains
+}
+
+func newSnapshot(model *parser.DSLModel) *snapshot {
+	s := &snapshot{
+		services:     make(map[string]parser.Service),
+		domainOwners: make(map[string]string),
+		dependencies: make(map[[2]string]bool),
+		publishers:   make(map[string][]string),
+		consumers:    make(map[string]map[string]bool),
+	}
+
+	for _, service := range model.Services {
+		s.services[service.Name] = service
+	}
+
+	modelGraph := graph.Build(model)
+	for _, owns := range modelGraph.Edges(graph.EdgeOwns) {
+		if owns.To.Kind() == graph.NodeDomain {
+			s.domainOwners[owns.To.Name()] = modelGraph.ServiceOf(owns.To.Name())
+		}
+	}
+
+	for _, call := range modelGraph.Edges(graph.EdgeSync) {
+		if call.From != call.To {
+			s.dependencies[[2]string{call.From.Name(), call.To.Name()}] = true
+		}
+	}
+
+	for _, event := range modelGraph.Nodes(graph.NodeEvent) {
+		if publishers := modelGraph.Publishers(event.Name); len(publishers) > 0 {
+			s.publishers[event.Name] = publishers
+		}
+		for _, consumer := range modelGraph.Consumers(event.Name) {
+			if s.consumers[event.Name] == nil {
+				s.consumers[event.Name] = make(map[string]bool)
+			}
+			s.consumers[event.Name][consumer] = true
+		}
+	}
+
+	return s
+}
+
+func (s *snapshot) hasService(name string) bool {
+	_, exists := s.services[name]
+	return exists
+}
@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+const oldShop = `services {
+  OrderService {
+    domains: Orders, Billing
+    data-stores: orders_db
+  }
+  LegacyService {
+    domains: Coupons
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Coupons to apply coupon
+    Orders notifies "Order Placed"
+
+  when Billing listens "Order Placed"
+    Billing notifies "Invoice Sent"
+}
+`
+
+const newShop = `services {
+  OrderService {
+    domains: Orders, Carts
+    data-stores: orders_db, orders_cache
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Billing to charge card
+    Orders notifies "Order Placed"
+
+  when Carts listens "Order Placed"
+    Carts clears cart
+}
+`
+
+func parseModel(t *testing.T, dsl string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(dsl)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestCompare_Changes(t *testing.T) {
+	d := Compare(parseModel(t, oldShop), parseModel(t, newShop))
+
+	expected := []Change{
+		{Kind: ChangeAdded, Element: ElementService, Name: "BillingService"},
+		{Kind: ChangeRemoved, Element: ElementService, Name: "LegacyService"},
+		{Kind: ChangeAdded, Element: ElementDomain, Name: "Carts", Service: "OrderService"},
+		{Kind: ChangeMoved, Element: ElementDomain, Name: "Billing", Service: "BillingService", From: "OrderService", To: "BillingService"},
+		{Kind: ChangeAdded, Element: ElementDataStore, Name: "orders_cache", Service: "OrderService"},
+		{Kind: ChangeAdded, Element: ElementDependency, Name: "Orders -> Billing", From: "Orders", To: "Billing"},
+		{Kind: ChangeRemoved, Element: ElementDependency, Name: "Orders -> Coupons", From: "Orders", To: "Coupons"},
+		{Kind: ChangeRemoved, Element: ElementEvent, Name: "Invoice Sent"},
+		{Kind: ChangeAdded, Element: ElementConsumer, Name: "Order Placed", From: "Order Placed", To: "Carts"},
+		{Kind: ChangeRemoved, Element: ElementConsumer, Name: "Order Placed", From: "Order Placed", To: "Billing"},
+	}
+
+	if len(d.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(d.Changes), d.Changes)
+	}
+	for i, change := range expected {
+		if d.Changes[i] != change {
+			t.Errorf("Change %d: expected %+v, got %+v", i, change, d.Changes[i])
+		}
+	}
+}
+
+func TestCompare_IdenticalModels(t *testing.T) {
+	d := Compare(parseModel(t, oldShop), parseModel(t, oldShop))
+
+	if !d.IsEmpty() {
+		t.Errorf("Expected no changes, got %+v", d.Changes)
+	}
+	if d.Text() != "No architectural changes\n" {
+		t.Errorf("Unexpected text output: %q", d.Text())
+	}
+}
+
+func TestModelDiff_Text(t *testing.T) {
+	text := Compare(parseModel(t, oldShop), parseModel(t, newShop)).Text()
+
+	for _, expected := range []string{
+		"Services:\n  + BillingService\n  - LegacyService\n",
+		"  ~ Billing: OrderService -> BillingService\n",
+		"Data stores:\n  + orders_cache (OrderService)\n",
+		"Sync dependencies:\n  + Orders -> Billing\n  - Orders -> Coupons\n",
+		"Events:\n  - \"Invoice Sent\"\n",
+		"Consumers:\n  + \"Order Placed\" -> Carts\n  - \"Order Placed\" -> Billing\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected text to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestModelDiff_JSON(t *testing.T) {
+	content, err := Compare(parseModel(t, oldShop), parseModel(t, newShop)).JSON()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var decoded struct {
+		Changes []Change `json:"changes"`
+	}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if len(decoded.Changes) != 10 || decoded.Changes[0].Kind != ChangeAdded {
+		t.Errorf("Unexpected decoded changes %+v", decoded.Changes)
+	}
+}
+
+func TestModelDiff_MergedModel(t *testing.T) {
+	merged := Compare(parseModel(t, oldShop), parseModel(t, newShop)).MergedModel()
+
+	if len(merged.Services) != 3 || merged.Services[2].Name != "LegacyService" {
+		t.Fatalf("Expected removed LegacyService to be appended, got %+v", merged.Services)
+	}
+	if len(merged.UseCases) != 2 {
+		t.Errorf("Expected use cases of both versions, got %d", len(merged.UseCases))
+	}
+}
+
+func TestModelDiff_HighlightedDiagram(t *testing.T) {
+	d := Compare(parseModel(t, oldShop), parseModel(t, newShop))
+
+	generator := visualizer.NewC4DiagramGenerator(visualizer.C4ModeBoundaries, true)
+	generator.SetHighlights(d.Highlights())
+	diagram := generator.GenerateC4Diagram(d.MergedModel(), visualizer.C4Containers)
+
+	for _, expected := range []string{
+		`AddElementTag("added"`,
+		`AddRelTag("removed"`,
+		`System_Boundary(BillingService_boundary, "BillingService", $tags="added")`,
+		`System_Boundary(LegacyService_boundary, "LegacyService", $tags="removed")`,
+		`Rel(Orders, Billing, "charge card", "Service API", $tags="added")`,
+		`Rel(Orders, Coupons, "apply coupon", "Service API", $tags="removed")`,
+	} {
+		if !strings.Contains(diagram, expected) {
+			t.Errorf("Expected diagram to contain %s, got:\n%s", expected, diagram)
+		}
+	}
+}
+
+func TestChange_Sentence(t *testing.T) {
+	cases := map[string]Change{
+		"Billing now depends on Ledger (sync)":                {Kind: ChangeAdded, Element: ElementDependency, From: "Billing", To: "Ledger"},
+		"Event 'Funds Reserved' gained consumer Notification": {Kind: ChangeAdded, Element: ElementConsumer, Name: "Funds Reserved", To: "Notification"},
+		"Domain Billing moved from Orders to Payments":        {Kind: ChangeMoved, Element: ElementDomain, Name: "Billing", From: "Orders", To: "Payments"},
+		"Service Legacy removed":                              {Kind: ChangeRemoved, Element: ElementService, Name: "Legacy"},
+	}
+
+	for expected, change := range cases {
+		if got := change.Sentence(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
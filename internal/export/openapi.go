@@ -0,0 +1,333 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// OpenAPIDocument represents the subset of an OpenAPI 3 document generated from a Craft model
+type OpenAPIDocument struct {
+	OpenAPI string               `json:"openapi"`
+	Info    OpenAPIInfo          `json:"info"`
+	Tags    []OpenAPITag         `json:"tags,omitempty"`
+	Paths   map[string]*PathItem `json:"paths"`
+}
+
+// OpenAPIInfo holds the document metadata
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPITag describes a tag used to group operations
+type OpenAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations available on a single path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation represents a single API operation skeleton
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags"`
+	Responses   map[string]*Response `json:"responses"`
+	Callers     []string             `json:"x-craft-callers,omitempty"`  // Domains or actors invoking the operation
+	Gateways    []string             `json:"x-craft-gateways,omitempty"` // Gateways from the exposure "through:" list
+	Exposures   []string             `json:"x-craft-exposures,omitempty"`
+}
+
+// Response represents an operation response
+type Response struct {
+	Description string `json:"description"`
+}
+
+// OpenAPIExporter derives OpenAPI skeletons from sync interactions and exposures
+type OpenAPIExporter struct {
+	model *parser.DSLModel
+	graph *graph.Graph
+}
+
+// NewOpenAPIExporter creates a new exporter for the given model
+func NewOpenAPIExporter(model *parser.DSLModel) *OpenAPIExporter {
+	return &OpenAPIExporter{model: model, graph: graph.Build(model)}
+}
+
+// GenerateAll builds one OpenAPI document per service, keyed by service name
+func (e *OpenAPIExporter) GenerateAll() map[string]*OpenAPIDocument {
+	documents := make(map[string]*OpenAPIDocument)
+	for _, service := range e.model.Services {
+		documents[service.Name] = e.Generate(service)
+	}
+	return documents
+}
+
+// Generate builds the OpenAPI document for a single service
+func (e *OpenAPIExporter) Generate(service parser.Service) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: OpenAPIInfo{
+			Title:       fmt.Sprintf("%s API", service.Name),
+			Description: fmt.Sprintf("Skeleton generated from Craft use cases for domains: %s", strings.Join(service.Domains, ", ")),
+			Version:     "0.1.0",
+		},
+		Tags:  make([]OpenAPITag, 0),
+		Paths: make(map[string]*PathItem),
+	}
+
+	tags := make(map[string]string)
+
+	// Operations reached by external triggers routed through an exposure
+	for _, useCase := range e.model.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			if scenario.Trigger.Type == parser.TriggerTypeExternal {
+				e.addExternalOperation(doc, tags, service, useCase, scenario)
+			}
+		}
+	}
+
+	// Operations reached by inbound "asks" from other services
+	for _, call := range e.graph.Edges(graph.EdgeSync) {
+		caller, target := call.From.Name(), call.To.Name()
+		if !slices.Contains(service.Domains, target) {
+			continue
+		}
+		if e.graph.ServiceOf(caller) == service.Name {
+			// Calls inside the same service are not part of its API surface
+			continue
+		}
+
+		e.addOperation(doc, tags, operationSpec{
+			domain:  target,
+			phrase:  call.Label,
+			useCase: call.UseCase,
+			caller:  caller,
+		})
+	}
+
+	tagNames := make([]string, 0, len(tags))
+	for name := range tags {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+	for _, name := range tagNames {
+		doc.Tags = append(doc.Tags, OpenAPITag{Name: name, Description: tags[name]})
+	}
+
+	return doc
+}
+
+// addExternalOperation adds the operation invoked by an external trigger if an exposure routes it
+func (e *OpenAPIExporter) addExternalOperation(doc *OpenAPIDocument, tags map[string]string, service parser.Service, useCase parser.UseCase, scenario parser.Scenario) {
+	entryDomain := graph.EntryDomain(scenario)
+	if entryDomain == "" || !slices.Contains(service.Domains, entryDomain) {
+		return
+	}
+
+	exposures := make([]string, 0)
+	gateways := make([]string, 0)
+	for _, exposure := range e.model.Exposures {
+		if !slices.Contains(exposure.Of, entryDomain) {
+			continue
+		}
+		exposures = append(exposures, exposure.Name)
+		for _, gateway := range exposure.Through {
+			if !slices.Contains(gateways, gateway) {
+				gateways = append(gateways, gateway)
+			}
+		}
+	}
+	if len(exposures) == 0 {
+		return
+	}
+
+	phrase := strings.TrimSpace(scenario.Trigger.Verb + " " + scenario.Trigger.Phrase)
+	e.addOperation(doc, tags, operationSpec{
+		domain:    entryDomain,
+		phrase:    phrase,
+		useCase:   useCase.Name,
+		caller:    scenario.Trigger.Actor,
+		exposures: exposures,
+		gateways:  gateways,
+	})
+}
+
+// operationSpec captures the information needed to add or merge an operation
+type operationSpec struct {
+	domain    string
+	phrase    string
+	useCase   string
+	caller    string
+	exposures []string
+	gateways  []string
+}
+
+// addOperation adds a new operation or merges the spec into an existing one on the same path and method
+func (e *OpenAPIExporter) addOperation(doc *OpenAPIDocument, tags map[string]string, spec operationSpec) {
+	if spec.domain == "" || spec.phrase == "" {
+		return
+	}
+
+	path := fmt.Sprintf("/%s/%s", toKebabCase(spec.domain), toKebabCase(spec.phrase))
+	method := inferHTTPMethod(spec.phrase)
+
+	item := doc.Paths[path]
+	if item == nil {
+		item = &PathItem{}
+		doc.Paths[path] = item
+	}
+
+	operation := item.operation(method)
+	if operation == nil {
+		operation = &Operation{
+			OperationID: toCamelCase(spec.domain + " " + spec.phrase),
+			Summary:     spec.phrase,
+			Tags:        []string{spec.domain},
+			Responses: map[string]*Response{
+				"200": {Description: "Successful response"},
+			},
+		}
+		item.setOperation(method, operation)
+	}
+
+	operation.Tags = appendUnique(operation.Tags, spec.useCase)
+	operation.Callers = appendUnique(operation.Callers, spec.caller)
+	for _, exposure := range spec.exposures {
+		operation.Exposures = appendUnique(operation.Exposures, exposure)
+	}
+	for _, gateway := range spec.gateways {
+		operation.Gateways = appendUnique(operation.Gateways, gateway)
+	}
+	operation.Description = fmt.Sprintf("Called by %s in: %s",
+		strings.Join(operation.Callers, ", "), strings.Join(operation.Tags[1:], ", "))
+
+	tags[spec.domain] = "Domain"
+	tags[spec.useCase] = "Use case"
+}
+
+// operation returns the operation registered for the HTTP method
+func (p *PathItem) operation(method string) *Operation {
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "delete":
+		return p.Delete
+	default:
+		return p.Post
+	}
+}
+
+// setOperation registers the operation for the HTTP method
+func (p *PathItem) setOperation(method string, operation *Operation) {
+	switch method {
+	case "get":
+		p.Get = operation
+	case "put":
+		p.Put = operation
+	case "delete":
+		p.Delete = operation
+	default:
+		p.Post = operation
+	}
+}
+
+// MarshalOpenAPI renders the document as indented JSON
+func MarshalOpenAPI(doc *OpenAPIDocument) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// inferHTTPMethod guesses the HTTP method from the leading verb of a phrase
+func inferHTTPMethod(phrase string) string {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) == 0 {
+		return "post"
+	}
+
+	switch words[0] {
+	case "get", "gets", "fetch", "fetches", "list", "lists", "find", "finds", "check", "checks",
+		"verify", "verifies", "lookup", "search", "searches", "read", "reads", "retrieve", "retrieves":
+		return "get"
+	case "update", "updates", "change", "changes", "modify", "modifies", "replace", "replaces":
+		return "put"
+	case "delete", "deletes", "remove", "removes", "cancel", "cancels":
+		return "delete"
+	}
+	return "post"
+}
+
+// toKebabCase converts names and phrases into URL path segments
+func toKebabCase(text string) string {
+	words := splitWords(text)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "-")
+}
+
+// toCamelCase converts names and phrases into operation identifiers
+func toCamelCase(text string) string {
+	words := splitWords(text)
+	var sb strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		sb.WriteString(word)
+	}
+	return sb.String()
+}
+
+// splitWords splits on separators and camel case boundaries
+func splitWords(text string) []string {
+	words := make([]string, 0)
+	var current []rune
+	runes := []rune(text)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// appendUnique appends item when it is not empty and not already present
+func appendUnique(items []string, item string) []string {
+	if item == "" || slices.Contains(items, item) {
+		return items
+	}
+	return append(items, item)
+}
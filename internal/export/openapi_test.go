@@ -0,0 +1,99 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// closure exposes PaymentProcessing to customers and adds a second use case asking for the same
+// account check
+const closure = `
+exposure PublicAPI {
+  to: Customer
+  of: PaymentProcessing
+  through: APIGateway
+}
+
+use_case "Account Closure" {
+  when Customer closes account
+    PaymentProcessing asks AccountManagement to verify source account
+}
+`
+
+// bankingModel parses the banking fixture shared by the package tests, with the closure added
+func bankingModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	source, err := os.ReadFile(filepath.Join("..", "..", "testdata", "banking.craft"))
+	if err != nil {
+		t.Fatalf("Failed to read banking fixture: %v", err)
+	}
+	model, err := parser.ParseDSLToModel(string(source) + closure)
+	if err != nil {
+		t.Fatalf("Failed to parse banking fixture: %v", err)
+	}
+	return model
+}
+
+func TestOpenAPIExporter_InboundAsks(t *testing.T) {
+	documents := NewOpenAPIExporter(bankingModel(t)).GenerateAll()
+
+	doc := documents["AccountService"]
+	if doc == nil {
+		t.Fatal("Expected document for AccountService")
+	}
+
+	item := doc.Paths["/account-management/verify-source-account"]
+	if item == nil || item.Get == nil {
+		t.Fatalf("Expected GET operation for verify source account, got %+v", doc.Paths)
+	}
+
+	op := item.Get
+	if op.OperationID != "accountManagementVerifySourceAccount" {
+		t.Errorf("Unexpected operationId '%s'", op.OperationID)
+	}
+
+	expectedTags := []string{"AccountManagement", "Money Transfer", "Account Closure"}
+	if !slices.Equal(op.Tags, expectedTags) {
+		t.Errorf("Expected tags %v, got %v", expectedTags, op.Tags)
+	}
+
+	if !slices.Equal(op.Callers, []string{"PaymentProcessing"}) {
+		t.Errorf("Expected caller PaymentProcessing, got %v", op.Callers)
+	}
+}
+
+func TestOpenAPIExporter_SkipsIntraServiceCalls(t *testing.T) {
+	doc := NewOpenAPIExporter(bankingModel(t)).GenerateAll()["PaymentService"]
+
+	if _, exists := doc.Paths["/transaction-validation/check-transfer-limits"]; exists {
+		t.Error("Expected calls within PaymentService not to become operations")
+	}
+}
+
+func TestOpenAPIExporter_ExternalTriggersThroughExposures(t *testing.T) {
+	doc := NewOpenAPIExporter(bankingModel(t)).GenerateAll()["PaymentService"]
+
+	item := doc.Paths["/payment-processing/initiates-transfer"]
+	if item == nil || item.Post == nil {
+		t.Fatalf("Expected POST operation for external trigger, got %+v", doc.Paths)
+	}
+
+	if !slices.Equal(item.Post.Gateways, []string{"APIGateway"}) {
+		t.Errorf("Expected gateway APIGateway, got %v", item.Post.Gateways)
+	}
+	if !slices.Equal(item.Post.Exposures, []string{"PublicAPI"}) {
+		t.Errorf("Expected exposure PublicAPI, got %v", item.Post.Exposures)
+	}
+	if !slices.Equal(item.Post.Callers, []string{"Customer"}) {
+		t.Errorf("Expected caller Customer, got %v", item.Post.Callers)
+	}
+
+	// "closes account" enters through PaymentProcessing as well
+	if doc.Paths["/payment-processing/closes-account"] == nil {
+		t.Error("Expected operation for second external trigger")
+	}
+}
@@ -0,0 +1,104 @@
+package exposure
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Issue codes
+const (
+	CodeUnexposedDomain = "unexposed_domain"
+	CodeUnknownExposure = "unknown_exposure"
+	CodeNotExposedTo    = "exposure_not_open_to_actor"
+)
+
+// Issue is an actor reaching a domain no exposure opens to it, or an actor using an exposure it cannot go through
+type Issue struct {
+	Actor   string `json:"actor"`
+	UseCase string `json:"useCase,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validate checks that every external trigger enters the model at a domain exposed to its actor, that is
+// listed in the of: of an exposure whose to: names the actor. An exposure of a top-level domain covers
+// its subdomains. It also checks that the exposures an actor uses exist and are open to it. Models
+// without exposures are not checked.
+func Validate(model *parser.DSLModel) []Issue {
+	issues := make([]Issue, 0)
+	if len(model.Exposures) == 0 {
+		return issues
+	}
+
+	for _, actor := range model.Actors {
+		for _, name := range actor.Uses {
+			exposure := find(model, name)
+			switch {
+			case exposure == nil:
+				issues = append(issues, Issue{
+					Actor:   actor.Name,
+					Line:    actor.Line,
+					Code:    CodeUnknownExposure,
+					Message: fmt.Sprintf("actor '%s' uses unknown exposure %s", actor.Name, name),
+				})
+			case !slices.Contains(exposure.To, actor.Name):
+				issues = append(issues, Issue{
+					Actor:   actor.Name,
+					Line:    actor.Line,
+					Code:    CodeNotExposedTo,
+					Message: fmt.Sprintf("actor '%s' uses exposure %s, which is not exposed to it", actor.Name, name),
+				})
+			}
+		}
+	}
+
+	for _, useCase := range model.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			trigger := scenario.Trigger
+			entry := graph.EntryDomain(scenario)
+			if trigger.Type != parser.TriggerTypeExternal || trigger.Actor == "" || entry == "" {
+				continue
+			}
+			if Exposed(model, trigger.Actor, entry) {
+				continue
+			}
+
+			issues = append(issues, Issue{
+				Actor:   trigger.Actor,
+				UseCase: useCase.Name,
+				Line:    trigger.Line,
+				Code:    CodeUnexposedDomain,
+				Message: fmt.Sprintf("actor '%s' reaches domain '%s' in use case '%s', but no exposure opens it to the actor", trigger.Actor, entry, useCase.Name),
+			})
+		}
+	}
+
+	return issues
+}
+
+// Exposed reports whether an exposure opens a domain, or its top-level domain, to an actor
+func Exposed(model *parser.DSLModel, actor, domain string) bool {
+	parent := model.ParentDomain(domain)
+	for _, exposure := range model.Exposures {
+		if !slices.Contains(exposure.To, actor) {
+			continue
+		}
+		if slices.Contains(exposure.Of, domain) || (parent != "" && slices.Contains(exposure.Of, parent)) {
+			return true
+		}
+	}
+	return false
+}
+
+func find(model *parser.DSLModel, name string) *parser.Exposure {
+	for i := range model.Exposures {
+		if model.Exposures[i].Name == name {
+			return &model.Exposures[i]
+		}
+	}
+	return nil
+}
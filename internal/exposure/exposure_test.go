@@ -0,0 +1,98 @@
+package exposure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const shop = `actor user Customer {
+  uses: PublicAPI
+}
+actor user Clerk {
+  uses: PublicAPI, BackOffice
+}
+
+domains {
+  Sales {
+    Orders
+    Carts
+  }
+}
+
+exposure PublicAPI {
+  to: Customer
+  of: Sales
+}
+
+exposure AdminAPI {
+  to: Clerk
+  of: Billing
+}
+
+use_case "Shop" {
+  when Customer opens page
+    Orders loads data
+
+  when Customer opens page
+    Billing loads data
+}
+
+use_case "Refund" {
+  when Clerk opens page
+    Billing loads data
+
+  when Clerk opens page
+    Carts loads data
+}
+
+use_case "Nightly" {
+  when Billing listens "Day Closed"
+    Carts purges carts
+}
+`
+
+func shopModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(shop)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestValidate(t *testing.T) {
+	expected := []Issue{
+		{Actor: "Clerk", Line: 4, Code: CodeNotExposedTo, Message: "actor 'Clerk' uses exposure PublicAPI, which is not exposed to it"},
+		{Actor: "Clerk", Line: 4, Code: CodeUnknownExposure, Message: "actor 'Clerk' uses unknown exposure BackOffice"},
+		{Actor: "Customer", UseCase: "Shop", Line: 29, Code: CodeUnexposedDomain, Message: "actor 'Customer' reaches domain 'Billing' in use case 'Shop', but no exposure opens it to the actor"},
+		{Actor: "Clerk", UseCase: "Refund", Line: 37, Code: CodeUnexposedDomain, Message: "actor 'Clerk' reaches domain 'Carts' in use case 'Refund', but no exposure opens it to the actor"},
+	}
+	if issues := Validate(shopModel(t)); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Unexpected issues:\nExpected %+v\nGot      %+v", expected, issues)
+	}
+}
+
+func TestValidate_NoExposures(t *testing.T) {
+	model := shopModel(t)
+	model.Exposures = nil
+
+	if issues := Validate(model); len(issues) != 0 {
+		t.Errorf("Expected models without exposures to go unchecked, got %+v", issues)
+	}
+}
+
+func TestExposed(t *testing.T) {
+	model := shopModel(t)
+
+	if !Exposed(model, "Customer", "Carts") {
+		t.Errorf("Expected the exposure of Sales to cover its subdomain Carts")
+	}
+	if Exposed(model, "Customer", "Billing") {
+		t.Errorf("Expected Billing not to be exposed to Customer")
+	}
+	if Exposed(model, "Visitor", "Orders") {
+		t.Errorf("Expected nothing to be exposed to an actor no exposure names")
+	}
+}
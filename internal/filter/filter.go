@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Filter keys
+const (
+	KeyOwner   = "owner"
+	KeyTag     = "tag"
+	KeyUseCase = "usecase"
+	KeyActor   = "actor"
+	KeyEnv     = "env"
+)
+
+// Filter narrows a model before generation. Conditions on the same key match any of their values, and
+// conditions on different keys must all match.
+type Filter struct {
+	Owners   []string `json:"owners,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	UseCases []string `json:"useCases,omitempty"`
+	Actors   []string `json:"actors,omitempty"`
+	Env      string   `json:"env,omitempty"`
+}
+
+// Parse reads a filter expression of key=value conditions separated by spaces or commas, e.g.
+// owner=team-payments tag=pci usecase="Money Transfer". Values holding spaces or commas are quoted.
+// An empty expression gives an empty filter.
+func Parse(expression string) (*Filter, error) {
+	f := &Filter{}
+	conditions, err := split(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range conditions {
+		key, value, found := strings.Cut(condition, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = unquote(strings.TrimSpace(value))
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid filter condition %q, expected key=value", condition)
+		}
+
+		switch key {
+		case KeyOwner:
+			f.Owners = append(f.Owners, value)
+		case KeyTag:
+			f.Tags = append(f.Tags, value)
+		case KeyUseCase, "use_case":
+			f.UseCases = append(f.UseCases, value)
+		case KeyActor:
+			f.Actors = append(f.Actors, value)
+		case KeyEnv:
+			if f.Env != "" && f.Env != value {
+				return nil, fmt.Errorf("filter selects environments %s and %s", f.Env, value)
+			}
+			f.Env = value
+		default:
+			return nil, fmt.Errorf("unknown filter key %q, expected one of owner, tag, usecase, actor, env", key)
+		}
+	}
+	return f, nil
+}
+
+// IsEmpty reports whether the filter keeps the whole model
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.Owners) == 0 && len(f.Tags) == 0 && len(f.UseCases) == 0 && len(f.Actors) == 0 && f.Env == "")
+}
+
+// Apply returns the model resolved for the filter's environment and narrowed to its conditions:
+// owner and tag keep the elements whose metadata matches, as parser.DSLModel.Narrow does; usecase keeps
+// the named use cases, or one scenario with "Name#scenario", and actor the scenarios the actors
+// trigger, with the scenarios listening to their events. Services are then narrowed to those owning a
+// domain of a kept scenario.
+func (f *Filter) Apply(model *parser.DSLModel) (*parser.DSLModel, error) {
+	if f.IsEmpty() {
+		return model, nil
+	}
+
+	if f.Env != "" {
+		resolved, err := model.ForEnvironment(f.Env)
+		if err != nil {
+			return nil, err
+		}
+		model = resolved
+	}
+
+	if len(f.Owners) > 0 || len(f.Tags) > 0 {
+		model = model.Narrow(f.matches)
+	}
+
+	if len(f.UseCases) > 0 || len(f.Actors) > 0 {
+		narrowed, err := f.narrowFlows(model)
+		if err != nil {
+			return nil, err
+		}
+		model = narrowed
+	}
+	return model, nil
+}
+
+// matches reports whether metadata satisfies the owner and tag conditions
+func (f *Filter) matches(metadata parser.Metadata) bool {
+	if len(f.Owners) > 0 && !slices.Contains(f.Owners, metadata.Owner()) {
+		return false
+	}
+	return len(f.Tags) == 0 || metadata.HasTag(f.Tags...)
+}
+
+// narrowFlows keeps the selected use cases and the scenarios of the selected actors, then the services
+// and actors taking part in them
+func (f *Filter) narrowFlows(model *parser.DSLModel) (*parser.DSLModel, error) {
+	for _, name := range f.Actors {
+		if model.Actor(name) == nil && !triggeredBy(model, name) {
+			return nil, fmt.Errorf("unknown actor %s", name)
+		}
+	}
+
+	useCases := model.UseCases
+	if len(f.UseCases) > 0 {
+		selections := make([]parser.UseCaseSelection, 0, len(f.UseCases))
+		for _, value := range f.UseCases {
+			selections = append(selections, parser.ParseUseCaseSelection(value))
+		}
+		selected, err := model.SelectUseCases(selections...)
+		if err != nil {
+			return nil, err
+		}
+		useCases = selected
+	}
+
+	if len(f.Actors) > 0 {
+		useCases = f.actorUseCases(useCases)
+	}
+	return model.WithFlows(useCases), nil
+}
+
+// actorUseCases keeps the scenarios triggered by the selected actors, with the scenarios listening to
+// the events they notify, transitively and across use cases, then the use cases left with scenarios
+func (f *Filter) actorUseCases(useCases []parser.UseCase) []parser.UseCase {
+	kept := make([][]bool, len(useCases))
+	for i, useCase := range useCases {
+		kept[i] = make([]bool, len(useCase.Scenarios))
+	}
+
+	events := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for i, useCase := range useCases {
+			for j, scenario := range useCase.Scenarios {
+				if kept[i][j] {
+					continue
+				}
+				external := scenario.Trigger.Type == parser.TriggerTypeExternal && slices.Contains(f.Actors, scenario.Trigger.Actor)
+				if !external && (scenario.Trigger.Event == "" || !events[scenario.Trigger.Event]) {
+					continue
+				}
+				kept[i][j] = true
+				changed = true
+				for _, action := range scenario.Actions {
+					if action.Type == parser.ActionTypeAsync {
+						events[action.Event] = true
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]parser.UseCase, 0)
+	for i, useCase := range useCases {
+		scenarios := make([]parser.Scenario, 0)
+		for j, scenario := range useCase.Scenarios {
+			if kept[i][j] {
+				scenarios = append(scenarios, scenario)
+			}
+		}
+		if len(scenarios) > 0 {
+			useCase.Scenarios = scenarios
+			result = append(result, useCase)
+		}
+	}
+	return result
+}
+
+// triggeredBy reports whether any scenario of the model is triggered by the actor
+func triggeredBy(model *parser.DSLModel, actor string) bool {
+	for _, useCase := range model.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			if scenario.Trigger.Type == parser.TriggerTypeExternal && scenario.Trigger.Actor == actor {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// split breaks an expression into conditions at spaces and commas outside double quotes
+func split(expression string) ([]string, error) {
+	conditions := make([]string, 0)
+	var current strings.Builder
+	quoted := false
+	for _, r := range expression {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == ','):
+			if current.Len() > 0 {
+				conditions = append(conditions, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in filter %q", expression)
+	}
+	if current.Len() > 0 {
+		conditions = append(conditions, current.String())
+	}
+	return conditions, nil
+}
+
+// unquote strips the double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const bank = `actor user Customer
+actor user Auditor
+
+services {
+  @owner(team-payments) @tag(pci)
+  PaymentService {
+    domains: Payments
+    data-stores: payment_db
+  }
+  @owner(team-core)
+  AccountService {
+    domains: Accounts
+  }
+  @owner(team-core) @tag(pci)
+  ReportService {
+    domains: Reports
+    data-stores: payment_db
+  }
+}
+
+datastores {
+  payment_db {
+    type: postgres
+  }
+}
+
+environment prod {
+  service AccountService {
+    language: java
+  }
+}
+
+use_case "Money Transfer" {
+  when Customer sends money
+    Payments asks Accounts to debit account
+    Payments notifies "Transfer Completed"
+
+  when Accounts listens "Transfer Completed"
+    Accounts updates balance
+}
+
+use_case "Audit" {
+  when Auditor requests report
+    Reports builds report
+}
+`
+
+func bankModel(t *testing.T, extra string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(bank + extra)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func serviceNames(model *parser.DSLModel) []string {
+	names := make([]string, 0)
+	for _, service := range model.Services {
+		names = append(names, service.Name)
+	}
+	return names
+}
+
+func useCaseNames(model *parser.DSLModel) []string {
+	names := make([]string, 0)
+	for _, useCase := range model.UseCases {
+		names = append(names, useCase.Name)
+	}
+	return names
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse(`owner=team-payments, tag=pci tag=gdpr usecase="Money Transfer" actor=Customer env=prod`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := &Filter{
+		Owners:   []string{"team-payments"},
+		Tags:     []string{"pci", "gdpr"},
+		UseCases: []string{"Money Transfer"},
+		Actors:   []string{"Customer"},
+		Env:      "prod",
+	}
+	if !reflect.DeepEqual(f, expected) {
+		t.Errorf("Unexpected filter:\nExpected %+v\nGot      %+v", expected, f)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	f, err := Parse("  ")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !f.IsEmpty() {
+		t.Errorf("Expected an empty filter, got %+v", f)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expression := range []string{"pci", "team=core", "tag=", `usecase="Money Transfer`, "env=prod env=staging"} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", expression)
+		}
+	}
+}
+
+func TestApply_OwnerAndTag(t *testing.T) {
+	model, err := (&Filter{Owners: []string{"team-core"}, Tags: []string{"pci"}}).Apply(bankModel(t, ""))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if names := serviceNames(model); !reflect.DeepEqual(names, []string{"ReportService"}) {
+		t.Errorf("Expected only the pci service of team-core, got %v", names)
+	}
+	if names := useCaseNames(model); !reflect.DeepEqual(names, []string{"Audit"}) {
+		t.Errorf("Expected only the use case involving ReportService, got %v", names)
+	}
+	if owner := model.DataStores[0].Owner; owner != "ReportService" {
+		t.Errorf("Expected payment_db to be relinked to ReportService, got %q", owner)
+	}
+}
+
+func TestApply_UseCase(t *testing.T) {
+	model, err := (&Filter{UseCases: []string{"Money Transfer"}}).Apply(bankModel(t, ""))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if names := useCaseNames(model); !reflect.DeepEqual(names, []string{"Money Transfer"}) {
+		t.Errorf("Expected only Money Transfer, got %v", names)
+	}
+	if names := serviceNames(model); !reflect.DeepEqual(names, []string{"PaymentService", "AccountService"}) {
+		t.Errorf("Expected the services taking part in Money Transfer, got %v", names)
+	}
+	if len(model.Actors) != 1 || model.Actors[0].Name != "Customer" {
+		t.Errorf("Expected only Customer to be kept, got %+v", model.Actors)
+	}
+}
+
+func TestApply_ActorFollowsEvents(t *testing.T) {
+	model, err := (&Filter{Actors: []string{"Customer"}}).Apply(bankModel(t, ""))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if names := useCaseNames(model); !reflect.DeepEqual(names, []string{"Money Transfer"}) {
+		t.Fatalf("Expected only the use case Customer triggers, got %v", names)
+	}
+	if scenarios := model.UseCases[0].Scenarios; len(scenarios) != 2 {
+		t.Errorf("Expected the listener of Transfer Completed to be kept, got %d scenarios", len(scenarios))
+	}
+}
+
+func TestApply_ActorFollowsEventsAcrossUseCases(t *testing.T) {
+	source := bankModel(t, `
+use_case "Statements" {
+  when Reports listens "Transfer Completed"
+    Reports records statement line
+}
+`)
+
+	model, err := (&Filter{Actors: []string{"Customer"}}).Apply(source)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if names := useCaseNames(model); !reflect.DeepEqual(names, []string{"Money Transfer", "Statements"}) {
+		t.Errorf("Expected the listener in Statements to be kept, got %v", names)
+	}
+}
+
+func TestApply_Env(t *testing.T) {
+	model, err := (&Filter{Env: "prod"}).Apply(bankModel(t, ""))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if language := model.Services[1].Language; language != "java" {
+		t.Errorf("Expected the prod override to apply, got language %q", language)
+	}
+}
+
+func TestApply_Unknown(t *testing.T) {
+	for _, f := range []*Filter{{UseCases: []string{"Refund"}}, {Actors: []string{"Clerk"}}, {Env: "qa"}} {
+		if _, err := f.Apply(bankModel(t, "")); err == nil {
+			t.Errorf("Expected %+v to fail", f)
+		}
+	}
+}
+
+func TestApply_Empty(t *testing.T) {
+	model := bankModel(t, "")
+	if got, err := (&Filter{}).Apply(model); err != nil || got != model {
+		t.Errorf("Expected an empty filter to return the model unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// commented is a code line of the source with its comments: the comment lines above it and the
+// comment at its end
+type commented struct {
+	code     int // Index of the code line among the source's code lines, -1 after the last one
+	leading  []string
+	trailing string
+}
+
+// FormatSource renders the model as canonical Craft source, keeping the comments of the source
+// it was parsed from. The grammar drops comments, so each one is placed back next to the
+// formatted line matching its code line; comments whose line is no longer found follow the line
+// of the previous comment placed.
+func FormatSource(model *parser.DSLModel, source string) string {
+	return KeepComments(source, Format(model))
+}
+
+// KeepComments places the comments of the source into its formatted version. Code lines are
+// matched in order first, so repeated lines keep their comments; lines the formatter moved, like
+// sections in another order, are then matched on their own.
+func KeepComments(source, formatted string) string {
+	lines := strings.Split(strings.TrimRight(formatted, "\n"), "\n")
+	code, comments := sourceComments(source, generatedComments(lines))
+	if len(comments) == 0 {
+		return formatted
+	}
+
+	normalized := make([]string, len(lines))
+	for i, line := range lines {
+		normalized[i] = normalize(line)
+	}
+	aligned := align(code, normalized)
+
+	before := make([][]string, len(lines))
+	after := make([][]string, len(lines)) // Comments whose line is gone, after the line placed before them
+	trailing := make([]string, len(lines))
+	opening, closing := make([]string, 0), make([]string, 0)
+	used := make([]bool, len(lines))
+	for _, index := range aligned {
+		if index >= 0 {
+			used[index] = true
+		}
+	}
+
+	cursor, previous := 0, -1
+	for _, comment := range comments {
+		if comment.code < 0 {
+			closing = append(closing, comment.leading...)
+			continue
+		}
+
+		index := aligned[comment.code]
+		if index < 0 {
+			index = findLine(normalized, used, code[comment.code], cursor)
+		}
+		if index < 0 {
+			lost := comment.leading
+			if comment.trailing != "" {
+				lost = append(lost, comment.trailing)
+			}
+			if previous < 0 {
+				opening = append(opening, lost...)
+			} else {
+				after[previous] = append(after[previous], lost...)
+			}
+			continue
+		}
+
+		used[index] = true
+		before[index] = append(before[index], comment.leading...)
+		trailing[index] = comment.trailing
+		cursor, previous = index, index
+	}
+
+	var sb strings.Builder
+	for _, text := range opening {
+		sb.WriteString(text + "\n")
+	}
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		for _, text := range before[i] {
+			sb.WriteString(indent + text + "\n")
+		}
+		sb.WriteString(line)
+		if trailing[i] != "" {
+			sb.WriteString(" " + trailing[i])
+		}
+		sb.WriteString("\n")
+		for _, text := range after[i] {
+			sb.WriteString(indent + text + "\n")
+		}
+	}
+	for _, text := range closing {
+		sb.WriteString(text + "\n")
+	}
+	return sb.String()
+}
+
+// sourceComments returns the normalized code lines of a Craft source and its commented code lines,
+// in order, leaving out the comments the formatter writes itself
+func sourceComments(source string, generated map[string]bool) ([]string, []commented) {
+	code := make([]string, 0)
+	comments := make([]commented, 0)
+	leading := make([]string, 0)
+
+	for _, line := range strings.Split(source, "\n") {
+		text, comment := splitComment(line)
+		if generated[comment] {
+			comment = ""
+		}
+
+		normalized := normalize(text)
+		if normalized == "" {
+			if comment != "" {
+				leading = append(leading, comment)
+			}
+			continue
+		}
+
+		code = append(code, normalized)
+		if len(leading) > 0 || comment != "" {
+			comments = append(comments, commented{code: len(code) - 1, leading: leading, trailing: comment})
+			leading = make([]string, 0)
+		}
+	}
+
+	if len(leading) > 0 {
+		comments = append(comments, commented{code: -1, leading: leading})
+	}
+	return code, comments
+}
+
+// generatedComments returns the comment lines of the formatted source
+func generatedComments(lines []string) map[string]bool {
+	generated := make(map[string]bool)
+	for _, line := range lines {
+		if text := strings.TrimSpace(line); strings.HasPrefix(text, "//") {
+			generated[text] = true
+		}
+	}
+	return generated
+}
+
+// splitComment splits a line at the // starting a comment, skipping // inside strings
+func splitComment(line string) (string, string) {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(line[i:], "//"):
+			return line[:i], strings.TrimSpace(line[i:])
+		}
+	}
+	return line, ""
+}
+
+// align matches the source code lines to formatted lines along their longest common subsequence,
+// returning the formatted line of each source line or -1
+func align(code, lines []string) []int {
+	width := len(lines) + 1
+	lengths := make([]int32, (len(code)+1)*width)
+	for i := len(code) - 1; i >= 0; i-- {
+		for j := len(lines) - 1; j >= 0; j-- {
+			switch {
+			case code[i] == lines[j]:
+				lengths[i*width+j] = lengths[(i+1)*width+j+1] + 1
+			case lengths[(i+1)*width+j] >= lengths[i*width+j+1]:
+				lengths[i*width+j] = lengths[(i+1)*width+j]
+			default:
+				lengths[i*width+j] = lengths[i*width+j+1]
+			}
+		}
+	}
+
+	aligned := make([]int, len(code))
+	i, j := 0, 0
+	for i < len(code) {
+		switch {
+		case j < len(lines) && code[i] == lines[j]:
+			aligned[i] = j
+			i, j = i+1, j+1
+		case j == len(lines) || lengths[(i+1)*width+j] >= lengths[i*width+j+1]:
+			aligned[i] = -1
+			i++
+		default:
+			j++
+		}
+	}
+	return aligned
+}
+
+// findLine returns the first unused line from the cursor, wrapping around, matching the code line
+func findLine(lines []string, used []bool, code string, cursor int) int {
+	for offset := range lines {
+		index := (cursor + offset) % len(lines)
+		if !used[index] && lines[index] == code {
+			return index
+		}
+	}
+	return -1
+}
+
+// normalize reduces a code line to what the formatter keeps: the formatter may respace, requote
+// and rejoin lists, so whitespace, quotes and commas are dropped
+func normalize(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '"', ',':
+			return -1
+		}
+		return r
+	}, code)
+}
@@ -0,0 +1,164 @@
+package formatter
+
+import (
+	"testing"
+)
+
+func TestKeepComments(t *testing.T) {
+	source := `// Shop model
+services {
+    OrderService {
+        domains: Orders,Carts   // carts move out next quarter
+        language: golang
+    }
+}
+
+use_case "Checkout" {
+    when Customer places order
+        // stock first
+        Orders asks Stock to reserve "items"
+        Orders notifies "Order Placed" // see https://wiki/orders
+        // Removed later
+        Orders asks Legacy to sync
+}
+// end of model
+`
+	formatted := `services {
+  OrderService {
+    domains: Orders, Carts
+    language: golang
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Stock to reserve items
+    Orders notifies "Order Placed"
+}
+`
+	expected := `// Shop model
+services {
+  OrderService {
+    domains: Orders, Carts // carts move out next quarter
+    language: golang
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    // stock first
+    Orders asks Stock to reserve items
+    Orders notifies "Order Placed" // see https://wiki/orders
+    // Removed later
+}
+// end of model
+`
+
+	if got := KeepComments(source, formatted); got != expected {
+		t.Errorf("Unexpected output.\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestKeepComments_RepeatedLinesAndStrings(t *testing.T) {
+	source := `use_case "A" {
+    when Customer opens "http://shop"
+        Orders validates cart
+}
+use_case "B" {
+    when Customer opens "http://shop"
+        // second validation
+        Orders validates cart
+}
+`
+	expected := `use_case "A" {
+  when Customer opens "http://shop"
+    Orders validates cart
+}
+use_case "B" {
+  when Customer opens "http://shop"
+    // second validation
+    Orders validates cart
+}
+`
+	formatted := `use_case "A" {
+  when Customer opens "http://shop"
+    Orders validates cart
+}
+use_case "B" {
+  when Customer opens "http://shop"
+    Orders validates cart
+}
+`
+
+	if got := KeepComments(source, formatted); got != expected {
+		t.Errorf("Unexpected output.\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestKeepComments_IsStableAcrossRuns(t *testing.T) {
+	formatted := `services {
+  Bare {
+    // Bare has no properties
+  }
+}
+`
+	source := `// Placeholder services
+services {
+  Bare {
+    // Bare has no properties
+  }
+}
+`
+	once := KeepComments(source, formatted)
+	if twice := KeepComments(once, formatted); twice != once {
+		t.Errorf("Expected formatting to be stable.\nFirst:\n%s\nSecond:\n%s", once, twice)
+	}
+	if once != source {
+		t.Errorf("Expected the generated comment once, got:\n%s", once)
+	}
+}
+
+func TestKeepComments_MovedSections(t *testing.T) {
+	source := `services {
+  // one service per team
+  OrderService {
+    domains: Orders
+  }
+}
+
+domains {
+  Shop {
+    Orders // was Sales
+  }
+}
+`
+	formatted := `domains {
+  Shop {
+    Orders
+  }
+}
+
+services {
+  OrderService {
+    domains: Orders
+  }
+}
+`
+	expected := `domains {
+  Shop {
+    Orders // was Sales
+  }
+}
+
+services {
+  // one service per team
+  OrderService {
+    domains: Orders
+  }
+}
+`
+
+	if got := KeepComments(source, formatted); got != expected {
+		t.Errorf("Unexpected output.\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// TestFormatSource_Examples formats the example models and checks that the result parses back to
+// the same model and keeps every comment
+func TestFormatSource_Examples(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "examples", "*.craft"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find examples: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", file, err)
+			}
+			source := string(content)
+
+			model, err := parser.NewParser().ParseString(source)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", file, err)
+			}
+			formatted := FormatSource(model, source)
+
+			reparsed, err := parser.NewParser().ParseString(formatted)
+			if err != nil {
+				t.Fatalf("Formatted source does not parse: %v\n%s", err, formatted)
+			}
+			if Format(reparsed) != Format(model) {
+				t.Errorf("Formatted source parses to another model:\n%s", formatted)
+			}
+			if again := FormatSource(reparsed, formatted); again != formatted {
+				t.Errorf("Formatting is not stable.\nFirst:\n%s\nSecond:\n%s", formatted, again)
+			}
+
+			for _, line := range strings.Split(source, "\n") {
+				if _, comment := splitComment(line); comment != "" && !strings.Contains(formatted, comment) {
+					t.Errorf("Comment %q was lost:\n%s", comment, formatted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,589 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const indentUnit = "  "
+
+// identifierPattern mirrors the IDENTIFIER lexer rule of the Craft grammar
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$`)
+
+// Formatter pretty-prints a DSL model as canonical Craft source
+type Formatter struct {
+	sb strings.Builder
+}
+
+// NewFormatter creates a new formatter instance
+func NewFormatter() *Formatter {
+	return &Formatter{}
+}
+
+// Format renders the model as canonical Craft source
+func Format(model *parser.DSLModel) string {
+	return NewFormatter().Format(model)
+}
+
+// Format renders the model as canonical Craft source.
+// Sections are emitted in a fixed order: actors, domains, context map, arch, exposures, services, datastores, environments,
+// use cases, sagas, rules.
+func (f *Formatter) Format(model *parser.DSLModel) string {
+	f.sb.Reset()
+
+	sections := []func(*parser.DSLModel){
+		f.writeActors,
+		f.writeDomains,
+		f.writeContextMap,
+		f.writeArchitectures,
+		f.writeExposures,
+		f.writeServices,
+		f.writeDataStores,
+		f.writeExternalSystems,
+		f.writeEnvironments,
+		f.writeUseCases,
+		f.writeSagas,
+		f.writeRules,
+	}
+
+	for _, section := range sections {
+		section(model)
+	}
+
+	return strings.TrimRight(f.sb.String(), "\n") + "\n"
+}
+
+// writeActors emits a single actors block
+func (f *Formatter) writeActors(model *parser.DSLModel) {
+	if len(model.Actors) == 0 {
+		return
+	}
+
+	f.sb.WriteString("actors {\n")
+	for _, actor := range model.Actors {
+		f.annotate(1, actor.Metadata)
+		if !hasActorDetails(actor) {
+			f.line(1, "%s %s", actor.Type, formatName(actor.Name))
+			continue
+		}
+
+		f.line(1, "%s %s {", actor.Type, formatName(actor.Name))
+		if actor.Description != "" {
+			f.line(2, "description: %s", quote(actor.Description))
+		}
+		if len(actor.Persona) > 0 {
+			f.line(2, "persona {")
+			for _, attribute := range actor.Persona {
+				value := attribute.Value
+				if !identifierPattern.MatchString(value) {
+					value = quote(value)
+				}
+				f.line(3, "%s: %s", formatName(attribute.Name), value)
+			}
+			f.line(2, "}")
+		}
+		if len(actor.Uses) > 0 {
+			f.line(2, "uses: %s", formatNameList(actor.Uses))
+		}
+		if len(actor.Roles) > 0 {
+			f.line(2, "roles: %s", formatNameList(actor.Roles))
+		}
+		if len(actor.Permissions) > 0 {
+			f.line(2, "permissions: %s", formatNameList(actor.Permissions))
+		}
+		f.line(1, "}")
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// hasActorDetails reports whether an actor needs a body for its description, persona, uses, roles or permissions
+func hasActorDetails(actor parser.Actor) bool {
+	return actor.Description != "" || len(actor.Persona) > 0 || len(actor.Uses) > 0 || len(actor.Roles) > 0 || len(actor.Permissions) > 0
+}
+
+// writeDomains emits a single domains block; domains without subdomains cannot be expressed and are skipped
+func (f *Formatter) writeDomains(model *parser.DSLModel) {
+	domains := make([]parser.Domain, 0, len(model.Domains))
+	for _, domain := range model.Domains {
+		if len(domain.SubDomains) > 0 {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return
+	}
+
+	f.sb.WriteString("domains {\n")
+	for _, domain := range domains {
+		f.annotate(1, domain.Metadata)
+		f.line(1, "%s {", formatName(domain.Name))
+
+		subDomains := append([]string(nil), domain.SubDomains...)
+		sort.Strings(subDomains)
+		for _, subDomain := range subDomains {
+			f.line(2, "%s", joinNonEmpty(formatMetadata(domain.SubDomainMetadata[subDomain]), formatName(subDomain)))
+		}
+
+		f.line(1, "}")
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// writeContextMap emits a single context_map block, relations in declaration order
+func (f *Formatter) writeContextMap(model *parser.DSLModel) {
+	if len(model.ContextMap) == 0 {
+		return
+	}
+
+	f.sb.WriteString("context_map {\n")
+	for _, relation := range model.ContextMap {
+		entry := fmt.Sprintf("%s -> %s", formatName(relation.From), formatName(relation.To))
+		if len(relation.Patterns) > 0 {
+			entry += " : " + strings.Join(relation.Patterns, ", ")
+		}
+		f.line(1, "%s", entry)
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// writeArchitectures emits one arch block per architecture
+func (f *Formatter) writeArchitectures(model *parser.DSLModel) {
+	for _, arch := range model.Architectures {
+		if len(arch.Presentation) == 0 && len(arch.Gateway) == 0 {
+			continue
+		}
+
+		if arch.Name != "" {
+			f.line(0, "arch %s {", formatName(arch.Name))
+		} else {
+			f.line(0, "arch {")
+		}
+
+		f.writeArchSection("presentation", arch.Presentation)
+		f.writeArchSection("gateway", arch.Gateway)
+
+		f.sb.WriteString("}\n\n")
+	}
+}
+
+// writeArchSection emits a presentation or gateway section
+func (f *Formatter) writeArchSection(name string, components []parser.Component) {
+	if len(components) == 0 {
+		return
+	}
+
+	f.line(1, "%s:", name)
+	for _, component := range components {
+		f.line(2, "%s", formatComponent(component))
+	}
+}
+
+// writeExposures emits one exposure block per exposure
+func (f *Formatter) writeExposures(model *parser.DSLModel) {
+	for _, exposure := range model.Exposures {
+		if len(exposure.To) == 0 && len(exposure.Of) == 0 && len(exposure.Through) == 0 {
+			continue
+		}
+
+		f.annotate(0, exposure.Metadata)
+		f.line(0, "exposure %s {", formatName(exposure.Name))
+		if len(exposure.To) > 0 {
+			f.line(1, "to: %s", formatNameList(exposure.To))
+		}
+		if len(exposure.Of) > 0 {
+			f.line(1, "of: %s", formatNameList(exposure.Of))
+		}
+		if len(exposure.Through) > 0 {
+			f.line(1, "through: %s", formatNameList(exposure.Through))
+		}
+		f.sb.WriteString("}\n\n")
+	}
+}
+
+// writeServices emits a single services block
+func (f *Formatter) writeServices(model *parser.DSLModel) {
+	if len(model.Services) == 0 {
+		return
+	}
+
+	f.sb.WriteString("services {\n")
+	for _, service := range model.Services {
+		properties := serviceProperties(service)
+		if len(properties) == 0 {
+			// A service block needs at least one property to be valid
+			f.line(1, "// %s has no properties", service.Name)
+			continue
+		}
+
+		f.annotate(1, service.Metadata)
+		f.line(1, "%s {", formatServiceName(service.Name))
+		for _, property := range properties {
+			f.line(2, "%s", property)
+		}
+		f.line(1, "}")
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// writeDataStores emits a single datastores block; stores without properties cannot be expressed and are skipped
+func (f *Formatter) writeDataStores(model *parser.DSLModel) {
+	declared := make([]parser.DataStore, 0, len(model.DataStores))
+	for _, dataStore := range model.DataStores {
+		if dataStore.Type != "" || dataStore.EngineVersion != "" || len(dataStore.SharedBy) > 0 || len(dataStore.Metadata) > 0 {
+			declared = append(declared, dataStore)
+		}
+	}
+	if len(declared) == 0 {
+		return
+	}
+
+	f.sb.WriteString("datastores {\n")
+	for _, dataStore := range declared {
+		f.annotate(1, dataStore.Metadata)
+		f.line(1, "%s {", formatName(dataStore.Name))
+		if dataStore.Type != "" {
+			f.line(2, "type: %s", formatName(dataStore.Type))
+		}
+		if dataStore.EngineVersion != "" {
+			f.line(2, "engine_version: %s", formatName(dataStore.EngineVersion))
+		}
+		if len(dataStore.SharedBy) > 0 {
+			services := make([]string, 0, len(dataStore.SharedBy))
+			for _, service := range dataStore.SharedBy {
+				services = append(services, formatServiceName(service))
+			}
+			f.line(2, "shared_by: [%s]", strings.Join(services, ", "))
+		}
+		f.line(1, "}")
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// writeExternalSystems emits one external_system block per system, with its properties on one line
+func (f *Formatter) writeExternalSystems(model *parser.DSLModel) {
+	for _, system := range model.ExternalSystems {
+		f.annotate(0, system.Metadata)
+		properties := make([]string, 0, 2)
+		if system.Protocol != "" {
+			properties = append(properties, "protocol: "+formatName(system.Protocol))
+		}
+		if system.Owner != "" {
+			properties = append(properties, "owner: "+formatName(system.Owner))
+		}
+
+		if len(properties) == 0 {
+			f.sb.WriteString(fmt.Sprintf("external_system %s {}\n\n", formatName(system.Name)))
+			continue
+		}
+		f.sb.WriteString(fmt.Sprintf("external_system %s { %s }\n\n", formatName(system.Name), strings.Join(properties, ", ")))
+	}
+}
+
+// writeEnvironments emits one environment block per environment, its overrides formatted as a
+// model of their own and indented one level
+func (f *Formatter) writeEnvironments(model *parser.DSLModel) {
+	for _, environment := range model.Environments {
+		overrides := NewFormatter().Format(&parser.DSLModel{
+			Architectures: environment.Architectures,
+			Services:      environment.Services,
+		})
+
+		f.line(0, "environment %s {", formatName(environment.Name))
+		for _, line := range strings.Split(strings.TrimRight(overrides, "\n"), "\n") {
+			if line == "" {
+				f.sb.WriteString("\n")
+				continue
+			}
+			f.line(1, "%s", line)
+		}
+		f.sb.WriteString("}\n\n")
+	}
+}
+
+// serviceProperties returns the formatted property lines of a service
+func serviceProperties(service parser.Service) []string {
+	properties := make([]string, 0)
+
+	if len(service.Domains) > 0 {
+		domains := make([]string, 0, len(service.Domains))
+		for _, domain := range service.Domains {
+			domains = append(domains, joinNonEmpty(formatName(domain), formatModifiers(service.DomainModifiers[domain])))
+		}
+		properties = append(properties, "domains: "+strings.Join(domains, ", "))
+	}
+	if len(service.DataStores) > 0 {
+		properties = append(properties, "data-stores: "+formatNameList(service.DataStores))
+	}
+	if service.Language != "" {
+		properties = append(properties, "language: "+service.Language)
+	}
+	if service.Deployment.Type != "" {
+		deployment := "deployment: " + service.Deployment.Type
+		if len(service.Deployment.Rules) > 0 {
+			rules := make([]string, 0, len(service.Deployment.Rules))
+			for _, rule := range service.Deployment.Rules {
+				rules = append(rules, fmt.Sprintf("%s -> %s", rule.Percentage, formatName(rule.Target)))
+			}
+			deployment += "(" + strings.Join(rules, ", ") + ")"
+		}
+		properties = append(properties, deployment)
+	}
+
+	return properties
+}
+
+// writeUseCases emits one use_case block per use case
+func (f *Formatter) writeUseCases(model *parser.DSLModel) {
+	for _, useCase := range model.UseCases {
+		f.annotate(0, useCase.Metadata)
+		f.line(0, "%s {", joinNonEmpty("use_case", quote(useCase.Name), formatModifiers(useCase.Modifiers)))
+
+		for i, scenario := range useCase.Scenarios {
+			if i > 0 {
+				f.sb.WriteString("\n")
+			}
+			f.line(1, "%s", formatTrigger(scenario.Trigger))
+			for _, action := range scenario.Actions {
+				if formatted := formatAction(action); formatted != "" {
+					f.line(2, "%s", formatted)
+				}
+			}
+		}
+
+		f.sb.WriteString("}\n\n")
+	}
+}
+
+// writeSagas emits one saga block per saga, with each compensation under its step
+func (f *Formatter) writeSagas(model *parser.DSLModel) {
+	for _, saga := range model.Sagas {
+		f.line(0, "saga %s {", quote(saga.Name))
+		for _, step := range saga.Steps {
+			f.line(1, "step %s", formatAction(step.Action))
+			if step.Compensation != nil {
+				f.line(2, "compensate with %s", formatAction(*step.Compensation))
+			}
+		}
+		f.sb.WriteString("}\n\n")
+	}
+}
+
+// writeRules emits a single rules block
+func (f *Formatter) writeRules(model *parser.DSLModel) {
+	if len(model.Rules) == 0 {
+		return
+	}
+
+	f.sb.WriteString("rules {\n")
+	for _, rule := range model.Rules {
+		f.line(1, "%s", formatRule(rule))
+	}
+	f.sb.WriteString("}\n\n")
+}
+
+// line writes a single indented line
+func (f *Formatter) line(depth int, format string, args ...interface{}) {
+	f.sb.WriteString(strings.Repeat(indentUnit, depth))
+	f.sb.WriteString(fmt.Sprintf(format, args...))
+	f.sb.WriteString("\n")
+}
+
+// annotate emits the annotations of an element on the line before it
+func (f *Formatter) annotate(depth int, metadata parser.Metadata) {
+	if annotations := formatMetadata(metadata); annotations != "" {
+		f.line(depth, "%s", annotations)
+	}
+}
+
+// formatMetadata renders metadata as annotations: owner, tag and description first, then the rest by name
+func formatMetadata(metadata parser.Metadata) string {
+	keys := make([]string, 0, len(metadata))
+	for _, key := range []string{parser.MetadataOwner, parser.MetadataTag, parser.MetadataDescription} {
+		if _, ok := metadata[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	others := make([]string, 0, len(metadata))
+	for key := range metadata {
+		if key != parser.MetadataOwner && key != parser.MetadataTag && key != parser.MetadataDescription {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+
+	annotations := make([]string, 0, len(metadata))
+	for _, key := range append(keys, others...) {
+		if len(metadata[key]) == 0 {
+			annotations = append(annotations, "@"+key)
+			continue
+		}
+		values := make([]string, 0, len(metadata[key]))
+		for _, value := range metadata[key] {
+			if identifierPattern.MatchString(value) {
+				values = append(values, value)
+			} else {
+				values = append(values, quote(value))
+			}
+		}
+		annotations = append(annotations, fmt.Sprintf("@%s(%s)", key, strings.Join(values, ", ")))
+	}
+	return strings.Join(annotations, " ")
+}
+
+// formatTrigger renders a scenario trigger in its grammar form
+func formatTrigger(trigger parser.Trigger) string {
+	switch trigger.Type {
+	case parser.TriggerTypeExternal:
+		return joinNonEmpty("when", formatName(trigger.Actor), formatName(trigger.Verb), formatPhrase(trigger.Phrase))
+	case parser.TriggerTypeDomainListen:
+		return fmt.Sprintf("when %s listens %s", formatName(trigger.Domain), quote(trigger.Event))
+	case parser.TriggerTypeEvent:
+		return fmt.Sprintf("when %s", quote(trigger.Event))
+	}
+	return "when " + formatPhrase(trigger.Description)
+}
+
+// formatAction renders an action in its grammar form
+func formatAction(action parser.Action) string {
+	switch action.Type {
+	case parser.ActionTypeSync:
+		return joinNonEmpty(formatName(action.Domain), "asks", formatName(action.TargetDomain), action.Connector, formatPhrase(action.Phrase), formatModifiers(action.Modifiers))
+	case parser.ActionTypeAsync:
+		return joinNonEmpty(formatName(action.Domain), "notifies", quote(action.Event), formatMetadata(action.Metadata))
+	case parser.ActionTypeInternal:
+		return joinNonEmpty(formatName(action.Domain), formatName(action.Verb), action.Connector, formatPhrase(action.Phrase), formatModifiers(action.Modifiers))
+	case parser.ActionTypeReturn:
+		if action.TargetDomain != "" {
+			return joinNonEmpty(formatName(action.Domain), "returns to", formatName(action.TargetDomain), action.Connector, formatPhrase(action.Phrase))
+		}
+		return joinNonEmpty(formatName(action.Domain), "returns", action.Connector, formatPhrase(action.Phrase))
+	case parser.ActionTypeDataAccess:
+		return joinNonEmpty(formatName(action.Domain), action.Verb, action.Connector, formatName(action.DataStore), formatPhrase(action.Phrase))
+	}
+	return ""
+}
+
+// formatRule renders a fitness rule in its grammar form, falling back to the source text when it was not understood
+func formatRule(rule parser.Rule) string {
+	switch rule.Type {
+	case parser.RuleTypeNoSync:
+		return fmt.Sprintf("no_sync from %s to %s", formatName(rule.From), formatName(rule.To))
+	case parser.RuleTypeCallsThrough:
+		return fmt.Sprintf("calls to %s only through %s", formatName(rule.To), formatName(rule.Through))
+	case parser.RuleTypeMaxSyncHops:
+		if rule.Limit >= 0 {
+			return fmt.Sprintf("max_sync_hops %d", rule.Limit)
+		}
+	}
+	return rule.Text
+}
+
+// formatComponent renders an arch component, including chains and modifiers
+func formatComponent(component parser.Component) string {
+	if component.Type == parser.ComponentTypeFlow && len(component.Chain) > 0 {
+		parts := make([]string, 0, len(component.Chain))
+		for _, chainComponent := range component.Chain {
+			parts = append(parts, formatComponent(chainComponent))
+		}
+		return strings.Join(parts, " > ")
+	}
+
+	return formatName(component.Name) + formatModifiers(component.Modifiers)
+}
+
+// formatModifiers renders a modifier list such as [ssl, p99:40ms], or "" when there are none
+func formatModifiers(modifiers []parser.ComponentModifier) string {
+	if len(modifiers) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(modifiers))
+	for _, modifier := range modifiers {
+		if modifier.Value != "" {
+			parts = append(parts, fmt.Sprintf("%s:%s", modifier.Key, modifier.Value))
+		} else {
+			parts = append(parts, modifier.Key)
+		}
+	}
+	return fmt.Sprintf("[%s]", strings.Join(parts, ", "))
+}
+
+// formatPhrase renders phrase words, quoting any word that is not a valid identifier
+func formatPhrase(phrase string) string {
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		if !identifierPattern.MatchString(word) {
+			words[i] = quote(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// formatName renders a name, replacing characters the IDENTIFIER rule does not accept
+func formatName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return Identifier(name)
+}
+
+// formatServiceName renders a service name, quoting it when it is not a valid identifier
+func formatServiceName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return quote(name)
+}
+
+// formatNameList renders a comma separated list of names
+func formatNameList(names []string) string {
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		formatted = append(formatted, formatName(name))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// Identifier converts arbitrary text into a valid Craft identifier (e.g. "Pet Store" -> "Pet_Store")
+func Identifier(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			sb.WriteRune(r)
+		case r == '.' || r == '-':
+			if sb.Len() > 0 {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune('_')
+			}
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
+
+// quote wraps text in double quotes, dropping characters the STRING rule does not accept
+func quote(text string) string {
+	cleaned := strings.NewReplacer("\"", "'", "\r", " ", "\n", " ").Replace(text)
+	return "\"" + cleaned + "\""
+}
+
+// joinNonEmpty joins the non-empty parts with single spaces
+func joinNonEmpty(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
@@ -0,0 +1,255 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestFormat_AllSections(t *testing.T) {
+	model := &parser.DSLModel{
+		Actors: []parser.Actor{
+			{Name: "Customer", Type: parser.ActorTypeUser},
+			{
+				Name:        "Teller",
+				Type:        parser.ActorTypeUser,
+				Description: "Branch staff",
+				Persona:     []parser.PersonaAttribute{{Name: "goal", Value: "Serve customers quickly"}, {Name: "expertise", Value: "expert"}},
+				Uses:        []string{"PublicAPI"},
+				Permissions: []string{"approve_transfer"},
+			},
+		},
+		Domains: []parser.Domain{
+			{Name: "Banking", SubDomains: []string{"Payments", "Accounts"}, SubDomainMetadata: map[string]parser.Metadata{"Payments": {"tag": {"pci"}}}},
+		},
+		ContextMap: []parser.ContextRelation{
+			{From: "Banking", To: "Fraud", Patterns: []string{"customer-supplier", "acl"}},
+			{From: "Banking", To: "Ledger"},
+		},
+		Architectures: []parser.Architecture{
+			{
+				Name: "production",
+				Presentation: []parser.Component{
+					{Name: "WebApp", Type: parser.ComponentTypeSimple, Modifiers: []parser.ComponentModifier{{Key: "framework", Value: "react"}, {Key: "ssl"}}},
+				},
+				Gateway: []parser.Component{
+					{Type: parser.ComponentTypeFlow, Chain: []parser.Component{{Name: "LoadBalancer"}, {Name: "APIGateway"}}},
+				},
+			},
+		},
+		Exposures: []parser.Exposure{
+			{Name: "PublicAPI", To: []string{"Customer"}, Of: []string{"Payments"}, Through: []string{"APIGateway"}},
+		},
+		Services: []parser.Service{
+			{
+				Name:     "Payment Service",
+				Domains:  []string{"Payments", "Ledger"},
+				Metadata: parser.Metadata{"description": {"Moves money"}, "owner": {"team-payments"}, "tag": {"pci", "gdpr"}},
+				DomainModifiers: map[string][]parser.ComponentModifier{
+					"Ledger": {{Key: "p99", Value: "30ms"}},
+				},
+				DataStores: []string{"payment_db"},
+				Language:   "golang",
+				Deployment: parser.DeploymentStrategy{Type: "canary", Rules: []parser.DeploymentRule{{Percentage: "10%", Target: "canary"}}},
+			},
+		},
+		DataStores: []parser.DataStore{
+			{Name: "payment_db", Type: "postgres", EngineVersion: "15", SharedBy: []string{"Audit Service"}, Owner: "Payment Service"},
+			{Name: "payment_sqlite", Owner: "Payment Service"},
+		},
+		ExternalSystems: []parser.ExternalSystem{
+			{Name: "Stripe", Protocol: "https", Owner: "vendor"},
+			{Name: "LegacyLedger"},
+		},
+		Environments: []parser.Environment{
+			{
+				Name:     "staging",
+				Services: []parser.Service{{Name: "Payment Service", DataStores: []string{"payment_sqlite"}, Deployment: parser.DeploymentStrategy{Type: "rolling"}}},
+				Architectures: []parser.Architecture{
+					{Name: "production", Gateway: []parser.Component{{Name: "Ingress", Type: parser.ComponentTypeSimple}}},
+				},
+			},
+		},
+		UseCases: []parser.UseCase{
+			{
+				Name:      "Money Transfer",
+				Modifiers: []parser.ComponentModifier{{Key: "slo", Value: "500ms"}},
+				Metadata:  parser.Metadata{"owner": {"team-payments"}, "reviewed": nil},
+				Scenarios: []parser.Scenario{
+					{
+						Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "initiates", Phrase: "transfer"},
+						Actions: []parser.Action{
+							{Type: parser.ActionTypeSync, Domain: "Payments", TargetDomain: "Accounts", Connector: "to", Phrase: "verify account", Modifiers: []parser.ComponentModifier{{Key: "p99", Value: "40ms"}}},
+							{Type: parser.ActionTypeInternal, Domain: "Payments", Verb: "stores", Phrase: "transfer row"},
+							{Type: parser.ActionTypeDataAccess, Domain: "Payments", Verb: "writes", Connector: "to", DataStore: "ledger_db", Access: parser.DataAccessWrite, Phrase: "transfer row"},
+							{Type: parser.ActionTypeAsync, Domain: "Payments", Event: "Transfer Completed", Metadata: parser.Metadata{"tag": {"audit"}}},
+							{Type: parser.ActionTypeReturn, Domain: "Payments", Phrase: "confirmation"},
+						},
+					},
+					{
+						Trigger: parser.Trigger{Type: parser.TriggerTypeDomainListen, Domain: "Accounts", Event: "Transfer Completed"},
+						Actions: []parser.Action{
+							{Type: parser.ActionTypeReturn, Domain: "Accounts", TargetDomain: "Payments", Phrase: "POST /transfers"},
+						},
+					},
+				},
+			},
+		},
+		Sagas: []parser.Saga{
+			{
+				Name: "Money Transfer",
+				Steps: []parser.SagaStep{
+					{Action: parser.Action{Type: parser.ActionTypeSync, Domain: "Payments", TargetDomain: "Accounts", Connector: "to", Phrase: "check limits", Modifiers: []parser.ComponentModifier{{Key: "readonly"}}}},
+					{
+						Action:       parser.Action{Type: parser.ActionTypeInternal, Domain: "Ledger", Verb: "debits", Phrase: "source account"},
+						Compensation: &parser.Action{Type: parser.ActionTypeInternal, Domain: "Ledger", Verb: "credits", Phrase: "source account"},
+					},
+				},
+			},
+		},
+		Rules: []parser.Rule{
+			{Type: parser.RuleTypeNoSync, From: "Notifications", To: parser.RuleScopeAny},
+			{Type: parser.RuleTypeCallsThrough, To: "Payments", Through: "PublicAPI"},
+			{Type: parser.RuleTypeMaxSyncHops, Limit: 3},
+			{Type: parser.RuleTypeMaxSyncHops, Limit: -1, Text: "max_sync_hops many"},
+		},
+	}
+
+	expected := `actors {
+  user Customer
+  user Teller {
+    description: "Branch staff"
+    persona {
+      goal: "Serve customers quickly"
+      expertise: expert
+    }
+    uses: PublicAPI
+    permissions: approve_transfer
+  }
+}
+
+domains {
+  Banking {
+    Accounts
+    @tag(pci) Payments
+  }
+}
+
+context_map {
+  Banking -> Fraud : customer-supplier, acl
+  Banking -> Ledger
+}
+
+arch production {
+  presentation:
+    WebApp[framework:react, ssl]
+  gateway:
+    LoadBalancer > APIGateway
+}
+
+exposure PublicAPI {
+  to: Customer
+  of: Payments
+  through: APIGateway
+}
+
+services {
+  @owner(team-payments) @tag(pci, gdpr) @description("Moves money")
+  "Payment Service" {
+    domains: Payments, Ledger [p99:30ms]
+    data-stores: payment_db
+    language: golang
+    deployment: canary(10% -> canary)
+  }
+}
+
+datastores {
+  payment_db {
+    type: postgres
+    engine_version: 15
+    shared_by: ["Audit Service"]
+  }
+}
+
+external_system Stripe { protocol: https, owner: vendor }
+
+external_system LegacyLedger {}
+
+environment staging {
+  arch production {
+    gateway:
+      Ingress
+  }
+
+  services {
+    "Payment Service" {
+      data-stores: payment_sqlite
+      deployment: rolling
+    }
+  }
+}
+
+@owner(team-payments) @reviewed
+use_case "Money Transfer" [slo:500ms] {
+  when Customer initiates transfer
+    Payments asks Accounts to verify account [p99:40ms]
+    Payments stores transfer row
+    Payments writes to ledger_db transfer row
+    Payments notifies "Transfer Completed" @tag(audit)
+    Payments returns confirmation
+
+  when Accounts listens "Transfer Completed"
+    Accounts returns to Payments POST "/transfers"
+}
+
+saga "Money Transfer" {
+  step Payments asks Accounts to check limits [readonly]
+  step Ledger debits source account
+    compensate with Ledger credits source account
+}
+
+rules {
+  no_sync from Notifications to any
+  calls to Payments only through PublicAPI
+  max_sync_hops 3
+  max_sync_hops many
+}
+`
+
+	if got := Format(model); got != expected {
+		t.Errorf("Unexpected output.\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+func TestFormat_SkipsInexpressibleElements(t *testing.T) {
+	model := &parser.DSLModel{
+		Domains:   []parser.Domain{{Name: "Empty", SubDomains: []string{}}},
+		Exposures: []parser.Exposure{{Name: "Nothing"}},
+		Services:  []parser.Service{{Name: "Bare"}},
+	}
+
+	got := Format(model)
+	if strings.Contains(got, "domains {") || strings.Contains(got, "exposure") {
+		t.Errorf("Expected empty domains and exposures to be skipped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "// Bare has no properties") {
+		t.Errorf("Expected placeholder comment for service without properties, got:\n%s", got)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"Pet Store":   "Pet_Store",
+		"user-db":     "user-db",
+		"-leading":    "_leading",
+		"":            "_",
+		"orders/v1.2": "orders_v1.2",
+	}
+
+	for input, expected := range cases {
+		if got := Identifier(input); got != expected {
+			t.Errorf("Identifier(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
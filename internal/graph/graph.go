@@ -0,0 +1,700 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// NodeKind identifies what a node stands for in the model
+type NodeKind string
+
+const (
+	NodeActor     NodeKind = "actor"
+	NodeDomain    NodeKind = "domain"
+	NodeService   NodeKind = "service"
+	NodeDataStore NodeKind = "datastore"
+	NodeEvent     NodeKind = "event"
+	NodeGateway   NodeKind = "gateway"
+	NodeExternal  NodeKind = "external" // Third party system domains call
+)
+
+// EdgeKind identifies the relationship an edge stands for
+type EdgeKind string
+
+const (
+	EdgeSync     EdgeKind = "sync"     // domain asks domain
+	EdgePublish  EdgeKind = "publish"  // domain notifies event
+	EdgeConsume  EdgeKind = "consume"  // event is listened to by domain
+	EdgeReturn   EdgeKind = "return"   // domain returns to domain
+	EdgeInternal EdgeKind = "internal" // domain acts on itself
+	EdgeRead     EdgeKind = "read"     // domain reads from datastore
+	EdgeWrite    EdgeKind = "write"    // domain writes to, creates in or deletes from datastore
+	EdgeTrigger  EdgeKind = "trigger"  // actor starts a scenario at its entry domain
+	EdgeOwns     EdgeKind = "owns"     // service owns domain or datastore
+	EdgeExposes  EdgeKind = "exposes"  // gateway exposes domain
+)
+
+// NodeID identifies a node by kind and name, e.g. "domain:Orders"
+type NodeID string
+
+// ID returns the identifier of the node of the given kind and name
+func ID(kind NodeKind, name string) NodeID {
+	return NodeID(string(kind) + ":" + name)
+}
+
+// Kind returns the kind part of the identifier
+func (id NodeID) Kind() NodeKind {
+	kind, _, _ := strings.Cut(string(id), ":")
+	return NodeKind(kind)
+}
+
+// Name returns the model name part of the identifier
+func (id NodeID) Name() string {
+	_, name, _ := strings.Cut(string(id), ":")
+	return name
+}
+
+// Node is a model element
+type Node struct {
+	ID       NodeID   `json:"id"`
+	Kind     NodeKind `json:"kind"`
+	Name     string   `json:"name"`
+	UseCases []string `json:"use_cases,omitempty"` // Use cases the element takes part in, in model order
+}
+
+// Edge is a single relationship. Behavioural edges carry the use case and scenario they come from;
+// structural edges (owns, exposes) have an empty UseCase and a Scenario of -1.
+type Edge struct {
+	From       NodeID   `json:"from"`
+	To         NodeID   `json:"to"`
+	Kind       EdgeKind `json:"kind"`
+	Label      string   `json:"label,omitempty"` // Action phrase, event or exposure name
+	UseCase    string   `json:"use_case,omitempty"`
+	Scenario   int      `json:"scenario"`              // Index of the scenario within its use case
+	ScenarioID string   `json:"scenario_id,omitempty"` // Parser assigned scenario ID, when present
+	Line       int      `json:"line,omitempty"`
+}
+
+// Graph is a typed multigraph of a model: parallel edges are kept, one per interaction
+type Graph struct {
+	nodes map[NodeID]*Node
+	edges []Edge
+	out   map[NodeID][]int
+	in    map[NodeID][]int
+
+	external map[string]bool // Names of the model's external systems, which actions refer to like domains
+}
+
+// New creates an empty graph
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[NodeID]*Node),
+		edges: make([]Edge, 0),
+		out:   make(map[NodeID][]int),
+		in:    make(map[NodeID][]int),
+
+		external: make(map[string]bool),
+	}
+}
+
+// Build derives the graph of a model from its services, exposures and use cases
+func Build(model *parser.DSLModel) *Graph {
+	g := New()
+
+	for _, actor := range model.Actors {
+		g.AddNode(NodeActor, actor.Name)
+	}
+
+	for _, system := range model.ExternalSystems {
+		g.AddNode(NodeExternal, system.Name)
+		g.external[system.Name] = true
+	}
+
+	for _, service := range model.Services {
+		serviceID := g.AddNode(NodeService, service.Name)
+		for _, domain := range service.Domains {
+			g.AddEdge(Edge{From: serviceID, To: g.AddNode(NodeDomain, domain), Kind: EdgeOwns, Scenario: -1})
+		}
+		for _, dataStore := range service.DataStores {
+			g.AddEdge(Edge{From: serviceID, To: g.AddNode(NodeDataStore, dataStore), Kind: EdgeOwns, Scenario: -1})
+		}
+	}
+
+	for _, exposure := range model.Exposures {
+		for _, actor := range exposure.To {
+			g.AddNode(NodeActor, actor)
+		}
+		for _, gateway := range exposure.Through {
+			gatewayID := g.AddNode(NodeGateway, gateway)
+			for _, domain := range exposure.Of {
+				g.AddEdge(Edge{From: gatewayID, To: g.AddNode(NodeDomain, domain), Kind: EdgeExposes, Label: exposure.Name, Scenario: -1})
+			}
+		}
+	}
+
+	for _, useCase := range model.UseCases {
+		for i, scenario := range useCase.Scenarios {
+			g.addScenario(useCase.Name, i, scenario)
+		}
+	}
+
+	return g
+}
+
+// addScenario adds the nodes and behavioural edges of one scenario
+func (g *Graph) addScenario(useCase string, index int, scenario parser.Scenario) {
+	edge := func(from, to NodeID, kind EdgeKind, label string, line int) {
+		g.AddEdge(Edge{From: from, To: to, Kind: kind, Label: label, UseCase: useCase, Scenario: index, ScenarioID: scenario.ID, Line: line})
+	}
+
+	trigger := scenario.Trigger
+	switch trigger.Type {
+	case parser.TriggerTypeExternal:
+		if trigger.Actor != "" {
+			actorID := g.addParticipant(NodeActor, trigger.Actor, useCase)
+			if entry := EntryDomain(scenario); entry != "" {
+				edge(actorID, g.addParticipant(NodeDomain, entry, useCase), EdgeTrigger, strings.TrimSpace(trigger.Verb+" "+trigger.Phrase), trigger.Line)
+			}
+		}
+	case parser.TriggerTypeEvent:
+		if trigger.Event != "" {
+			g.addParticipant(NodeEvent, trigger.Event, useCase)
+		}
+	case parser.TriggerTypeDomainListen:
+		if trigger.Domain != "" && trigger.Event != "" {
+			edge(g.addParticipant(NodeEvent, trigger.Event, useCase), g.addParticipant(NodeDomain, trigger.Domain, useCase), EdgeConsume, trigger.Event, trigger.Line)
+		}
+	}
+
+	for _, action := range scenario.Actions {
+		if action.Domain == "" {
+			continue
+		}
+		domainID := g.addParticipant(g.callableKind(action.Domain), action.Domain, useCase)
+
+		switch action.Type {
+		case parser.ActionTypeSync:
+			if action.TargetDomain != "" {
+				edge(domainID, g.addParticipant(g.callableKind(action.TargetDomain), action.TargetDomain, useCase), EdgeSync, action.Phrase, action.Line)
+			}
+		case parser.ActionTypeAsync:
+			if action.Event != "" {
+				edge(domainID, g.addParticipant(NodeEvent, action.Event, useCase), EdgePublish, action.Event, action.Line)
+			}
+		case parser.ActionTypeReturn:
+			if action.TargetDomain != "" {
+				edge(domainID, g.addParticipant(g.callableKind(action.TargetDomain), action.TargetDomain, useCase), EdgeReturn, action.Phrase, action.Line)
+			}
+		case parser.ActionTypeInternal:
+			edge(domainID, domainID, EdgeInternal, strings.TrimSpace(action.Verb+" "+action.Phrase), action.Line)
+		case parser.ActionTypeDataAccess:
+			if action.DataStore != "" {
+				kind := EdgeWrite
+				if action.Access == parser.DataAccessRead {
+					kind = EdgeRead
+				}
+				edge(domainID, g.addParticipant(NodeDataStore, action.DataStore, useCase), kind, action.Phrase, action.Line)
+			}
+		}
+	}
+}
+
+// callableKind returns the kind of a name actions refer to: an external system when declared as
+// one, a domain otherwise
+func (g *Graph) callableKind(name string) NodeKind {
+	if g.external[name] {
+		return NodeExternal
+	}
+	return NodeDomain
+}
+
+// addParticipant adds a node and records that it takes part in the use case
+func (g *Graph) addParticipant(kind NodeKind, name, useCase string) NodeID {
+	id := g.AddNode(kind, name)
+	if node := g.nodes[id]; !slices.Contains(node.UseCases, useCase) {
+		node.UseCases = append(node.UseCases, useCase)
+	}
+	return id
+}
+
+// AddNode adds a node if it does not exist yet and returns its identifier
+func (g *Graph) AddNode(kind NodeKind, name string) NodeID {
+	id := ID(kind, name)
+	if _, exists := g.nodes[id]; !exists {
+		g.nodes[id] = &Node{ID: id, Kind: kind, Name: name}
+	}
+	return id
+}
+
+// AddEdge adds an edge; both ends must already be nodes of the graph
+func (g *Graph) AddEdge(edge Edge) {
+	g.out[edge.From] = append(g.out[edge.From], len(g.edges))
+	g.in[edge.To] = append(g.in[edge.To], len(g.edges))
+	g.edges = append(g.edges, edge)
+}
+
+// Node returns the node with the given identifier
+func (g *Graph) Node(id NodeID) (Node, bool) {
+	node, exists := g.nodes[id]
+	if !exists {
+		return Node{}, false
+	}
+	return *node, true
+}
+
+// Has reports whether the graph contains the node
+func (g *Graph) Has(id NodeID) bool {
+	_, exists := g.nodes[id]
+	return exists
+}
+
+// Nodes returns the nodes of a kind sorted by name, or all nodes sorted by identifier when kind is empty
+func (g *Graph) Nodes(kind NodeKind) []Node {
+	nodes := make([]Node, 0)
+	for _, node := range g.nodes {
+		if kind == "" || node.Kind == kind {
+			nodes = append(nodes, *node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Edges returns the edges of the given kinds (all edges when none are given) in model order
+func (g *Graph) Edges(kinds ...EdgeKind) []Edge {
+	edges := make([]Edge, 0)
+	for _, edge := range g.edges {
+		if matchesKind(edge.Kind, kinds) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Out returns the edges leaving a node, restricted to the given kinds
+func (g *Graph) Out(id NodeID, kinds ...EdgeKind) []Edge {
+	return g.selectEdges(g.out[id], kinds)
+}
+
+// In returns the edges entering a node, restricted to the given kinds
+func (g *Graph) In(id NodeID, kinds ...EdgeKind) []Edge {
+	return g.selectEdges(g.in[id], kinds)
+}
+
+// Neighbors returns the distinct successors of a node over the given kinds, sorted, excluding itself
+func (g *Graph) Neighbors(id NodeID, kinds ...EdgeKind) []NodeID {
+	return distinctEnds(g.Out(id, kinds...), id, func(edge Edge) NodeID { return edge.To })
+}
+
+// Predecessors returns the distinct nodes with an edge into the node over the given kinds, sorted, excluding itself
+func (g *Graph) Predecessors(id NodeID, kinds ...EdgeKind) []NodeID {
+	return distinctEnds(g.In(id, kinds...), id, func(edge Edge) NodeID { return edge.From })
+}
+
+// Descendants returns every node reachable from a node over the given kinds, sorted, excluding itself
+func (g *Graph) Descendants(id NodeID, kinds ...EdgeKind) []NodeID {
+	return g.reach(id, func(current NodeID) []NodeID { return g.Neighbors(current, kinds...) })
+}
+
+// Ancestors returns every node that reaches a node over the given kinds, sorted, excluding itself
+func (g *Graph) Ancestors(id NodeID, kinds ...EdgeKind) []NodeID {
+	return g.reach(id, func(current NodeID) []NodeID { return g.Predecessors(current, kinds...) })
+}
+
+// FanOut counts the distinct nodes a node depends on over the given kinds
+func (g *Graph) FanOut(id NodeID, kinds ...EdgeKind) int {
+	return len(g.Neighbors(id, kinds...))
+}
+
+// FanIn counts the distinct nodes depending on a node over the given kinds
+func (g *Graph) FanIn(id NodeID, kinds ...EdgeKind) int {
+	return len(g.Predecessors(id, kinds...))
+}
+
+// ServiceOf returns the first service owning a domain or datastore, or "" when it is unowned
+func (g *Graph) ServiceOf(name string) string {
+	for _, kind := range []NodeKind{NodeDomain, NodeDataStore} {
+		for _, edge := range g.In(ID(kind, name), EdgeOwns) {
+			return edge.From.Name()
+		}
+	}
+	return ""
+}
+
+// DomainsOf returns the domains owned by a service in declaration order
+func (g *Graph) DomainsOf(service string) []string {
+	return g.ownedNames(service, NodeDomain)
+}
+
+// DataStoresOf returns the datastores owned by a service in declaration order
+func (g *Graph) DataStoresOf(service string) []string {
+	return g.ownedNames(service, NodeDataStore)
+}
+
+// Publishers returns the domains publishing an event, in the order they first do so
+func (g *Graph) Publishers(event string) []string {
+	return g.endNames(g.In(ID(NodeEvent, event), EdgePublish), func(edge Edge) NodeID { return edge.From })
+}
+
+// Consumers returns the domains listening to an event, in the order they first do so
+func (g *Graph) Consumers(event string) []string {
+	return g.endNames(g.Out(ID(NodeEvent, event), EdgeConsume), func(edge Edge) NodeID { return edge.To })
+}
+
+// ShortestPath returns the nodes of a shortest path from one node to another over the given kinds, or nil
+func (g *Graph) ShortestPath(from, to NodeID, kinds ...EdgeKind) []NodeID {
+	if !g.Has(from) || !g.Has(to) {
+		return nil
+	}
+
+	previous := map[NodeID]NodeID{from: ""}
+	queue := []NodeID{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			break
+		}
+		for _, next := range g.Neighbors(current, kinds...) {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if _, reached := previous[to]; !reached {
+		return nil
+	}
+
+	path := []NodeID{to}
+	for current := to; current != from; {
+		current = previous[current]
+		path = append([]NodeID{current}, path...)
+	}
+	return path
+}
+
+// MaxPaths bounds the paths Paths returns, since the simple paths between two nodes can grow
+// exponentially with the graph
+const MaxPaths = 1000
+
+// Paths returns the simple paths from one node to another over the given kinds, in sorted order, stopping
+// after MaxPaths of them. When from and to are the same node, the paths are the cycles through it.
+// The walk only enters nodes that reach the target, and gives up after MaxPaths steps per node.
+func (g *Graph) Paths(from, to NodeID, kinds ...EdgeKind) [][]NodeID {
+	paths := make([][]NodeID, 0)
+	if !g.Has(from) || !g.Has(to) {
+		return paths
+	}
+
+	reaches := make(map[NodeID]bool)
+	for _, id := range g.Ancestors(to, kinds...) {
+		reaches[id] = true
+	}
+	steps := MaxPaths * len(g.nodes)
+
+	onPath := map[NodeID]bool{from: true}
+	var walk func(path []NodeID)
+	walk = func(path []NodeID) {
+		for _, next := range g.Neighbors(path[len(path)-1], kinds...) {
+			if len(paths) == MaxPaths || steps == 0 {
+				return
+			}
+			steps--
+			if next == to {
+				paths = append(paths, append(slices.Clone(path), to))
+				continue
+			}
+			if onPath[next] || !reaches[next] {
+				continue
+			}
+			onPath[next] = true
+			walk(append(path, next))
+			onPath[next] = false
+		}
+	}
+	walk([]NodeID{from})
+
+	return paths
+}
+
+// StronglyConnectedComponents partitions the nodes touched by the given kinds into strongly connected components.
+// Each component is sorted, and components are ordered by their first node.
+func (g *Graph) StronglyConnectedComponents(kinds ...EdgeKind) [][]NodeID {
+	nodes := make([]NodeID, 0)
+	seen := make(map[NodeID]bool)
+	for _, edge := range g.Edges(kinds...) {
+		for _, id := range []NodeID{edge.From, edge.To} {
+			if !seen[id] {
+				seen[id] = true
+				nodes = append(nodes, id)
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	// Tarjan's algorithm
+	index := 0
+	indices := make(map[NodeID]int)
+	lowLinks := make(map[NodeID]int)
+	onStack := make(map[NodeID]bool)
+	stack := make([]NodeID, 0)
+	components := make([][]NodeID, 0)
+
+	var connect func(id NodeID)
+	connect = func(id NodeID) {
+		indices[id] = index
+		lowLinks[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range g.Neighbors(id, kinds...) {
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				lowLinks[id] = min(lowLinks[id], lowLinks[next])
+			} else if onStack[next] {
+				lowLinks[id] = min(lowLinks[id], indices[next])
+			}
+		}
+
+		if lowLinks[id] == indices[id] {
+			component := make([]NodeID, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			sort.Slice(component, func(i, j int) bool { return component[i] < component[j] })
+			components = append(components, component)
+		}
+	}
+
+	for _, id := range nodes {
+		if _, visited := indices[id]; !visited {
+			connect(id)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// Cycles returns the strongly connected components with more than one node over the given kinds
+func (g *Graph) Cycles(kinds ...EdgeKind) [][]NodeID {
+	cycles := make([][]NodeID, 0)
+	for _, component := range g.StronglyConnectedComponents(kinds...) {
+		if len(component) > 1 {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+// Subgraph returns a graph with the edges accepted by keep and the nodes they connect
+func (g *Graph) Subgraph(keep func(Edge) bool) *Graph {
+	sub := New()
+	for _, edge := range g.edges {
+		if !keep(edge) {
+			continue
+		}
+		for _, id := range []NodeID{edge.From, edge.To} {
+			if !sub.Has(id) {
+				node := *g.nodes[id]
+				sub.nodes[id] = &node
+			}
+		}
+		sub.AddEdge(edge)
+	}
+	return sub
+}
+
+// ServiceView collapses domains into their owning services: every edge of the given kinds between
+// domains of two different services becomes an edge between the services. Other edges are dropped.
+func (g *Graph) ServiceView(kinds ...EdgeKind) *Graph {
+	view := New()
+	for _, node := range g.Nodes(NodeService) {
+		view.AddNode(NodeService, node.Name)
+	}
+
+	for _, edge := range g.Edges(kinds...) {
+		if edge.From.Kind() != NodeDomain || edge.To.Kind() != NodeDomain {
+			continue
+		}
+		from, to := g.ServiceOf(edge.From.Name()), g.ServiceOf(edge.To.Name())
+		if from == "" || to == "" || from == to {
+			continue
+		}
+
+		projected := edge
+		projected.From = ID(NodeService, from)
+		projected.To = ID(NodeService, to)
+		view.AddEdge(projected)
+	}
+	return view
+}
+
+// LongestPath returns the nodes of a longest simple path over the given kinds, preferring the
+// lexically smallest one on ties, or nil when there are no such edges. The graph is condensed into its
+// strongly connected components and the components are solved in topological order, so only the paths
+// inside a cycle are enumerated; past MaxPaths steps per node of a component, the longest path found so
+// far through it is kept.
+func (g *Graph) LongestPath(kinds ...EdgeKind) []NodeID {
+	components := g.StronglyConnectedComponents(kinds...)
+	componentOf := make(map[NodeID]int)
+	for i, component := range components {
+		for _, id := range component {
+			componentOf[id] = i
+		}
+	}
+
+	// longest holds the longest path starting at each node of a solved component
+	longest := make(map[NodeID][]NodeID)
+	keep := func(start NodeID, path []NodeID) {
+		best := longest[start]
+		if len(path) > len(best) || (len(path) == len(best) && slices.Compare(path, best) < 0) {
+			longest[start] = path
+		}
+	}
+
+	solved := make([]bool, len(components))
+	var solve func(i int)
+	solve = func(i int) {
+		solved[i] = true
+		for _, id := range components[i] {
+			for _, next := range g.Neighbors(id, kinds...) {
+				if j := componentOf[next]; !solved[j] {
+					solve(j)
+				}
+			}
+		}
+
+		// A path starting in the component walks inside it, then may leave for a solved component it
+		// cannot come back from
+		steps := MaxPaths * len(components[i])
+		for _, start := range components[i] {
+			onPath := map[NodeID]bool{start: true}
+			var walk func(path []NodeID)
+			walk = func(path []NodeID) {
+				keep(start, slices.Clone(path))
+				for _, next := range g.Neighbors(path[len(path)-1], kinds...) {
+					if steps == 0 {
+						return
+					}
+					steps--
+					if componentOf[next] != i {
+						keep(start, append(slices.Clone(path), longest[next]...))
+					} else if !onPath[next] {
+						onPath[next] = true
+						walk(append(path, next))
+						onPath[next] = false
+					}
+				}
+			}
+			walk([]NodeID{start})
+		}
+	}
+
+	var result []NodeID
+	for i, component := range components {
+		if !solved[i] {
+			solve(i)
+		}
+		for _, id := range component {
+			if path := longest[id]; len(path) > len(result) || (len(path) == len(result) && slices.Compare(path, result) < 0) {
+				result = path
+			}
+		}
+	}
+
+	if len(result) < 2 {
+		return nil
+	}
+	return result
+}
+
+// EntryDomain returns the first domain acting in a scenario, where an external trigger enters the system
+func EntryDomain(scenario parser.Scenario) string {
+	for _, action := range scenario.Actions {
+		if action.Domain != "" {
+			return action.Domain
+		}
+	}
+	return ""
+}
+
+// reach walks breadth first from a node and returns the nodes visited, sorted, excluding the start
+func (g *Graph) reach(from NodeID, next func(NodeID) []NodeID) []NodeID {
+	seen := map[NodeID]bool{from: true}
+	reached := make([]NodeID, 0)
+	queue := []NodeID{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, id := range next(current) {
+			if !seen[id] {
+				seen[id] = true
+				reached = append(reached, id)
+				queue = append(queue, id)
+			}
+		}
+	}
+	sort.Slice(reached, func(i, j int) bool { return reached[i] < reached[j] })
+	return reached
+}
+
+func (g *Graph) selectEdges(indices []int, kinds []EdgeKind) []Edge {
+	edges := make([]Edge, 0, len(indices))
+	for _, i := range indices {
+		if matchesKind(g.edges[i].Kind, kinds) {
+			edges = append(edges, g.edges[i])
+		}
+	}
+	return edges
+}
+
+func (g *Graph) ownedNames(service string, kind NodeKind) []string {
+	names := make([]string, 0)
+	for _, edge := range g.Out(ID(NodeService, service), EdgeOwns) {
+		if edge.To.Kind() == kind {
+			names = append(names, edge.To.Name())
+		}
+	}
+	return names
+}
+
+func (g *Graph) endNames(edges []Edge, end func(Edge) NodeID) []string {
+	names := make([]string, 0)
+	for _, edge := range edges {
+		if name := end(edge).Name(); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func distinctEnds(edges []Edge, self NodeID, end func(Edge) NodeID) []NodeID {
+	seen := make(map[NodeID]bool)
+	ids := make([]NodeID, 0)
+	for _, edge := range edges {
+		id := end(edge)
+		if id != self && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func matchesKind(kind EdgeKind, kinds []EdgeKind) bool {
+	return len(kinds) == 0 || slices.Contains(kinds, kind)
+}
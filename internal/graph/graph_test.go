@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const shop = `actor user Customer
+
+services {
+  OrderService {
+    domains: Orders, Carts
+    data-stores: orders_db
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+exposure PublicAPI {
+  to: Customer
+  of: Orders
+  through: APIGateway
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Billing to charge card
+    Billing asks Carts to load cart
+    Billing returns to Orders receipt
+    Orders notifies "Order Placed"
+    Orders stores order
+    Orders writes to orders_db order row
+
+  when Billing listens "Order Placed"
+    Billing asks Orders to confirm
+}
+
+use_case "Reorder" {
+  when Customer repeats order
+    Carts notifies "Order Placed"
+}
+`
+
+func shopModel(t *testing.T, extra string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(shop + extra)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestBuild_NodesAndEdges(t *testing.T) {
+	g := Build(shopModel(t, ""))
+
+	counts := map[EdgeKind]int{}
+	for _, edge := range g.Edges() {
+		counts[edge.Kind]++
+	}
+	expected := map[EdgeKind]int{
+		EdgeOwns: 4, EdgeExposes: 1, EdgeTrigger: 2, EdgeSync: 3,
+		EdgeReturn: 1, EdgePublish: 2, EdgeInternal: 1, EdgeConsume: 1, EdgeWrite: 1,
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected edge counts %v, got %v", expected, counts)
+	}
+
+	sync := g.Edges(EdgeSync)[1]
+	if sync.From != ID(NodeDomain, "Billing") || sync.To != ID(NodeDomain, "Carts") ||
+		sync.Label != "load cart" || sync.UseCase != "Checkout" || sync.Scenario != 0 || sync.Line != 22 {
+		t.Errorf("Unexpected sync edge: %+v", sync)
+	}
+
+	write := g.Edges(EdgeWrite)[0]
+	if write.From != ID(NodeDomain, "Orders") || write.To != ID(NodeDataStore, "orders_db") || write.Label != "order row" {
+		t.Errorf("Unexpected write edge: %+v", write)
+	}
+
+	gateway := g.Out(ID(NodeGateway, "APIGateway"), EdgeExposes)
+	if len(gateway) != 1 || gateway[0].To != ID(NodeDomain, "Orders") || gateway[0].Label != "PublicAPI" {
+		t.Errorf("Unexpected exposure edges: %+v", gateway)
+	}
+
+	carts, _ := g.Node(ID(NodeDomain, "Carts"))
+	if !reflect.DeepEqual(carts.UseCases, []string{"Checkout", "Reorder"}) {
+		t.Errorf("Expected Carts in both use cases, got %v", carts.UseCases)
+	}
+	if id := ID(NodeEvent, "Order Placed"); id.Kind() != NodeEvent || id.Name() != "Order Placed" {
+		t.Errorf("Unexpected node ID parts: %s %s", id.Kind(), id.Name())
+	}
+}
+
+func TestGraph_Ownership(t *testing.T) {
+	g := Build(shopModel(t, ""))
+
+	if got := g.ServiceOf("Carts"); got != "OrderService" {
+		t.Errorf("Expected OrderService to own Carts, got %q", got)
+	}
+	if got := g.ServiceOf("orders_db"); got != "OrderService" {
+		t.Errorf("Expected OrderService to own orders_db, got %q", got)
+	}
+	if got := g.ServiceOf("Unknown"); got != "" {
+		t.Errorf("Expected no owner, got %q", got)
+	}
+	if got := g.DomainsOf("OrderService"); !reflect.DeepEqual(got, []string{"Orders", "Carts"}) {
+		t.Errorf("Unexpected domains: %v", got)
+	}
+	if got := g.DataStoresOf("OrderService"); !reflect.DeepEqual(got, []string{"orders_db"}) {
+		t.Errorf("Unexpected datastores: %v", got)
+	}
+}
+
+func TestGraph_EventsAndFan(t *testing.T) {
+	g := Build(shopModel(t, ""))
+
+	if got := g.Publishers("Order Placed"); !reflect.DeepEqual(got, []string{"Orders", "Carts"}) {
+		t.Errorf("Expected both publishers in model order, got %v", got)
+	}
+	if got := g.Consumers("Order Placed"); !reflect.DeepEqual(got, []string{"Billing"}) {
+		t.Errorf("Unexpected consumers: %v", got)
+	}
+
+	billing := ID(NodeDomain, "Billing")
+	if got := g.FanOut(billing, EdgeSync); got != 2 {
+		t.Errorf("Expected Billing to call 2 domains, got %d", got)
+	}
+	if got := g.FanIn(billing, EdgeSync); got != 1 {
+		t.Errorf("Expected 1 caller of Billing, got %d", got)
+	}
+	carts := ID(NodeDomain, "Carts")
+	if got := g.Ancestors(carts, EdgeSync); !reflect.DeepEqual(got, []NodeID{billing, ID(NodeDomain, "Orders")}) {
+		t.Errorf("Unexpected transitive callers of Carts: %v", got)
+	}
+	if got := g.Descendants(carts, EdgePublish, EdgeConsume); !reflect.DeepEqual(got, []NodeID{billing, ID(NodeEvent, "Order Placed")}) {
+		t.Errorf("Unexpected event reach of Carts: %v", got)
+	}
+
+	// Internal self-loops are not neighbours
+	if got := g.Neighbors(ID(NodeDomain, "Orders"), EdgeInternal); len(got) != 0 {
+		t.Errorf("Expected no internal neighbours, got %v", got)
+	}
+}
+
+func TestGraph_ExternalSystems(t *testing.T) {
+	model := shopModel(t, `
+external_system Stripe {
+  protocol: https
+}
+
+external_system Unused {}
+
+use_case "Capture" {
+  when Customer pays order
+    Billing asks Stripe to capture
+    Stripe returns to Billing receipt
+}
+`)
+	g := Build(model)
+
+	stripe := ID(NodeExternal, "Stripe")
+	if got := g.Neighbors(ID(NodeDomain, "Billing"), EdgeSync); !reflect.DeepEqual(got, []NodeID{ID(NodeDomain, "Carts"), ID(NodeDomain, "Orders"), stripe}) {
+		t.Errorf("Expected Billing to call the external Stripe, got %v", got)
+	}
+	if got := g.Out(stripe, EdgeReturn); len(got) != 1 || got[0].To != ID(NodeDomain, "Billing") {
+		t.Errorf("Expected Stripe to return to Billing, got %+v", got)
+	}
+	if g.Has(ID(NodeDomain, "Stripe")) {
+		t.Errorf("Expected Stripe not to be a domain")
+	}
+
+	external := g.Nodes(NodeExternal)
+	if len(external) != 2 || external[0].Name != "Stripe" || !reflect.DeepEqual(external[0].UseCases, []string{"Capture"}) || len(external[1].UseCases) != 0 {
+		t.Errorf("Unexpected external nodes: %+v", external)
+	}
+}
+
+func TestGraph_PathsAndComponents(t *testing.T) {
+	g := Build(shopModel(t, ""))
+	orders, billing, carts := ID(NodeDomain, "Orders"), ID(NodeDomain, "Billing"), ID(NodeDomain, "Carts")
+
+	if got := g.ShortestPath(orders, carts, EdgeSync); !reflect.DeepEqual(got, []NodeID{orders, billing, carts}) {
+		t.Errorf("Unexpected shortest path: %v", got)
+	}
+	if got := g.ShortestPath(carts, orders, EdgeSync); got != nil {
+		t.Errorf("Expected no path from Carts, got %v", got)
+	}
+
+	paths := g.Paths(orders, orders, EdgeSync)
+	if !reflect.DeepEqual(paths, [][]NodeID{{orders, billing, orders}}) {
+		t.Errorf("Expected the Orders-Billing cycle, got %v", paths)
+	}
+
+	components := g.StronglyConnectedComponents(EdgeSync)
+	expected := [][]NodeID{{billing, orders}, {carts}}
+	if !reflect.DeepEqual(components, expected) {
+		t.Errorf("Expected components %v, got %v", expected, components)
+	}
+	if cycles := g.Cycles(EdgeSync); !reflect.DeepEqual(cycles, [][]NodeID{{billing, orders}}) {
+		t.Errorf("Unexpected cycles: %v", cycles)
+	}
+}
+
+func TestGraph_ServiceViewAndLongestPath(t *testing.T) {
+	g := Build(shopModel(t, ""))
+
+	view := g.ServiceView(EdgeSync)
+	orderService, billingService := ID(NodeService, "OrderService"), ID(NodeService, "BillingService")
+	if got := view.Neighbors(orderService); !reflect.DeepEqual(got, []NodeID{billingService}) {
+		t.Errorf("Unexpected OrderService dependencies: %v", got)
+	}
+	// Orders->Billing, Billing->Carts and Billing->Orders all cross the service boundary
+	if got := len(view.Edges()); got != 3 {
+		t.Errorf("Expected 3 projected edges, got %d", got)
+	}
+
+	reorder := g.Subgraph(func(edge Edge) bool { return edge.UseCase == "Reorder" })
+	if reorder.Has(ID(NodeDomain, "Billing")) {
+		t.Errorf("Expected Reorder subgraph to leave out Billing")
+	}
+
+	orders, billing, carts := ID(NodeDomain, "Orders"), ID(NodeDomain, "Billing"), ID(NodeDomain, "Carts")
+	if got := g.LongestPath(EdgeSync); !reflect.DeepEqual(got, []NodeID{orders, billing, carts}) {
+		t.Errorf("Unexpected longest path: %v", got)
+	}
+	if got := reorder.LongestPath(EdgeSync); got != nil {
+		t.Errorf("Expected no sync path in Reorder, got %v", got)
+	}
+}
+
+// ladder builds layers of two domains each calling both domains of the next layer, so the paths from
+// the first layer to the last double with every layer
+func ladder(layers int) *Graph {
+	g := New()
+	domain := func(layer, side int) NodeID {
+		return g.AddNode(NodeDomain, fmt.Sprintf("L%02d%c", layer, 'a'+side))
+	}
+	for layer := 0; layer < layers-1; layer++ {
+		for from := 0; from < 2; from++ {
+			for to := 0; to < 2; to++ {
+				g.AddEdge(Edge{From: domain(layer, from), To: domain(layer+1, to), Kind: EdgeSync})
+			}
+		}
+	}
+	return g
+}
+
+func TestGraph_PathsAreBounded(t *testing.T) {
+	g := ladder(40)
+	first, last := ID(NodeDomain, "L00a"), ID(NodeDomain, "L39a")
+
+	paths := g.Paths(first, last, EdgeSync)
+	if len(paths) != MaxPaths {
+		t.Fatalf("Expected the search to stop at %d paths, got %d", MaxPaths, len(paths))
+	}
+	if got := paths[0]; len(got) != 40 || got[1] != ID(NodeDomain, "L01a") {
+		t.Errorf("Expected the lexically first path to come first, got %v", got)
+	}
+}
+
+func TestGraph_LongestPathIsLinearOutsideCycles(t *testing.T) {
+	g := ladder(40)
+
+	path := g.LongestPath(EdgeSync)
+	if len(path) != 40 || path[0] != ID(NodeDomain, "L00a") || path[39] != ID(NodeDomain, "L39a") {
+		t.Errorf("Expected the lexically smallest path through every layer, got %v", path)
+	}
+}
+
+func TestGraph_LongestPathThroughLargeCycle(t *testing.T) {
+	g := ladder(40)
+	// Closing the ladder makes one strongly connected component of all but its two ends
+	g.AddEdge(Edge{From: ID(NodeDomain, "L39b"), To: ID(NodeDomain, "L00b"), Kind: EdgeSync})
+
+	path := g.LongestPath(EdgeSync)
+	if len(path) < 40 {
+		t.Fatalf("Expected at least a path through every layer, got %v", path)
+	}
+	seen := make(map[NodeID]bool)
+	for i, id := range path {
+		if seen[id] {
+			t.Fatalf("Expected a simple path, %s repeats in %v", id, path)
+		}
+		seen[id] = true
+		if i > 0 && !slices.Contains(g.Neighbors(path[i-1], EdgeSync), id) {
+			t.Fatalf("Expected consecutive nodes to be connected, got %v", path)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tcarcao/craft/internal/diff"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// ParseFunc parses Craft source into a model
+type ParseFunc func(source string) (*parser.DSLModel, error)
+
+// Revision describes a single git commit
+type Revision struct {
+	Hash      string    `json:"hash"`
+	ShortHash string    `json:"short_hash"`
+	Author    string    `json:"author"`
+	Date      time.Time `json:"date"`
+	Subject   string    `json:"subject"`
+}
+
+// Entry holds the architectural changes introduced by one revision
+type Entry struct {
+	Revision Revision      `json:"revision"`
+	Changes  []diff.Change `json:"changes,omitempty"`
+	Error    string        `json:"error,omitempty"` // Set when the revision's model could not be parsed
+}
+
+// Changelog walks the git history of a workspace and diffs the model at each revision
+type Changelog struct {
+	repoDir string
+	paths   []string
+	parse   ParseFunc
+}
+
+// NewChangelog creates a changelog for the Craft files under paths (the whole repository if empty)
+func NewChangelog(repoDir string, paths []string, parse ParseFunc) *Changelog {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	return &Changelog{repoDir: repoDir, paths: paths, parse: parse}
+}
+
+// Since returns one entry per revision after since (all revisions if empty) that changed the model.
+// Revisions whose Craft files do not parse are reported with an error and do not advance the baseline.
+func (c *Changelog) Since(since string) ([]Entry, error) {
+	revisions, err := c.revisions(since)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &parser.DSLModel{}
+	if since != "" {
+		if baseline, err = c.modelAt(since); err != nil {
+			return nil, fmt.Errorf("failed to load model at %s: %v", since, err)
+		}
+	}
+
+	entries := make([]Entry, 0)
+	for _, revision := range revisions {
+		model, err := c.modelAt(revision.Hash)
+		if err != nil {
+			entries = append(entries, Entry{Revision: revision, Error: err.Error()})
+			continue
+		}
+
+		modelDiff := diff.Compare(baseline, model)
+		baseline = model
+		if modelDiff.IsEmpty() {
+			continue
+		}
+		entries = append(entries, Entry{Revision: revision, Changes: modelDiff.Changes})
+	}
+
+	return entries, nil
+}
+
+// revisions lists the commits touching the watched paths, oldest first. Only first parents are
+// followed, so each commit is diffed against its own parent and a merged branch shows as its merge.
+func (c *Changelog) revisions(since string) ([]Revision, error) {
+	args := []string{"log", "--first-parent", "--reverse", "--format=%H%x1f%h%x1f%an%x1f%aI%x1f%s"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	}
+	args = append(args, "--")
+	args = append(args, c.paths...)
+
+	output, err := c.git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]Revision, 0)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date %q: %v", fields[3], err)
+		}
+
+		revisions = append(revisions, Revision{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Author:    fields[2],
+			Date:      date,
+			Subject:   fields[4],
+		})
+	}
+	return revisions, nil
+}
+
+// modelAt parses all Craft files under the watched paths at a revision as a single workspace
+func (c *Changelog) modelAt(revision string) (*parser.DSLModel, error) {
+	// Full names are relative to the repository root, as git show expects, even from a subdirectory
+	args := append([]string{"ls-tree", "-r", "--name-only", "--full-name", revision, "--"}, c.paths...)
+	output, err := c.git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var source strings.Builder
+	for _, file := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasSuffix(file, ".craft") {
+			continue
+		}
+
+		content, err := c.git("show", revision+":"+file)
+		if err != nil {
+			return nil, err
+		}
+		source.WriteString(content)
+		source.WriteString("\n")
+	}
+
+	model, err := c.parse(source.String())
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// git runs a git command in the repository and returns its standard output
+func (c *Changelog) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", c.repoDir}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v, stderr: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// FormatText renders entries as a plain text changelog, one block per revision
+func FormatText(entries []Entry) string {
+	if len(entries) == 0 {
+		return "No architectural changes\n"
+	}
+
+	var sb strings.Builder
+	for i, entry := range entries {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s %s %s: %s\n",
+			entry.Revision.ShortHash, entry.Revision.Date.Format("2006-01-02"), entry.Revision.Author, entry.Revision.Subject))
+
+		if entry.Error != "" {
+			sb.WriteString(fmt.Sprintf("  ! model could not be parsed: %s\n", entry.Error))
+			continue
+		}
+		for _, change := range entry.Changes {
+			sb.WriteString(fmt.Sprintf("  - %s\n", change.Sentence()))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,160 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// fakeParse reads a line based stand-in for Craft source:
+// "service <name> <domain>..." and "asks <domain> <target>"
+func fakeParse(source string) (*parser.DSLModel, error) {
+	model := &parser.DSLModel{}
+	scenario := parser.Scenario{}
+
+	for _, line := range strings.Split(source, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "service":
+			model.Services = append(model.Services, parser.Service{Name: fields[1], Domains: fields[2:]})
+		case "asks":
+			scenario.Actions = append(scenario.Actions, parser.Action{Type: parser.ActionTypeSync, Domain: fields[1], TargetDomain: fields[2]})
+		}
+	}
+
+	model.UseCases = []parser.UseCase{{Name: "All", Scenarios: []parser.Scenario{scenario}}}
+	return model, nil
+}
+
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", message)
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func newRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	commitFile(t, dir, "system.craft", "service Billing Billing\n", "Initial model")
+	runGit(t, dir, "tag", "v1.0")
+	commitFile(t, dir, "README.md", "docs\n", "Docs only")
+	commitFile(t, dir, "system.craft", "service Billing Billing\nservice Ledger Ledger\nasks Billing Ledger\n", "Add ledger")
+	return dir
+}
+
+func TestChangelog_Since(t *testing.T) {
+	dir := newRepo(t)
+
+	entries, err := NewChangelog(dir, nil, fakeParse).Since("v1.0")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+
+	entry := entries[0]
+	if entry.Revision.Subject != "Add ledger" || entry.Revision.Author != "Test" {
+		t.Errorf("Unexpected revision %+v", entry.Revision)
+	}
+
+	sentences := make([]string, 0)
+	for _, change := range entry.Changes {
+		sentences = append(sentences, change.Sentence())
+	}
+	expected := []string{"Service Ledger added", "Billing now depends on Ledger (sync)"}
+	if strings.Join(sentences, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, sentences)
+	}
+}
+
+func TestChangelog_FromFirstCommit(t *testing.T) {
+	dir := newRepo(t)
+
+	entries, err := NewChangelog(dir, []string{"system.craft"}, fakeParse).Since("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Revision.Subject != "Initial model" {
+		t.Fatalf("Expected initial and ledger entries, got %+v", entries)
+	}
+
+	text := FormatText(entries)
+	if !strings.Contains(text, "  - Service Billing added\n") || !strings.Contains(text, ": Add ledger\n") {
+		t.Errorf("Unexpected text changelog:\n%s", text)
+	}
+}
+
+func TestChangelog_MergedBranch(t *testing.T) {
+	dir := newRepo(t)
+	runGit(t, dir, "checkout", "-q", "-b", "stock", "v1.0")
+	commitFile(t, dir, "stock.craft", "service Stock Stock\n", "Add stock")
+	runGit(t, dir, "checkout", "-q", "-")
+	runGit(t, dir, "merge", "-q", "--no-ff", "-m", "Merge stock", "stock")
+
+	entries, err := NewChangelog(dir, nil, fakeParse).Since("v1.0")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	subjects := make([]string, 0)
+	for _, entry := range entries {
+		subjects = append(subjects, entry.Revision.Subject)
+		for _, change := range entry.Changes {
+			if strings.Contains(change.Sentence(), "removed") {
+				t.Errorf("Expected no removals, %s reports %q", entry.Revision.Subject, change.Sentence())
+			}
+		}
+	}
+	if strings.Join(subjects, "|") != "Add ledger|Merge stock" {
+		t.Fatalf("Expected the ledger commit then the merge, got %v", subjects)
+	}
+	if sentence := entries[1].Changes[0].Sentence(); sentence != "Service Stock added" {
+		t.Errorf("Expected the merge to add Stock, got %q", sentence)
+	}
+}
+
+func TestChangelog_Subdirectory(t *testing.T) {
+	dir := newRepo(t)
+	models := filepath.Join(dir, "models")
+	if err := os.Mkdir(models, 0755); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, models, "payments.craft", "service Payments Payments\n", "Add payments")
+
+	entries, err := NewChangelog(models, nil, fakeParse).Since("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Error != "" || entries[0].Changes[0].Sentence() != "Service Payments added" {
+		t.Errorf("Expected the payments model to load from the subdirectory, got %+v", entries)
+	}
+}
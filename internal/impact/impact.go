@@ -0,0 +1,295 @@
+package impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+// ScenarioRef identifies a scenario touching the domain
+type ScenarioRef struct {
+	UseCase  string `json:"use_case"`
+	Scenario int    `json:"scenario"` // Index of the scenario within its use case
+	ID       string `json:"id,omitempty"`
+	Trigger  string `json:"trigger"`
+}
+
+// Dependent is a domain relying on the changed domain, with the operations it relies on
+type Dependent struct {
+	Domain     string   `json:"domain"`
+	Service    string   `json:"service,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+}
+
+// EventImpact is an event the domain publishes and the domains consuming it
+type EventImpact struct {
+	Event     string      `json:"event"`
+	Consumers []Dependent `json:"consumers"`
+}
+
+// Route is an exposure routing requests to the domain
+type Route struct {
+	Exposure string   `json:"exposure"`
+	Gateways []string `json:"gateways"`
+	Actors   []string `json:"actors,omitempty"`
+}
+
+// Report lists what depends on a domain and would be affected by changing it.
+// Upstream holds the domains reaching it through sync calls, directly or not;
+// Downstream the domains it reaches through sync calls and events.
+type Report struct {
+	Domain      string        `json:"domain"`
+	Service     string        `json:"service,omitempty"`
+	Scenarios   []ScenarioRef `json:"scenarios"`
+	SyncCallers []Dependent   `json:"sync_callers"`
+	Events      []EventImpact `json:"events"`
+	Routes      []Route       `json:"routes"`
+	Upstream    []string      `json:"upstream"`
+	Downstream  []string      `json:"downstream"`
+	Services    []string      `json:"services"` // Services owning the domain or any domain up or downstream
+
+	model *parser.DSLModel
+	graph *graph.Graph
+}
+
+// Analyze walks the interaction graph around a domain
+func Analyze(model *parser.DSLModel, domain string) (*Report, error) {
+	modelGraph := graph.Build(model)
+	id := graph.ID(graph.NodeDomain, domain)
+	if !modelGraph.Has(id) {
+		return nil, fmt.Errorf("unknown domain %s", domain)
+	}
+
+	report := &Report{
+		Domain:      domain,
+		Service:     modelGraph.ServiceOf(domain),
+		Scenarios:   scenarios(model, modelGraph, id),
+		SyncCallers: dependents(modelGraph, modelGraph.In(id, graph.EdgeSync), func(edge graph.Edge) graph.NodeID { return edge.From }),
+		Events:      make([]EventImpact, 0),
+		Routes:      routes(model, modelGraph, id),
+		Upstream:    domainNames(modelGraph.Ancestors(id, graph.EdgeSync)),
+		Downstream:  domainNames(modelGraph.Descendants(id, graph.EdgeSync, graph.EdgePublish, graph.EdgeConsume)),
+		model:       model,
+		graph:       modelGraph,
+	}
+
+	for _, event := range modelGraph.Neighbors(id, graph.EdgePublish) {
+		consumers := dependents(modelGraph, modelGraph.Out(event, graph.EdgeConsume), func(edge graph.Edge) graph.NodeID { return edge.To })
+		report.Events = append(report.Events, EventImpact{Event: event.Name(), Consumers: consumers})
+	}
+
+	services := make([]string, 0)
+	for _, name := range append([]string{domain}, append(report.Upstream, report.Downstream...)...) {
+		if service := modelGraph.ServiceOf(name); service != "" && !slices.Contains(services, service) {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	report.Services = services
+
+	return report, nil
+}
+
+// scenarios returns the scenarios with an interaction involving the domain, in model order
+func scenarios(model *parser.DSLModel, modelGraph *graph.Graph, id graph.NodeID) []ScenarioRef {
+	touched := make(map[string]map[int]bool)
+	for _, edge := range append(modelGraph.Out(id), modelGraph.In(id)...) {
+		if edge.Scenario < 0 {
+			continue
+		}
+		if touched[edge.UseCase] == nil {
+			touched[edge.UseCase] = make(map[int]bool)
+		}
+		touched[edge.UseCase][edge.Scenario] = true
+	}
+
+	refs := make([]ScenarioRef, 0)
+	for _, useCase := range model.UseCases {
+		for i, scenario := range useCase.Scenarios {
+			if touched[useCase.Name][i] {
+				refs = append(refs, ScenarioRef{UseCase: useCase.Name, Scenario: i, ID: scenario.ID, Trigger: scenario.Trigger.Description})
+			}
+		}
+	}
+	return refs
+}
+
+// dependents groups edges by the domain at the given end, sorted by domain, excluding self calls
+func dependents(modelGraph *graph.Graph, edges []graph.Edge, end func(graph.Edge) graph.NodeID) []Dependent {
+	byDomain := make(map[string]*Dependent)
+	for _, edge := range edges {
+		if edge.From == edge.To {
+			continue
+		}
+
+		name := end(edge).Name()
+		dependent, exists := byDomain[name]
+		if !exists {
+			dependent = &Dependent{Domain: name, Service: modelGraph.ServiceOf(name)}
+			byDomain[name] = dependent
+		}
+		if edge.Kind == graph.EdgeSync && edge.Label != "" && !slices.Contains(dependent.Operations, edge.Label) {
+			dependent.Operations = append(dependent.Operations, edge.Label)
+		}
+	}
+
+	result := make([]Dependent, 0, len(byDomain))
+	for _, dependent := range byDomain {
+		result = append(result, *dependent)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Domain < result[j].Domain })
+	return result
+}
+
+// routes returns the exposures of the domain and the gateways they go through, in model order
+func routes(model *parser.DSLModel, modelGraph *graph.Graph, id graph.NodeID) []Route {
+	gateways := make(map[string][]string)
+	for _, edge := range modelGraph.In(id, graph.EdgeExposes) {
+		gateways[edge.Label] = append(gateways[edge.Label], edge.From.Name())
+	}
+
+	result := make([]Route, 0)
+	for _, exposure := range model.Exposures {
+		if slices.Contains(exposure.Of, id.Name()) {
+			result = append(result, Route{Exposure: exposure.Name, Gateways: gateways[exposure.Name], Actors: exposure.To})
+		}
+	}
+	return result
+}
+
+func domainNames(ids []graph.NodeID) []string {
+	names := make([]string, 0)
+	for _, id := range ids {
+		if id.Kind() == graph.NodeDomain {
+			names = append(names, id.Name())
+		}
+	}
+	return names
+}
+
+// Model returns the model the report was computed from
+func (r *Report) Model() *parser.DSLModel {
+	return r.model
+}
+
+// Highlights marks the domain as changed and its blast radius as impacted: the domains up and
+// downstream, the events it leads to and the interactions along the way
+func (r *Report) Highlights() *visualizer.Highlights {
+	highlights := visualizer.NewHighlights()
+	highlights.Mark(visualizer.HighlightDomain, r.Domain, visualizer.HighlightChanged)
+
+	upstream := map[string]bool{r.Domain: true}
+	for _, domain := range r.Upstream {
+		upstream[domain] = true
+		highlights.Mark(visualizer.HighlightDomain, domain, visualizer.HighlightImpacted)
+	}
+
+	id := graph.ID(graph.NodeDomain, r.Domain)
+	downstream := map[graph.NodeID]bool{id: true}
+	for _, reached := range r.graph.Descendants(id, graph.EdgeSync, graph.EdgePublish, graph.EdgeConsume) {
+		downstream[reached] = true
+		switch reached.Kind() {
+		case graph.NodeDomain:
+			highlights.Mark(visualizer.HighlightDomain, reached.Name(), visualizer.HighlightImpacted)
+		case graph.NodeEvent:
+			highlights.Mark(visualizer.HighlightEvent, reached.Name(), visualizer.HighlightImpacted)
+		}
+	}
+
+	for _, edge := range r.graph.Edges(graph.EdgeSync, graph.EdgePublish, graph.EdgeConsume) {
+		from, to := edge.From.Name(), edge.To.Name()
+		if edge.From == edge.To {
+			continue
+		}
+		if edge.Kind == graph.EdgeSync && upstream[from] && upstream[to] || downstream[edge.From] && downstream[edge.To] {
+			highlights.MarkRelation(from, to, visualizer.HighlightImpacted)
+		}
+	}
+
+	return highlights
+}
+
+// JSON renders the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Text renders the report for the terminal
+func (r *Report) Text() string {
+	var sb strings.Builder
+
+	if r.Service != "" {
+		sb.WriteString(fmt.Sprintf("Impact of changing %s (%s)\n", r.Domain, r.Service))
+	} else {
+		sb.WriteString(fmt.Sprintf("Impact of changing %s\n", r.Domain))
+	}
+
+	sb.WriteString("\nScenarios:\n")
+	if len(r.Scenarios) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, scenario := range r.Scenarios {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", scenario.UseCase, scenario.Trigger))
+	}
+
+	sb.WriteString("\nSync callers:\n")
+	if len(r.SyncCallers) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, caller := range r.SyncCallers {
+		sb.WriteString(fmt.Sprintf("  %s (%s): %s\n", caller.Domain, serviceLabel(caller.Service), strings.Join(caller.Operations, ", ")))
+	}
+
+	sb.WriteString("\nPublished events:\n")
+	if len(r.Events) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, event := range r.Events {
+		consumers := make([]string, 0, len(event.Consumers))
+		for _, consumer := range event.Consumers {
+			consumers = append(consumers, fmt.Sprintf("%s (%s)", consumer.Domain, serviceLabel(consumer.Service)))
+		}
+		if len(consumers) == 0 {
+			consumers = append(consumers, "no consumers")
+		}
+		sb.WriteString(fmt.Sprintf("  %q -> %s\n", event.Event, strings.Join(consumers, ", ")))
+	}
+
+	sb.WriteString("\nRoutes:\n")
+	if len(r.Routes) == 0 {
+		sb.WriteString("  none\n")
+	}
+	for _, route := range r.Routes {
+		sb.WriteString(fmt.Sprintf("  %s through %s", route.Exposure, strings.Join(route.Gateways, ", ")))
+		if len(route.Actors) > 0 {
+			sb.WriteString(fmt.Sprintf(" to %s", strings.Join(route.Actors, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("\nUpstream: %s\n", listOrNone(r.Upstream)))
+	sb.WriteString(fmt.Sprintf("Downstream: %s\n", listOrNone(r.Downstream)))
+	sb.WriteString(fmt.Sprintf("Services: %s\n", listOrNone(r.Services)))
+
+	return sb.String()
+}
+
+func serviceLabel(service string) string {
+	if service == "" {
+		return "no service"
+	}
+	return service
+}
+
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
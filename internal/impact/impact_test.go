@@ -0,0 +1,127 @@
+package impact
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/visualizer"
+)
+
+const auth = `services {
+  IdentityService {
+    domains: Authentication, Users
+  }
+  OrderService {
+    domains: Orders
+  }
+  AuditService {
+    domains: Audit
+  }
+}
+
+exposure LoginAPI {
+  to: Customer
+  of: Authentication
+  through: APIGateway
+}
+
+use_case "Login" {
+  when Customer enters credentials
+    Authentication asks Users to load credentials
+    Authentication notifies "User Logged In"
+
+  when Audit listens "User Logged In"
+    Audit records login
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Authentication to verify token
+    Orders asks Authentication to verify token
+}
+`
+
+func authModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(auth)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestAnalyze(t *testing.T) {
+	report, err := Analyze(authModel(t), "Authentication")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.Service != "IdentityService" {
+		t.Errorf("Expected IdentityService, got %q", report.Service)
+	}
+	if len(report.Scenarios) != 2 || report.Scenarios[0].UseCase != "Login" || report.Scenarios[1].UseCase != "Checkout" {
+		t.Errorf("Expected the Login and Checkout scenarios, got %+v", report.Scenarios)
+	}
+
+	expectedCallers := []Dependent{{Domain: "Orders", Service: "OrderService", Operations: []string{"verify token"}}}
+	if !reflect.DeepEqual(report.SyncCallers, expectedCallers) {
+		t.Errorf("Expected callers %+v, got %+v", expectedCallers, report.SyncCallers)
+	}
+
+	expectedEvents := []EventImpact{{Event: "User Logged In", Consumers: []Dependent{{Domain: "Audit", Service: "AuditService"}}}}
+	if !reflect.DeepEqual(report.Events, expectedEvents) {
+		t.Errorf("Expected events %+v, got %+v", expectedEvents, report.Events)
+	}
+
+	expectedRoutes := []Route{{Exposure: "LoginAPI", Gateways: []string{"APIGateway"}, Actors: []string{"Customer"}}}
+	if !reflect.DeepEqual(report.Routes, expectedRoutes) {
+		t.Errorf("Expected routes %+v, got %+v", expectedRoutes, report.Routes)
+	}
+
+	if !reflect.DeepEqual(report.Upstream, []string{"Orders"}) || !reflect.DeepEqual(report.Downstream, []string{"Audit", "Users"}) {
+		t.Errorf("Unexpected upstream %v or downstream %v", report.Upstream, report.Downstream)
+	}
+	if !reflect.DeepEqual(report.Services, []string{"AuditService", "IdentityService", "OrderService"}) {
+		t.Errorf("Unexpected services: %v", report.Services)
+	}
+
+	if _, err := Analyze(authModel(t), "Payments"); err == nil {
+		t.Error("Expected an error for an unknown domain")
+	}
+}
+
+func TestReport_HighlightsAndText(t *testing.T) {
+	report, err := Analyze(authModel(t), "Authentication")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	highlights := report.Highlights()
+	if tag := highlights.Tag(visualizer.HighlightDomain, "Authentication"); tag != visualizer.HighlightChanged {
+		t.Errorf("Expected the domain to be changed, got %q", tag)
+	}
+	for _, domain := range []string{"Orders", "Users", "Audit"} {
+		if tag := highlights.Tag(visualizer.HighlightDomain, domain); tag != visualizer.HighlightImpacted {
+			t.Errorf("Expected %s to be impacted, got %q", domain, tag)
+		}
+	}
+	for _, relation := range [][2]string{{"Orders", "Authentication"}, {"Authentication", "User Logged In"}, {"User Logged In", "Audit"}} {
+		if tag := highlights.RelationTag(relation[0], relation[1]); tag != visualizer.HighlightImpacted {
+			t.Errorf("Expected %s -> %s to be impacted, got %q", relation[0], relation[1], tag)
+		}
+	}
+
+	text := report.Text()
+	for _, expected := range []string{
+		"Impact of changing Authentication (IdentityService)",
+		"Orders (OrderService): verify token",
+		`"User Logged In" -> Audit (AuditService)`,
+		"LoginAPI through APIGateway to Customer",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected text to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tcarcao/craft/internal/formatter"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// SpecFormat identifies the kind of API specification being imported
+type SpecFormat string
+
+const (
+	SpecOpenAPI  SpecFormat = "openapi"
+	SpecAsyncAPI SpecFormat = "asyncapi"
+)
+
+// clientActor is the actor used for stub use cases triggered through an API
+const clientActor = "Client"
+
+// ImportFile reads a local OpenAPI or AsyncAPI spec (JSON or YAML) and returns a starter model
+func ImportFile(format SpecFormat, path string) (*parser.DSLModel, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %v", err)
+	}
+	return Import(format, content)
+}
+
+// Import converts the spec content into a starter model
+func Import(format SpecFormat, content []byte) (*parser.DSLModel, error) {
+	var spec map[string]interface{}
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %v", err)
+	}
+
+	switch format {
+	case SpecOpenAPI:
+		return importOpenAPI(spec), nil
+	case SpecAsyncAPI:
+		return importAsyncAPI(spec), nil
+	}
+	return nil, fmt.Errorf("unsupported spec format %q", format)
+}
+
+// ImportToCraft converts a spec file into canonical Craft source
+func ImportToCraft(format SpecFormat, path string) (string, error) {
+	model, err := ImportFile(format, path)
+	if err != nil {
+		return "", err
+	}
+	return formatter.Format(model), nil
+}
+
+// =============================================================================
+// OpenAPI
+// =============================================================================
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPIOperation is an operation with the service serving it
+type openAPIOperation struct {
+	operation map[string]interface{}
+	service   string
+	endpoint  string
+}
+
+// importOpenAPI maps server groups to services, tags to domains of the services whose operations use them
+// and operations to stub use cases
+func importOpenAPI(spec map[string]interface{}) *parser.DSLModel {
+	model := newModel()
+	builder := newServiceBuilder(titleService(spec))
+	specService := builder.serviceFor(openAPIServers(spec))
+
+	// Operation and path servers override the spec servers
+	operations := make([]openAPIOperation, 0)
+	tagServices := make(map[string]string)
+	paths := mapValue(spec, "paths")
+	for _, path := range sortedKeys(paths) {
+		pathItem := mapValue(paths, path)
+		pathService := specService
+		if servers := openAPIServers(pathItem); len(servers) > 0 {
+			pathService = builder.serviceFor(servers)
+		}
+		for _, method := range httpMethods {
+			operation := mapValue(pathItem, method)
+			if operation == nil {
+				continue
+			}
+			service := pathService
+			if servers := openAPIServers(operation); len(servers) > 0 {
+				service = builder.serviceFor(servers)
+			}
+			if _, ok := tagServices[firstTag(operation)]; !ok {
+				tagServices[firstTag(operation)] = service
+			}
+			operations = append(operations, openAPIOperation{
+				operation: operation,
+				service:   service,
+				endpoint:  fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+			})
+		}
+	}
+
+	// Declared tags become domains in declaration order, even when no operation uses them, and belong to
+	// the service of the first operation using them
+	if tags, ok := spec["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if tagMap, ok := tag.(map[string]interface{}); ok && stringValue(tagMap, "name") != "" {
+				name := stringValue(tagMap, "name")
+				service, used := tagServices[name]
+				if !used {
+					service = specService
+				}
+				builder.domainFor(service, name)
+			}
+		}
+	}
+
+	for _, op := range operations {
+		operation, endpoint := op.operation, op.endpoint
+		domain := builder.domainFor(op.service, firstTag(operation))
+
+		model.UseCases = append(model.UseCases, parser.UseCase{
+			Name: operationTitle(operation, endpoint),
+			Scenarios: []parser.Scenario{
+				{
+					Trigger: parser.Trigger{
+						Type:   parser.TriggerTypeExternal,
+						Actor:  clientActor,
+						Verb:   "calls",
+						Phrase: endpoint,
+					},
+					Actions: []parser.Action{
+						{Type: parser.ActionTypeInternal, Domain: domain, Verb: "handles", Phrase: phraseFor(operationTitle(operation, endpoint))},
+						{Type: parser.ActionTypeReturn, Domain: domain, Phrase: "response"},
+					},
+				},
+			},
+		})
+	}
+
+	if len(model.UseCases) > 0 {
+		model.Actors = append(model.Actors, parser.Actor{Name: clientActor, Type: parser.ActorTypeUser})
+	}
+	model.Services = builder.services()
+	return model
+}
+
+// =============================================================================
+// AsyncAPI
+// =============================================================================
+
+// importAsyncAPI maps server groups to services, channels to events and send/receive operations to stub
+// use cases. Both AsyncAPI 2.x (publish/subscribe under channels) and 3.x (top-level operations) are supported.
+func importAsyncAPI(spec map[string]interface{}) *parser.DSLModel {
+	model := newModel()
+	builder := newServiceBuilder(titleService(spec))
+
+	servers := mapValue(spec, "servers")
+	channels := mapValue(spec, "channels")
+	operations := mapValue(spec, "operations")
+	sends := false
+
+	if operations != nil {
+		// AsyncAPI 3.x: operations reference channels and declare send/receive
+		for _, operationID := range sortedKeys(operations) {
+			operation := mapValue(operations, operationID)
+			event := channelEvent(channels, channelRef(operation))
+			if event == "" {
+				continue
+			}
+			service := builder.serviceFor(channelServers(servers, mapValue(channels, channelRef(operation))))
+			domain := builder.domainFor(service, firstTag(operation))
+			send := stringValue(operation, "action") == "send"
+			sends = sends || send
+			model.UseCases = append(model.UseCases, asyncUseCase(operationTitle(operation, operationID), domain, event, send))
+		}
+	} else {
+		// AsyncAPI 2.x: "subscribe" means the application sends, "publish" means it receives
+		for _, channelName := range sortedKeys(channels) {
+			channel := mapValue(channels, channelName)
+			event := channelEvent(channels, channelName)
+			for _, kind := range []string{"subscribe", "publish"} {
+				operation := mapValue(channel, kind)
+				if operation == nil {
+					continue
+				}
+				service := builder.serviceFor(channelServers(servers, channel))
+				domain := builder.domainFor(service, firstTag(operation))
+				title := operationTitle(operation, fmt.Sprintf("%s %s", kind, channelName))
+				sends = sends || kind == "subscribe"
+				model.UseCases = append(model.UseCases, asyncUseCase(title, domain, event, kind == "subscribe"))
+			}
+		}
+	}
+
+	if sends {
+		model.Actors = append(model.Actors, parser.Actor{Name: clientActor, Type: parser.ActorTypeUser})
+	}
+	model.Services = builder.services()
+	return model
+}
+
+// asyncUseCase builds the stub use case for a send or receive operation. Nothing in the spec says what
+// makes the application send, so send stubs are triggered by the client for the author to fill in.
+func asyncUseCase(title, domain, event string, sends bool) parser.UseCase {
+	var scenario parser.Scenario
+	if sends {
+		scenario = parser.Scenario{
+			Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: clientActor, Verb: "triggers", Phrase: phraseFor(title)},
+			Actions: []parser.Action{
+				{Type: parser.ActionTypeAsync, Domain: domain, Event: event},
+			},
+		}
+	} else {
+		scenario = parser.Scenario{
+			Trigger: parser.Trigger{Type: parser.TriggerTypeDomainListen, Domain: domain, Event: event},
+			Actions: []parser.Action{
+				{Type: parser.ActionTypeInternal, Domain: domain, Verb: "handles", Phrase: phraseFor(event)},
+			},
+		}
+	}
+
+	return parser.UseCase{Name: title, Scenarios: []parser.Scenario{scenario}}
+}
+
+// channelRef resolves the channel name referenced by an AsyncAPI 3.x operation
+func channelRef(operation map[string]interface{}) string {
+	return refName(stringValue(mapValue(operation, "channel"), "$ref"))
+}
+
+// refName returns the name a JSON reference such as #/channels/orderPlaced points to
+func refName(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	parts := strings.Split(ref, "/")
+	return strings.ReplaceAll(parts[len(parts)-1], "~1", "/")
+}
+
+// channelServers returns the URLs of the servers a channel is available on, or of all servers when it
+// names none. AsyncAPI 2.x lists server names, 3.x references them; 3.x uses "host" instead of "url".
+func channelServers(servers, channel map[string]interface{}) []string {
+	names := make([]string, 0)
+	if list, ok := channel["servers"].([]interface{}); ok {
+		for _, entry := range list {
+			switch server := entry.(type) {
+			case string:
+				names = append(names, server)
+			case map[string]interface{}:
+				names = append(names, refName(stringValue(server, "$ref")))
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = sortedKeys(servers)
+	}
+
+	urls := make([]string, 0, len(names))
+	for _, name := range names {
+		server := mapValue(servers, name)
+		if serverURL := stringValue(server, "url"); serverURL != "" {
+			urls = append(urls, serverURL)
+		} else if host := stringValue(server, "host"); host != "" {
+			urls = append(urls, host)
+		}
+	}
+	return urls
+}
+
+// channelEvent returns the event name of a channel: its address (3.x) or its key (2.x)
+func channelEvent(channels map[string]interface{}, channelName string) string {
+	if channelName == "" {
+		return ""
+	}
+	if address := stringValue(mapValue(channels, channelName), "address"); address != "" {
+		return address
+	}
+	return channelName
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// serviceBuilder collects the services discovered while importing a spec, with the domains each owns
+type serviceBuilder struct {
+	fallback string              // service of the operations no server names
+	names    []string            // services in discovery order
+	domains  map[string][]string // domains by service
+	owned    map[string]bool
+}
+
+func newServiceBuilder(fallback string) *serviceBuilder {
+	return &serviceBuilder{
+		fallback: fallback,
+		names:    make([]string, 0),
+		domains:  make(map[string][]string),
+		owned:    make(map[string]bool),
+	}
+}
+
+// serviceFor returns the service of the server group the first named server belongs to, or the fallback
+// service when no server names one
+func (b *serviceBuilder) serviceFor(serverURLs []string) string {
+	for _, serverURL := range serverURLs {
+		if name := serverGroup(serverURL); name != "" {
+			return name
+		}
+	}
+	return b.fallback
+}
+
+// domainFor returns the domain for a tag, falling back to the service name for untagged operations.
+// A new domain is owned by the service; a domain is owned by the first service using it.
+func (b *serviceBuilder) domainFor(service, tag string) string {
+	domain := identifierFor(tag)
+	if tag == "" {
+		domain = strings.TrimSuffix(service, "Service")
+		if domain == "" {
+			domain = service
+		}
+	}
+	if !b.owned[domain] {
+		b.owned[domain] = true
+		if _, ok := b.domains[service]; !ok {
+			b.names = append(b.names, service)
+		}
+		b.domains[service] = append(b.domains[service], domain)
+	}
+	return domain
+}
+
+func (b *serviceBuilder) services() []parser.Service {
+	services := make([]parser.Service, 0, len(b.names))
+	for _, name := range b.names {
+		services = append(services, parser.Service{
+			Name:       name,
+			Domains:    b.domains[name],
+			DataStores: make([]string, 0),
+		})
+	}
+	return services
+}
+
+func newModel() *parser.DSLModel {
+	return &parser.DSLModel{
+		Architectures: make([]parser.Architecture, 0),
+		Exposures:     make([]parser.Exposure, 0),
+		Services:      make([]parser.Service, 0),
+		UseCases:      make([]parser.UseCase, 0),
+		Domains:       make([]parser.Domain, 0),
+		Actors:        make([]parser.Actor, 0),
+	}
+}
+
+// titleService names the service of a spec without servers from info.title
+func titleService(spec map[string]interface{}) string {
+	if title := stringValue(mapValue(spec, "info"), "title"); title != "" {
+		return identifierFor(title)
+	}
+	return "ImportedService"
+}
+
+// openAPIServers returns the URLs of the servers declared on a spec, path item or operation
+func openAPIServers(element map[string]interface{}) []string {
+	servers, _ := element["servers"].([]interface{})
+	urls := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if serverMap, ok := server.(map[string]interface{}); ok && stringValue(serverMap, "url") != "" {
+			urls = append(urls, stringValue(serverMap, "url"))
+		}
+	}
+	return urls
+}
+
+// genericHostLabels are host name labels naming an environment or an entry point rather than a service
+var genericHostLabels = map[string]bool{
+	"api": true, "www": true, "localhost": true,
+	"dev": true, "development": true, "test": true, "qa": true, "uat": true,
+	"staging": true, "stage": true, "sandbox": true, "prod": true, "production": true,
+}
+
+// serverGroup names the service behind a server from the first label of its host that does not name an
+// environment or entry point, so that api.orders.example.com and staging.orders.example.com form the
+// group Orders. Hosts without a scheme, as in AsyncAPI 3.x, are accepted; templated labels are skipped.
+func serverGroup(serverURL string) string {
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Hostname() == "" {
+		parsed, err = url.Parse("//" + serverURL)
+	}
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+
+	for _, label := range strings.Split(parsed.Hostname(), ".") {
+		if label == "" || strings.ContainsAny(label, "{}") || genericHostLabels[strings.ToLower(label)] {
+			continue
+		}
+		return identifierFor(label)
+	}
+	return ""
+}
+
+// operationTitle returns a human readable title for an operation
+func operationTitle(operation map[string]interface{}, fallback string) string {
+	if summary := stringValue(operation, "summary"); summary != "" {
+		return summary
+	}
+	if operationID := stringValue(operation, "operationId"); operationID != "" {
+		return operationID
+	}
+	return fallback
+}
+
+// firstTag returns the name of the first tag of an operation
+func firstTag(operation map[string]interface{}) string {
+	tags, ok := operation["tags"].([]interface{})
+	if !ok || len(tags) == 0 {
+		return ""
+	}
+	switch tag := tags[0].(type) {
+	case string:
+		return tag
+	case map[string]interface{}:
+		return stringValue(tag, "name")
+	}
+	return ""
+}
+
+// identifierFor converts free text into a PascalCase Craft identifier
+func identifierFor(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		sb.WriteRune(unicode.ToUpper(runes[0]))
+		sb.WriteString(string(runes[1:]))
+	}
+	return formatter.Identifier(sb.String())
+}
+
+// phraseFor lowercases free text into phrase words
+func phraseFor(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, " ")
+}
+
+func mapValue(m map[string]interface{}, key string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	value, _ := m[key].(map[string]interface{})
+	return value
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	if m == nil {
+		return ""
+	}
+	value, _ := m[key].(string)
+	return value
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,254 @@
+package importer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/formatter"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const petStoreSpec = `
+openapi: 3.0.0
+info:
+  title: Pet Store
+  version: 1.0.0
+tags:
+  - name: pets
+  - name: store orders
+paths:
+  /pets:
+    get:
+      summary: List all pets
+      tags: [pets]
+    post:
+      operationId: createPet
+      tags: [pets]
+  /store/orders/{id}:
+    delete:
+      summary: Cancel order
+      tags: [store orders]
+`
+
+func TestImport_OpenAPI(t *testing.T) {
+	model, err := Import(SpecOpenAPI, []byte(petStoreSpec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Services) != 1 || model.Services[0].Name != "PetStore" {
+		t.Fatalf("Expected single service PetStore, got %+v", model.Services)
+	}
+
+	expectedDomains := []string{"Pets", "StoreOrders"}
+	if !slices.Equal(model.Services[0].Domains, expectedDomains) {
+		t.Errorf("Expected domains %v, got %v", expectedDomains, model.Services[0].Domains)
+	}
+
+	if len(model.UseCases) != 3 {
+		t.Fatalf("Expected 3 use cases, got %d", len(model.UseCases))
+	}
+
+	listPets := model.UseCases[0]
+	if listPets.Name != "List all pets" {
+		t.Errorf("Expected use case 'List all pets', got '%s'", listPets.Name)
+	}
+	trigger := listPets.Scenarios[0].Trigger
+	if trigger.Actor != "Client" || trigger.Phrase != "GET /pets" {
+		t.Errorf("Unexpected trigger %+v", trigger)
+	}
+	if listPets.Scenarios[0].Actions[0].Domain != "Pets" {
+		t.Errorf("Expected action on Pets, got %+v", listPets.Scenarios[0].Actions[0])
+	}
+
+	if model.UseCases[1].Name != "createPet" {
+		t.Errorf("Expected operationId fallback 'createPet', got '%s'", model.UseCases[1].Name)
+	}
+}
+
+func TestImport_AsyncAPI2(t *testing.T) {
+	spec := `{
+  "asyncapi": "2.6.0",
+  "info": {"title": "Account Events", "version": "1.0.0"},
+  "channels": {
+    "user/signedup": {
+      "subscribe": {"summary": "User signed up", "tags": [{"name": "Accounts"}]}
+    },
+    "payment/received": {
+      "publish": {"operationId": "onPayment", "tags": [{"name": "Billing"}]}
+    }
+  }
+}`
+
+	model, err := Import(SpecAsyncAPI, []byte(spec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.UseCases) != 2 {
+		t.Fatalf("Expected 2 use cases, got %d", len(model.UseCases))
+	}
+
+	// Channels are sorted: payment/received (publish = receive) comes first
+	received := model.UseCases[0].Scenarios[0]
+	if received.Trigger.Type != parser.TriggerTypeDomainListen || received.Trigger.Event != "payment/received" || received.Trigger.Domain != "Billing" {
+		t.Errorf("Unexpected receive scenario trigger %+v", received.Trigger)
+	}
+
+	sent := model.UseCases[1].Scenarios[0]
+	if sent.Actions[0].Type != parser.ActionTypeAsync || sent.Actions[0].Event != "user/signedup" || sent.Actions[0].Domain != "Accounts" {
+		t.Errorf("Unexpected send scenario action %+v", sent.Actions[0])
+	}
+	if sent.Trigger.Type != parser.TriggerTypeExternal || sent.Trigger.Actor != "Client" || sent.Trigger.Phrase != "user signed up" {
+		t.Errorf("Expected the client to trigger the send, got %+v", sent.Trigger)
+	}
+	if len(model.Actors) != 1 || model.Actors[0].Name != "Client" {
+		t.Errorf("Expected the Client actor, got %+v", model.Actors)
+	}
+}
+
+func TestImport_AsyncAPI3(t *testing.T) {
+	spec := `
+asyncapi: 3.0.0
+info:
+  title: Notifications
+  version: 1.0.0
+channels:
+  orderPlaced:
+    address: orders.placed
+operations:
+  notifyOrder:
+    action: receive
+    channel:
+      $ref: '#/channels/orderPlaced'
+`
+
+	model, err := Import(SpecAsyncAPI, []byte(spec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.UseCases) != 1 {
+		t.Fatalf("Expected 1 use case, got %d", len(model.UseCases))
+	}
+
+	trigger := model.UseCases[0].Scenarios[0].Trigger
+	if trigger.Event != "orders.placed" || trigger.Domain != "Notifications" {
+		t.Errorf("Unexpected trigger %+v", trigger)
+	}
+}
+
+func TestImport_OpenAPIServers(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  title: Shop
+  version: 1.0.0
+servers:
+  - url: https://api.orders.example.com/v1
+  - url: https://staging.orders.example.com/v1
+tags:
+  - name: carts
+  - name: payments
+  - name: refunds
+paths:
+  /carts:
+    get:
+      tags: [carts]
+  /payments:
+    servers:
+      - url: https://billing.example.com
+    post:
+      tags: [payments]
+  /refunds:
+    post:
+      tags: [refunds]
+      servers:
+        - url: https://api.billing.example.com
+  /health:
+    get:
+      summary: Health check
+`
+
+	model, err := Import(SpecOpenAPI, []byte(spec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := map[string][]string{"Orders": {"Carts", "Orders"}, "Billing": {"Payments", "Refunds"}}
+	if len(model.Services) != len(expected) {
+		t.Fatalf("Expected services %v, got %+v", expected, model.Services)
+	}
+	for _, service := range model.Services {
+		if !slices.Equal(service.Domains, expected[service.Name]) {
+			t.Errorf("Expected %s to own %v, got %v", service.Name, expected[service.Name], service.Domains)
+		}
+	}
+}
+
+func TestImport_AsyncAPIServers(t *testing.T) {
+	spec := `
+asyncapi: 3.0.0
+info:
+  title: Events
+  version: 1.0.0
+servers:
+  orders:
+    host: orders.example.com:9092
+  shipping:
+    host: shipping.example.com:9092
+channels:
+  orderPlaced:
+    address: orders.placed
+    servers:
+      - $ref: '#/servers/orders'
+  parcelSent:
+    address: parcels.sent
+    servers:
+      - $ref: '#/servers/shipping'
+operations:
+  placeOrder:
+    action: send
+    tags: [{name: Ordering}]
+    channel:
+      $ref: '#/channels/orderPlaced'
+  sendParcel:
+    action: send
+    tags: [{name: Parcels}]
+    channel:
+      $ref: '#/channels/parcelSent'
+`
+
+	model, err := Import(SpecAsyncAPI, []byte(spec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Services) != 2 {
+		t.Fatalf("Expected a service for each server, got %+v", model.Services)
+	}
+	if service := model.Services[0]; service.Name != "Orders" || !slices.Equal(service.Domains, []string{"Ordering"}) {
+		t.Errorf("Expected Orders to own Ordering, got %+v", service)
+	}
+	if service := model.Services[1]; service.Name != "Shipping" || !slices.Equal(service.Domains, []string{"Parcels"}) {
+		t.Errorf("Expected Shipping to own Parcels, got %+v", service)
+	}
+}
+
+func TestImport_ProducesCanonicalCraft(t *testing.T) {
+	model, err := Import(SpecOpenAPI, []byte(petStoreSpec))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	source := formatter.Format(model)
+	for _, expected := range []string{
+		"services {\n  PetStore {\n    domains: Pets, StoreOrders\n  }\n}",
+		"use_case \"Cancel order\" {\n  when Client calls DELETE \"/store/orders/{id}\"\n    StoreOrders handles cancel order\n    StoreOrders returns response\n}",
+	} {
+		if !strings.Contains(source, expected) {
+			t.Errorf("Expected output to contain:\n%s\nGot:\n%s", expected, source)
+		}
+	}
+}
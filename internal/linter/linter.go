@@ -0,0 +1,137 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/contextmap"
+	"github.com/tcarcao/craft/internal/datastore"
+	"github.com/tcarcao/craft/internal/exposure"
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/rules"
+	"github.com/tcarcao/craft/internal/saga"
+)
+
+// Severity of a diagnostic
+type Severity string
+
+const (
+	SeverityError Severity = "error"
+)
+
+// CodeInvalidRule marks rules that cannot be evaluated against the model
+const CodeInvalidRule = "invalid_rule"
+
+// Diagnostic is a single finding reported at a source location
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	UseCase  string   `json:"use_case,omitempty"`
+}
+
+// Source is a parsed Craft file
+type Source struct {
+	File  string
+	Model *parser.DSLModel
+}
+
+// Lint evaluates the fitness rules of the model, plus those of any separate rules files, against
+// the model's use cases, and checks its sagas, context map, data stores and actor exposures.
+// Violations point into the model file; invalid rules point at the rule itself.
+func Lint(model Source, ruleFiles ...Source) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	for _, ruleSource := range append([]Source{model}, ruleFiles...) {
+		checked := *model.Model
+		checked.Rules = ruleSource.Model.Rules
+
+		for _, violation := range rules.NewEngine(&checked).Evaluate() {
+			diagnostic := Diagnostic{
+				File:     model.File,
+				Line:     violation.Line,
+				Severity: SeverityError,
+				Code:     string(violation.Rule.Type),
+				Message:  violation.Message,
+				UseCase:  violation.UseCase,
+			}
+			if violation.Invalid {
+				diagnostic.File = ruleSource.File
+				diagnostic.Code = CodeInvalidRule
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	for _, issue := range saga.Validate(model.Model) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     model.File,
+			Line:     issue.Line,
+			Severity: SeverityError,
+			Code:     issue.Code,
+			Message:  issue.Message,
+		})
+	}
+
+	for _, issue := range contextmap.Validate(model.Model) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     model.File,
+			Line:     issue.Line,
+			Severity: SeverityError,
+			Code:     issue.Code,
+			Message:  issue.Message,
+		})
+	}
+
+	for _, issue := range datastore.Validate(model.Model) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     model.File,
+			Line:     issue.Line,
+			Severity: SeverityError,
+			Code:     issue.Code,
+			Message:  issue.Message,
+			UseCase:  issue.UseCase,
+		})
+	}
+
+	for _, issue := range exposure.Validate(model.Model) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     model.File,
+			Line:     issue.Line,
+			Severity: SeverityError,
+			Code:     issue.Code,
+			Message:  issue.Message,
+			UseCase:  issue.UseCase,
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].File != diagnostics[j].File {
+			return diagnostics[i].File < diagnostics[j].File
+		}
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+	return diagnostics
+}
+
+// FormatText renders diagnostics in the conventional "file:line: severity: message" form
+func FormatText(diagnostics []Diagnostic) string {
+	var sb strings.Builder
+	for _, diagnostic := range diagnostics {
+		location := diagnostic.File
+		if diagnostic.Line > 0 {
+			location = fmt.Sprintf("%s:%d", diagnostic.File, diagnostic.Line)
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s: %s [%s]\n", location, diagnostic.Severity, diagnostic.Message, diagnostic.Code))
+	}
+	return sb.String()
+}
+
+// FormatJSON renders diagnostics as an indented JSON array
+func FormatJSON(diagnostics []Diagnostic) ([]byte, error) {
+	return json.MarshalIndent(diagnostics, "", "  ")
+}
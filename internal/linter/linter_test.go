@@ -0,0 +1,173 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/contextmap"
+	"github.com/tcarcao/craft/internal/datastore"
+	"github.com/tcarcao/craft/internal/exposure"
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/saga"
+)
+
+func TestLint_ReportsViolationsAndInvalidRulesPerFile(t *testing.T) {
+	model := &parser.DSLModel{
+		Services: []parser.Service{
+			{Name: "NotificationService", Domains: []string{"Notification"}},
+			{Name: "OrderService", Domains: []string{"Orders"}},
+		},
+		UseCases: []parser.UseCase{
+			{
+				Name: "Checkout",
+				Scenarios: []parser.Scenario{
+					{
+						Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "places", Phrase: "order", Line: 4},
+						Actions: []parser.Action{
+							{Type: parser.ActionTypeSync, Domain: "Notification", TargetDomain: "Orders", Phrase: "load order", Line: 5},
+						},
+					},
+				},
+			},
+		},
+		Rules: []parser.Rule{
+			{Type: parser.RuleTypeNoSync, From: "Notification", To: parser.RuleScopeAny, Text: "no_sync from Notification to any", Line: 9},
+		},
+	}
+	rulesModel := &parser.DSLModel{
+		Rules: []parser.Rule{
+			{Type: parser.RuleTypeMaxSyncHops, Limit: -1, Text: "max_sync_hops few", Line: 2},
+		},
+	}
+
+	diagnostics := Lint(Source{File: "shop.craft", Model: model}, Source{File: "rules.craft", Model: rulesModel})
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %d: %+v", len(diagnostics), diagnostics)
+	}
+
+	invalid, violation := diagnostics[0], diagnostics[1]
+	if invalid.File != "rules.craft" || invalid.Line != 2 || invalid.Code != CodeInvalidRule {
+		t.Errorf("Unexpected invalid rule diagnostic: %+v", invalid)
+	}
+	if violation.File != "shop.craft" || violation.Line != 5 || violation.Code != string(parser.RuleTypeNoSync) {
+		t.Errorf("Unexpected violation diagnostic: %+v", violation)
+	}
+
+	text := FormatText(diagnostics)
+	if !strings.Contains(text, "shop.craft:5: error: sync call from Notification to Orders") {
+		t.Errorf("Unexpected text output:\n%s", text)
+	}
+}
+
+func TestLint_RulesFromOtherFilesDoNotChangeModel(t *testing.T) {
+	model := &parser.DSLModel{}
+	rulesModel := &parser.DSLModel{Rules: []parser.Rule{{Type: parser.RuleTypeMaxSyncHops, Limit: 1}}}
+
+	if diagnostics := Lint(Source{File: "a.craft", Model: model}, Source{File: "r.craft", Model: rulesModel}); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %+v", diagnostics)
+	}
+	if len(model.Rules) != 0 {
+		t.Errorf("Expected the linted model to be left untouched, got %+v", model.Rules)
+	}
+}
+
+func TestLint_ReportsSagaStepsWithoutCompensation(t *testing.T) {
+	model := &parser.DSLModel{
+		Sagas: []parser.Saga{
+			{
+				Name: "Money Transfer",
+				Steps: []parser.SagaStep{
+					{Action: parser.Action{Description: "Payments debits account", Line: 3}},
+					{Action: parser.Action{Description: "Notification sends receipt", Line: 4, Modifiers: []parser.ComponentModifier{{Key: parser.SagaModifierRetriable}}}},
+				},
+			},
+		},
+	}
+
+	diagnostics := Lint(Source{File: "bank.craft", Model: model})
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if diagnostic := diagnostics[0]; diagnostic.File != "bank.craft" || diagnostic.Line != 3 || diagnostic.Code != saga.CodeMissingCompensation {
+		t.Errorf("Unexpected saga diagnostic: %+v", diagnostic)
+	}
+}
+
+func TestLint_ReportsInvalidContextMapEntries(t *testing.T) {
+	model := &parser.DSLModel{
+		Domains: []parser.Domain{{Name: "Sales"}, {Name: "Billing"}},
+		ContextMap: []parser.ContextRelation{
+			{From: "Sales", To: "Billing", Patterns: []string{"acl"}, Line: 5},
+			{From: "Sales", To: "Billing", Patterns: []string{"friendship"}, Line: 6},
+		},
+	}
+
+	diagnostics := Lint(Source{File: "shop.craft", Model: model})
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if diagnostic := diagnostics[0]; diagnostic.Line != 6 || diagnostic.Code != contextmap.CodeUnknownPattern {
+		t.Errorf("Unexpected context map diagnostic: %+v", diagnostic)
+	}
+}
+
+func TestLint_ReportsSharedDataStores(t *testing.T) {
+	model := &parser.DSLModel{
+		Services: []parser.Service{
+			{Name: "AccountService", DataStores: []string{"account_db"}},
+			{Name: "ReportService", DataStores: []string{"account_db"}},
+		},
+		DataStores: []parser.DataStore{{Name: "account_db", Type: "postgres", Line: 12}},
+	}
+
+	diagnostics := Lint(Source{File: "bank.craft", Model: model})
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if diagnostic := diagnostics[0]; diagnostic.Line != 12 || diagnostic.Code != datastore.CodeSharedDataStore {
+		t.Errorf("Unexpected data store diagnostic: %+v", diagnostic)
+	}
+}
+
+func TestLint_ReportsDataAccessToUnknownStores(t *testing.T) {
+	model := &parser.DSLModel{
+		Services: []parser.Service{{Name: "CatalogService", Domains: []string{"Catalog"}, DataStores: []string{"catalog_db"}}},
+		UseCases: []parser.UseCase{{
+			Name: "Browse",
+			Scenarios: []parser.Scenario{{
+				Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "opens", Phrase: "catalog"},
+				// "Catalog reads from cache prices" once was an internal action of Catalog
+				Actions: []parser.Action{{Type: parser.ActionTypeDataAccess, Domain: "Catalog", DataStore: "cache", Access: parser.DataAccessRead, Phrase: "prices", Line: 4}},
+			}},
+		}},
+	}
+
+	diagnostics := Lint(Source{File: "shop.craft", Model: model})
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if diagnostic := diagnostics[0]; diagnostic.Line != 4 || diagnostic.Code != datastore.CodeUnknownDataStore || diagnostic.UseCase != "Browse" {
+		t.Errorf("Unexpected data store diagnostic: %+v", diagnostic)
+	}
+}
+
+func TestLint_ReportsUnexposedDomains(t *testing.T) {
+	model := &parser.DSLModel{
+		Exposures: []parser.Exposure{{Name: "PublicAPI", To: []string{"Customer"}, Of: []string{"Orders"}}},
+		UseCases: []parser.UseCase{{
+			Name: "Refund",
+			Scenarios: []parser.Scenario{{
+				Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "requests", Phrase: "refund", Line: 9},
+				Actions: []parser.Action{{Type: parser.ActionTypeInternal, Domain: "Billing", Verb: "refunds", Phrase: "payment"}},
+			}},
+		}},
+	}
+
+	diagnostics := Lint(Source{File: "shop.craft", Model: model})
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if diagnostic := diagnostics[0]; diagnostic.Line != 9 || diagnostic.Code != exposure.CodeUnexposedDomain || diagnostic.UseCase != "Refund" {
+		t.Errorf("Unexpected exposure diagnostic: %+v", diagnostic)
+	}
+}
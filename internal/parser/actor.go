@@ -0,0 +1,11 @@
+package parser
+
+// Actor returns the actor with the given name, or nil when it is not declared
+func (m *DSLModel) Actor(name string) *Actor {
+	for i := range m.Actors {
+		if m.Actors[i].Name == name {
+			return &m.Actors[i]
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/tcarcao/craft/pkg/parser"
 )
 
@@ -10,9 +12,12 @@ func (b *DSLModelBuilder) VisitActor_def(ctx *parser.Actor_defContext) interface
 	actorName := b.extractActorName(ctx.Actor_name())
 
 	actor := Actor{
-		Name: actorName,
-		Type: actorType,
+		Name:     actorName,
+		Type:     actorType,
+		Metadata: b.extractMetadata(ctx.Annotations()),
+		Line:     ctx.Actor_name().GetStart().GetLine(),
 	}
+	b.extractActorBody(ctx.Actor_body(), &actor)
 
 	b.model.Actors = append(b.model.Actors, actor)
 	return nil
@@ -40,14 +45,63 @@ func (b *DSLModelBuilder) VisitActor_definition(ctx *parser.Actor_definitionCont
 	actorName := b.extractActorName(ctx.Actor_name())
 
 	actor := Actor{
-		Name: actorName,
-		Type: actorType,
+		Name:     actorName,
+		Type:     actorType,
+		Metadata: b.extractMetadata(ctx.Annotations()),
+		Line:     ctx.Actor_name().GetStart().GetLine(),
 	}
+	b.extractActorBody(ctx.Actor_body(), &actor)
 
 	b.model.Actors = append(b.model.Actors, actor)
 	return nil
 }
 
+// extractActorBody fills the optional details of an actor: description, persona, uses, roles and permissions
+func (b *DSLModelBuilder) extractActorBody(ctx parser.IActor_bodyContext, actor *Actor) {
+	if ctx == nil {
+		return
+	}
+	properties := ctx.(*parser.Actor_bodyContext).Actor_properties()
+	if properties == nil {
+		return
+	}
+
+	for _, property := range properties.(*parser.Actor_propertiesContext).AllActor_property() {
+		propertyCtx := property.(*parser.Actor_propertyContext)
+
+		names := make([]string, 0)
+		for _, identifier := range propertyCtx.AllIdentifier() {
+			names = append(names, identifier.GetText())
+		}
+
+		// The keyword opening the property tells the alternatives apart
+		switch propertyCtx.GetStart().GetText() {
+		case "description":
+			actor.Description = strings.Trim(propertyCtx.STRING().GetText(), "\"")
+		case "persona":
+			if attributes := propertyCtx.Persona_attributes(); attributes != nil {
+				for _, attribute := range attributes.(*parser.Persona_attributesContext).AllPersona_attribute() {
+					attributeCtx := attribute.(*parser.Persona_attributeContext)
+					identifiers := attributeCtx.AllIdentifier()
+					value := ""
+					if str := attributeCtx.STRING(); str != nil {
+						value = strings.Trim(str.GetText(), "\"")
+					} else if len(identifiers) > 1 {
+						value = identifiers[1].GetText()
+					}
+					actor.Persona = append(actor.Persona, PersonaAttribute{Name: identifiers[0].GetText(), Value: value})
+				}
+			}
+		case "uses":
+			actor.Uses = append(actor.Uses, names...)
+		case "roles":
+			actor.Roles = append(actor.Roles, names...)
+		case "permissions":
+			actor.Permissions = append(actor.Permissions, names...)
+		}
+	}
+}
+
 // extractActorType extracts and validates the actor type from the ActorType context
 func (b *DSLModelBuilder) extractActorType(ctx *parser.ActorTypeContext) ActorType {
 	if ctx == nil {
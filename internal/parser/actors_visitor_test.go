@@ -1,6 +1,8 @@
 package parser
 
-import (	"testing"
+import (
+	"reflect"
+	"testing"
 )
 
 func TestParser_IndividualActor(t *testing.T) {
@@ -113,3 +115,52 @@ func TestParser_MixedActorDefinitions(t *testing.T) {
 	}
 }
 
+func TestParser_ActorDetails(t *testing.T) {
+	dsl := `actor user Customer {
+		description: "Buys products online"
+		persona {
+			goal: "Check out quickly"
+			expertise: novice
+		}
+		uses: PublicAPI, MobileAPI
+		roles: shopper
+		permissions: place_order, view_orders
+	}
+
+	actors {
+		user Clerk { description: "Handles returns" }
+		system Scheduler
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []Actor{
+		{
+			Name:        "Customer",
+			Type:        ActorTypeUser,
+			Description: "Buys products online",
+			Persona:     []PersonaAttribute{{Name: "goal", Value: "Check out quickly"}, {Name: "expertise", Value: "novice"}},
+			Uses:        []string{"PublicAPI", "MobileAPI"},
+			Roles:       []string{"shopper"},
+			Permissions: []string{"place_order", "view_orders"},
+			Line:        1,
+		},
+		{Name: "Clerk", Type: ActorTypeUser, Description: "Handles returns", Line: 13},
+		{Name: "Scheduler", Type: ActorTypeSystem, Line: 14},
+	}
+	if !reflect.DeepEqual(model.Actors, expected) {
+		t.Errorf("Unexpected actors:\nExpected %+v\nGot      %+v", expected, model.Actors)
+	}
+
+	if actor := model.Actor("Clerk"); actor == nil || actor.Description != "Handles returns" {
+		t.Errorf("Expected to find Clerk, got %+v", actor)
+	}
+	if actor := model.Actor("Nobody"); actor != nil {
+		t.Errorf("Expected no actor named Nobody, got %+v", actor)
+	}
+}
@@ -29,14 +29,20 @@ func NewDSLModelBuilder() *DSLModelBuilder {
 			UseCases:      make([]UseCase, 0),
 			Domains:       make([]Domain, 0),
 			Actors:        make([]Actor, 0),
+			Rules:         make([]Rule, 0),
+			Sagas:         make([]Saga, 0),
+			ContextMap:    make([]ContextRelation, 0),
+			Environments:  make([]Environment, 0),
+			DataStores:    make([]DataStore, 0),
 		},
 		idCounter: 0,
 	}
 }
 
 func (b *DSLModelBuilder) GetModel() *DSLModel {
-	// Apply service merging before returning the model
+	// Apply service merging and link data stores to their owners before returning the model
 	b.model.Services = MergeServices(b.model.Services)
+	b.model.linkDataStores()
 	return b.model
 }
 
@@ -68,6 +74,18 @@ func (b *DSLModelBuilder) VisitDsl(ctx *parser.DslContext) interface{} {
 			b.VisitActor_def(c)
 		case *parser.Actors_defContext:
 			b.VisitActors_def(c)
+		case *parser.Rules_defContext:
+			b.VisitRules_def(c)
+		case *parser.SagaContext:
+			b.VisitSaga(c)
+		case *parser.Context_mapContext:
+			b.VisitContext_map(c)
+		case *parser.EnvironmentContext:
+			b.VisitEnvironment(c)
+		case *parser.Datastores_defContext:
+			b.VisitDatastores_def(c)
+		case *parser.External_systemContext:
+			b.VisitExternal_system(c)
 		}
 	}
 	return nil
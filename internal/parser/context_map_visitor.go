@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Context Map Visitors
+// =============================================================================
+
+// Visit context map block
+func (b *DSLModelBuilder) VisitContext_map(ctx *parser.Context_mapContext) interface{} {
+	if relationList := ctx.Context_relation_list(); relationList != nil {
+		b.VisitContext_relation_list(relationList.(*parser.Context_relation_listContext))
+	}
+	return nil
+}
+
+// Visit context relation list
+func (b *DSLModelBuilder) VisitContext_relation_list(ctx *parser.Context_relation_listContext) interface{} {
+	for _, relation := range ctx.AllContext_relation() {
+		b.VisitContext_relation(relation.(*parser.Context_relationContext))
+	}
+	return nil
+}
+
+// Visit a single relation: identifier '->' identifier (':' context_pattern (',' context_pattern)*)?
+func (b *DSLModelBuilder) VisitContext_relation(ctx *parser.Context_relationContext) interface{} {
+	relation := ContextRelation{
+		Patterns: make([]string, 0),
+		Line:     ctx.GetStart().GetLine(),
+	}
+
+	contexts := ctx.AllIdentifier()
+	if len(contexts) == 2 {
+		relation.From = contexts[0].GetText()
+		relation.To = contexts[1].GetText()
+	}
+
+	for _, pattern := range ctx.AllContext_pattern() {
+		relation.Patterns = append(relation.Patterns, pattern.GetText())
+	}
+
+	b.model.ContextMap = append(b.model.ContextMap, relation)
+	return nil
+}
+
+// Context map visitor stubs
+func (b *DSLModelBuilder) VisitContext_pattern(ctx *parser.Context_patternContext) interface{} {
+	return nil
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_ContextMap(t *testing.T) {
+	dsl := `domains {
+		Sales {
+			Orders
+		}
+		Billing {
+			Invoicing
+		}
+	}
+
+	context_map {
+		Sales -> Billing : customer-supplier, acl
+		Billing -> Payments : conformist
+		Sales -> Catalog
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []ContextRelation{
+		{From: "Sales", To: "Billing", Patterns: []string{"customer-supplier", "acl"}, Line: 11},
+		{From: "Billing", To: "Payments", Patterns: []string{"conformist"}, Line: 12},
+		{From: "Sales", To: "Catalog", Patterns: []string{}, Line: 13},
+	}
+	if !reflect.DeepEqual(model.ContextMap, expected) {
+		t.Errorf("Unexpected context map:\nExpected %+v\nGot      %+v", expected, model.ContextMap)
+	}
+}
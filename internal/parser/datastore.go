@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"slices"
+)
+
+// DataStore returns the declared data store with the given name, or nil when it is not declared
+func (m *DSLModel) DataStore(name string) *DataStore {
+	for i := range m.DataStores {
+		if m.DataStores[i].Name == name {
+			return &m.DataStores[i]
+		}
+	}
+	return nil
+}
+
+// DataStoreUsers returns the services using a data store: those listing it in their data-stores,
+// in declaration order, then those its declaration shares it with
+func (m *DSLModel) DataStoreUsers(name string) []string {
+	users := make([]string, 0)
+	for _, service := range m.Services {
+		if slices.Contains(service.DataStores, name) {
+			users = append(users, service.Name)
+		}
+	}
+	if dataStore := m.DataStore(name); dataStore != nil {
+		for _, service := range dataStore.SharedBy {
+			if !slices.Contains(users, service) {
+				users = append(users, service)
+			}
+		}
+	}
+	return users
+}
+
+// WithServices returns a copy of the model holding the services, with the owners of its data stores
+// linked to them
+func (m *DSLModel) WithServices(services []Service) *DSLModel {
+	result := *m
+	result.Services = services
+	result.DataStores = slices.Clone(m.DataStores)
+	result.linkDataStores()
+	return &result
+}
+
+// linkDataStores sets the owner of each declared data store to the first service listing it
+func (m *DSLModel) linkDataStores() {
+	for i := range m.DataStores {
+		m.DataStores[i].Owner = ""
+		for _, service := range m.Services {
+			if slices.Contains(service.DataStores, m.DataStores[i].Name) {
+				m.DataStores[i].Owner = service.Name
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDataStoreOwnership(t *testing.T) {
+	model := &DSLModel{
+		Services: []Service{
+			{Name: "AccountService", DataStores: []string{"account_db"}},
+			{Name: "ReportService", DataStores: []string{"account_db", "report_db"}},
+		},
+		DataStores: []DataStore{
+			{Name: "account_db", Type: "postgres", SharedBy: []string{"AuditService", "ReportService"}},
+			{Name: "report_db"},
+			{Name: "archive_bucket", Type: "s3"},
+		},
+		Environments: []Environment{
+			{Name: "staging", Services: []Service{{Name: "ReportService", DataStores: []string{"archive_bucket"}}}},
+		},
+	}
+	model.linkDataStores()
+
+	owners := make([]string, 0)
+	for _, dataStore := range model.DataStores {
+		owners = append(owners, dataStore.Owner)
+	}
+	if !reflect.DeepEqual(owners, []string{"AccountService", "ReportService", ""}) {
+		t.Errorf("Unexpected owners: %v", owners)
+	}
+
+	if users := model.DataStoreUsers("account_db"); !reflect.DeepEqual(users, []string{"AccountService", "ReportService", "AuditService"}) {
+		t.Errorf("Unexpected account_db users: %v", users)
+	}
+	if users := model.DataStoreUsers("undeclared_db"); len(users) != 0 {
+		t.Errorf("Expected no users of an unknown store, got %v", users)
+	}
+	if model.DataStore("report_db") == nil || model.DataStore("undeclared_db") != nil {
+		t.Errorf("Unexpected data store lookup results")
+	}
+
+	// Environment overrides move stores between services without touching the base model
+	staging, err := model.ForEnvironment("staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if owner := staging.DataStore("archive_bucket").Owner; owner != "ReportService" {
+		t.Errorf("Expected ReportService to own archive_bucket in staging, got %q", owner)
+	}
+	if owner := staging.DataStore("report_db").Owner; owner != "" {
+		t.Errorf("Expected report_db to have no owner in staging, got %q", owner)
+	}
+	if owner := model.DataStore("report_db").Owner; owner != "ReportService" {
+		t.Errorf("Expected the base model to keep its owners, got %q", owner)
+	}
+}
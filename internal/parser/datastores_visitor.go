@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Data Store Visitors
+// =============================================================================
+
+// Visit datastores block
+func (b *DSLModelBuilder) VisitDatastores_def(ctx *parser.Datastores_defContext) interface{} {
+	if blockList := ctx.Datastore_block_list(); blockList != nil {
+		for _, block := range blockList.(*parser.Datastore_block_listContext).AllDatastore_block() {
+			b.VisitDatastore_block(block.(*parser.Datastore_blockContext))
+		}
+	}
+	return nil
+}
+
+// Visit a single data store: name '{' properties '}'
+func (b *DSLModelBuilder) VisitDatastore_block(ctx *parser.Datastore_blockContext) interface{} {
+	dataStore := DataStore{
+		Name:     ctx.Datastore_name().GetText(),
+		SharedBy: make([]string, 0),
+		Metadata: b.extractMetadata(ctx.Annotations()),
+		Line:     ctx.Datastore_name().GetStart().GetLine(),
+	}
+
+	for _, property := range ctx.Datastore_properties().(*parser.Datastore_propertiesContext).AllDatastore_property() {
+		propertyCtx := property.(*parser.Datastore_propertyContext)
+
+		// The keyword opening the property tells the alternatives apart
+		switch propertyCtx.GetStart().GetText() {
+		case "type":
+			dataStore.Type = propertyCtx.Identifier().GetText()
+		case "engine_version":
+			dataStore.EngineVersion = propertyCtx.Identifier().GetText()
+		case "shared_by":
+			for _, serviceName := range propertyCtx.AllService_name() {
+				dataStore.SharedBy = append(dataStore.SharedBy, b.extractServiceName(serviceName.(*parser.Service_nameContext)))
+			}
+		}
+	}
+
+	b.model.DataStores = append(b.model.DataStores, dataStore)
+	return nil
+}
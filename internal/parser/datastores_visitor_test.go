@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_DataStores(t *testing.T) {
+	dsl := `services {
+		AccountService {
+			domains: Accounts
+			data-stores: account_db, session_cache
+		}
+		"Report Service" {
+			domains: Reporting
+			data-stores: account_db
+		}
+	}
+
+	datastores {
+		account_db {
+			type: postgres
+			engine_version: 15
+			shared_by: [AuditService, "Report Service"]
+		}
+		session_cache {
+			type: redis
+		}
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []DataStore{
+		{Name: "account_db", Type: "postgres", EngineVersion: "15", SharedBy: []string{"AuditService", "Report Service"}, Owner: "AccountService", Line: 13},
+		{Name: "session_cache", Type: "redis", SharedBy: []string{}, Owner: "AccountService", Line: 18},
+	}
+	if !reflect.DeepEqual(model.DataStores, expected) {
+		t.Errorf("Unexpected data stores:\nExpected %+v\nGot      %+v", expected, model.DataStores)
+	}
+
+	users := model.DataStoreUsers("account_db")
+	if !reflect.DeepEqual(users, []string{"AccountService", "Report Service", "AuditService"}) {
+		t.Errorf("Unexpected account_db users: %v", users)
+	}
+}
+
+func TestParser_DataStoresOnOneLine(t *testing.T) {
+	dsl := `datastores {
+		account_db { type: postgres, engine_version: 15, shared_by: [ReportService, AuditService] }
+		session_cache { type: redis }
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []DataStore{
+		{Name: "account_db", Type: "postgres", EngineVersion: "15", SharedBy: []string{"ReportService", "AuditService"}, Line: 2},
+		{Name: "session_cache", Type: "redis", SharedBy: []string{}, Line: 3},
+	}
+	if !reflect.DeepEqual(model.DataStores, expected) {
+		t.Errorf("Unexpected data stores:\nExpected %+v\nGot      %+v", expected, model.DataStores)
+	}
+}
+
+func TestParser_DataStoreKeywordsAsIdentifiers(t *testing.T) {
+	dsl := `use_case "Change Plan" {
+		when Customer changes plan type
+			Billing updates datastores shared_by engine_version
+	}`
+
+	parser := NewParser()
+	if _, err := parser.ParseString(dsl); err != nil {
+		t.Fatalf("Expected data store keywords to parse as words, got: %v", err)
+	}
+}
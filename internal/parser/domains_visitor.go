@@ -14,13 +14,15 @@ func (b *DSLModelBuilder) VisitDomain_def(ctx *parser.Domain_defContext) interfa
 		SubDomains: make([]string, 0),
 	}
 
-	// Extract domain name and subdomain list
+	// Extract domain name, annotations and subdomain list
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
-		if domainName, ok := child.(*parser.Domain_nameContext); ok {
+		if annotations, ok := child.(*parser.AnnotationsContext); ok {
+			domain.Metadata = b.extractMetadata(annotations)
+		} else if domainName, ok := child.(*parser.Domain_nameContext); ok {
 			domain.Name = b.extractIdentifier(&domainName.BaseParserRuleContext)
 		} else if subdomainList, ok := child.(*parser.Subdomain_listContext); ok {
-			domain.SubDomains = b.extractSubdomainList(subdomainList)
+			domain.SubDomains, domain.SubDomainMetadata = b.extractSubdomainList(subdomainList)
 		}
 	}
 
@@ -56,13 +58,15 @@ func (b *DSLModelBuilder) VisitDomain_block(ctx *parser.Domain_blockContext) int
 		SubDomains: make([]string, 0),
 	}
 
-	// Extract domain name and subdomain list
+	// Extract domain name, annotations and subdomain list
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
-		if domainName, ok := child.(*parser.Domain_nameContext); ok {
+		if annotations, ok := child.(*parser.AnnotationsContext); ok {
+			domain.Metadata = b.extractMetadata(annotations)
+		} else if domainName, ok := child.(*parser.Domain_nameContext); ok {
 			domain.Name = b.extractIdentifier(&domainName.BaseParserRuleContext)
 		} else if subdomainList, ok := child.(*parser.Subdomain_listContext); ok {
-			domain.SubDomains = b.extractSubdomainList(subdomainList)
+			domain.SubDomains, domain.SubDomainMetadata = b.extractSubdomainList(subdomainList)
 		}
 	}
 
@@ -70,9 +74,10 @@ func (b *DSLModelBuilder) VisitDomain_block(ctx *parser.Domain_blockContext) int
 	return nil
 }
 
-// Extract subdomain list from subdomain_list context
-func (b *DSLModelBuilder) extractSubdomainList(ctx *parser.Subdomain_listContext) []string {
+// Extract subdomain list from subdomain_list context, with the metadata of annotated subdomains
+func (b *DSLModelBuilder) extractSubdomainList(ctx *parser.Subdomain_listContext) ([]string, map[string]Metadata) {
 	subdomainSet := make(map[string]bool)
+	var metadata map[string]Metadata
 
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
@@ -81,6 +86,12 @@ func (b *DSLModelBuilder) extractSubdomainList(ctx *parser.Subdomain_listContext
 			if subdomainName != "" {
 				subdomainSet[subdomainName] = true
 			}
+			if annotations := subdomain.Annotations(); annotations != nil && subdomainName != "" {
+				if metadata == nil {
+					metadata = make(map[string]Metadata)
+				}
+				metadata[subdomainName] = b.extractMetadata(annotations)
+			}
 		}
 	}
 
@@ -90,7 +101,7 @@ func (b *DSLModelBuilder) extractSubdomainList(ctx *parser.Subdomain_listContext
 		subdomains = append(subdomains, subdomain)
 	}
 
-	return subdomains
+	return subdomains, metadata
 }
 
 // addOrMergeDomain adds a domain to the model or merges subdomains if domain already exists
@@ -98,8 +109,15 @@ func (b *DSLModelBuilder) addOrMergeDomain(newDomain Domain) {
 	// Check if domain already exists
 	for i := range b.model.Domains {
 		if b.model.Domains[i].Name == newDomain.Name {
-			// Domain exists, merge subdomains
+			// Domain exists, merge subdomains and metadata
 			b.model.Domains[i].SubDomains = b.mergeSubdomains(b.model.Domains[i].SubDomains, newDomain.SubDomains)
+			b.model.Domains[i].Metadata = b.model.Domains[i].Metadata.Inherit(newDomain.Metadata)
+			for name, metadata := range newDomain.SubDomainMetadata {
+				if b.model.Domains[i].SubDomainMetadata == nil {
+					b.model.Domains[i].SubDomainMetadata = make(map[string]Metadata)
+				}
+				b.model.Domains[i].SubDomainMetadata[name] = b.model.Domains[i].SubDomainMetadata[name].Inherit(metadata)
+			}
 			return
 		}
 	}
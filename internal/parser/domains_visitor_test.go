@@ -565,6 +565,36 @@ func TestParser_DuplicateSubdomainMerging(t *testing.T) {
 	}
 }
 
+func TestParser_DomainHierarchyLookup(t *testing.T) {
+	dsl := `domains {
+		Sales {
+			Orders
+			Cart
+		}
+		Billing {
+			Invoicing
+		}
+	}`
+
+	model, err := ParseDSLToModel(dsl)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if parent := model.ParentDomain("Cart"); parent != "Sales" {
+		t.Errorf("Expected Cart to belong to Sales, got '%s'", parent)
+	}
+	if parent := model.ParentDomain("Shipping"); parent != "" {
+		t.Errorf("Expected no parent for an undeclared subdomain, got '%s'", parent)
+	}
+	if subDomains := model.SubDomainsOf("Billing"); len(subDomains) != 1 || subDomains[0] != "Invoicing" {
+		t.Errorf("Expected Billing to hold Invoicing, got %v", subDomains)
+	}
+	if subDomains := model.SubDomainsOf("Orders"); subDomains != nil {
+		t.Errorf("Expected no subdomains for a subdomain, got %v", subDomains)
+	}
+}
+
 func BenchmarkParser_SingleDomainDefinition(b *testing.B) {
 	dsl := `domain BenchmarkDomain {
 		SubDomain1
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Environment returns the environment with the given name, or nil when the model declares none
+func (m *DSLModel) Environment(name string) *Environment {
+	for i := range m.Environments {
+		if m.Environments[i].Name == name {
+			return &m.Environments[i]
+		}
+	}
+	return nil
+}
+
+// ForEnvironment returns the model as deployed to the named environment: the properties its
+// service overrides set replace those of the top-level services, services only it declares are
+// added, and its arch blocks replace those with the same name. An empty name returns the model
+// unchanged.
+func (m *DSLModel) ForEnvironment(name string) (*DSLModel, error) {
+	if name == "" {
+		return m, nil
+	}
+
+	environment := m.Environment(name)
+	if environment == nil {
+		return nil, fmt.Errorf("unknown environment %s", name)
+	}
+
+	resolved := *m
+	resolved.Services = overrideServices(m.Services, environment.Services)
+	resolved.Architectures = overrideArchitectures(m.Architectures, environment.Architectures)
+
+	// Overrides can move data stores between services
+	resolved.DataStores = slices.Clone(m.DataStores)
+	resolved.linkDataStores()
+	return &resolved, nil
+}
+
+// overrideServices applies service overrides, keeping the declaration order of the base services
+func overrideServices(services, overrides []Service) []Service {
+	result := make([]Service, 0, len(services))
+	applied := make(map[string]bool)
+	for _, service := range services {
+		for _, override := range overrides {
+			if override.Name == service.Name {
+				service = overrideService(service, override)
+				applied[override.Name] = true
+			}
+		}
+		result = append(result, service)
+	}
+
+	for _, override := range overrides {
+		if !applied[override.Name] {
+			result = append(result, override)
+		}
+	}
+	return result
+}
+
+// overrideService replaces the properties of a service that the override sets
+func overrideService(service, override Service) Service {
+	if len(override.Domains) > 0 {
+		service.Domains = override.Domains
+		service.DomainModifiers = override.DomainModifiers
+	}
+	if len(override.DataStores) > 0 {
+		service.DataStores = override.DataStores
+	}
+	if override.Language != "" {
+		service.Language = override.Language
+	}
+	if override.Deployment.Type != "" {
+		service.Deployment = override.Deployment
+	}
+	if len(override.Metadata) > 0 {
+		service.Metadata = override.Metadata.Inherit(service.Metadata)
+	}
+	return service
+}
+
+// overrideArchitectures replaces arch blocks by name, unnamed ones included, and adds the rest
+func overrideArchitectures(architectures, overrides []Architecture) []Architecture {
+	result := make([]Architecture, 0, len(architectures))
+	applied := make(map[string]bool)
+	for _, arch := range architectures {
+		for _, override := range overrides {
+			if override.Name == arch.Name {
+				arch = override
+				applied[override.Name] = true
+			}
+		}
+		result = append(result, arch)
+	}
+
+	for _, override := range overrides {
+		if !applied[override.Name] {
+			result = append(result, override)
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func environmentModel() *DSLModel {
+	return &DSLModel{
+		Architectures: []Architecture{
+			{Name: "production", Gateway: []Component{{Name: "LoadBalancer", Type: ComponentTypeSimple}}},
+			{Name: "internal", Gateway: []Component{{Name: "VPN", Type: ComponentTypeSimple}}},
+		},
+		Services: []Service{
+			{
+				Name:       "OrderService",
+				Domains:    []string{"Orders"},
+				DataStores: []string{"orders_db"},
+				Language:   "go",
+				Deployment: DeploymentStrategy{Type: "canary", Rules: []DeploymentRule{{Percentage: "10%", Target: "production"}}},
+			},
+			{Name: "PaymentService", Domains: []string{"Payments"}, Language: "java"},
+		},
+		Environments: []Environment{
+			{
+				Name: "staging",
+				Services: []Service{
+					{Name: "OrderService", DataStores: []string{"orders_sqlite"}, Deployment: DeploymentStrategy{Type: "rolling"}},
+					{Name: "MockBank", Domains: []string{"Banking"}},
+				},
+				Architectures: []Architecture{
+					{Name: "production", Gateway: []Component{{Name: "Ingress", Type: ComponentTypeSimple}}},
+					{Name: "preview", Presentation: []Component{{Name: "WebApp", Type: ComponentTypeSimple}}},
+				},
+			},
+		},
+	}
+}
+
+func TestForEnvironment(t *testing.T) {
+	model := environmentModel()
+
+	staging, err := model.ForEnvironment("staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expectedServices := []Service{
+		{
+			Name:       "OrderService",
+			Domains:    []string{"Orders"},
+			DataStores: []string{"orders_sqlite"},
+			Language:   "go",
+			Deployment: DeploymentStrategy{Type: "rolling"},
+		},
+		{Name: "PaymentService", Domains: []string{"Payments"}, Language: "java"},
+		{Name: "MockBank", Domains: []string{"Banking"}},
+	}
+	if !reflect.DeepEqual(staging.Services, expectedServices) {
+		t.Errorf("Unexpected services:\nExpected %+v\nGot      %+v", expectedServices, staging.Services)
+	}
+
+	architectures := make([]string, 0)
+	for _, arch := range staging.Architectures {
+		components := append(arch.Presentation, arch.Gateway...)
+		architectures = append(architectures, arch.Name+":"+components[0].Name)
+	}
+	expectedArchitectures := []string{"production:Ingress", "internal:VPN", "preview:WebApp"}
+	if !reflect.DeepEqual(architectures, expectedArchitectures) {
+		t.Errorf("Expected architectures %v, got %v", expectedArchitectures, architectures)
+	}
+
+	// The base model is left untouched
+	if model.Services[0].DataStores[0] != "orders_db" || model.Architectures[0].Gateway[0].Name != "LoadBalancer" {
+		t.Errorf("Expected the base model to be unchanged, got %+v", model)
+	}
+}
+
+func TestForEnvironment_WithoutName(t *testing.T) {
+	model := environmentModel()
+
+	resolved, err := model.ForEnvironment("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resolved != model {
+		t.Errorf("Expected the model itself without an environment")
+	}
+}
+
+func TestForEnvironment_Unknown(t *testing.T) {
+	_, err := environmentModel().ForEnvironment("qa")
+	if err == nil || err.Error() != "unknown environment qa" {
+		t.Errorf("Expected unknown environment error, got %v", err)
+	}
+}
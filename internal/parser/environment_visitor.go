@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Environment Visitors
+// =============================================================================
+
+// Visit environment block. Its arch and service blocks are built into a model of their own and
+// kept as overrides, so they never reach the top-level services and architectures.
+func (b *DSLModelBuilder) VisitEnvironment(ctx *parser.EnvironmentContext) interface{} {
+	environment := Environment{
+		Name: ctx.Environment_name().GetText(),
+	}
+
+	base := b.model
+	b.model = &DSLModel{
+		Architectures: make([]Architecture, 0),
+		Services:      make([]Service, 0),
+	}
+
+	for i := 0; i < ctx.GetChildCount(); i++ {
+		switch c := ctx.GetChild(i).(type) {
+		case *parser.ArchContext:
+			b.VisitArch(c)
+		case *parser.Service_defContext:
+			b.VisitService_def(c)
+		case *parser.Services_defContext:
+			b.VisitServices_def(c)
+		}
+	}
+
+	environment.Architectures = b.model.Architectures
+	environment.Services = MergeServices(b.model.Services)
+
+	b.model = base
+	b.model.Environments = append(b.model.Environments, environment)
+	return nil
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_Environment(t *testing.T) {
+	dsl := `services {
+		OrderService {
+			domains: Orders
+			data-stores: orders_db
+			deployment: canary(10% -> production)
+		}
+	}
+
+	arch production {
+		gateway:
+			LoadBalancer > APIGateway
+	}
+
+	environment staging {
+		service OrderService {
+			data-stores: orders_sqlite
+			deployment: rolling
+		}
+
+		arch production {
+			gateway:
+				Ingress
+		}
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Services) != 1 || len(model.Architectures) != 1 {
+		t.Fatalf("Expected environment blocks to stay out of the top-level model, got %d services and %d architectures", len(model.Services), len(model.Architectures))
+	}
+
+	if len(model.Environments) != 1 {
+		t.Fatalf("Expected 1 environment, got %d", len(model.Environments))
+	}
+	environment := model.Environments[0]
+	if environment.Name != "staging" {
+		t.Errorf("Expected environment staging, got %s", environment.Name)
+	}
+
+	if len(environment.Services) != 1 {
+		t.Fatalf("Expected 1 service override, got %d", len(environment.Services))
+	}
+	override := environment.Services[0]
+	if override.Name != "OrderService" || !reflect.DeepEqual(override.DataStores, []string{"orders_sqlite"}) || override.Deployment.Type != "rolling" {
+		t.Errorf("Unexpected service override: %+v", override)
+	}
+	if len(override.Domains) != 0 {
+		t.Errorf("Expected the override to leave domains unset, got %v", override.Domains)
+	}
+
+	if len(environment.Architectures) != 1 || environment.Architectures[0].Name != "production" {
+		t.Fatalf("Expected the production arch override, got %+v", environment.Architectures)
+	}
+	if gateway := environment.Architectures[0].Gateway; len(gateway) != 1 || gateway[0].Name != "Ingress" {
+		t.Errorf("Expected the Ingress gateway, got %+v", gateway)
+	}
+
+	staging, err := model.ForEnvironment("staging")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if service := staging.Services[0]; !reflect.DeepEqual(service.Domains, []string{"Orders"}) || service.DataStores[0] != "orders_sqlite" {
+		t.Errorf("Unexpected staging service: %+v", service)
+	}
+}
@@ -11,9 +11,10 @@ import (
 // Visit exposure definition
 func (b *DSLModelBuilder) VisitExposure(ctx *parser.ExposureContext) interface{} {
 	exposure := Exposure{
-		To:      make([]string, 0),
-		Of:      make([]string, 0),
-		Through: make([]string, 0),
+		To:       make([]string, 0),
+		Of:       make([]string, 0),
+		Through:  make([]string, 0),
+		Metadata: b.extractMetadata(ctx.Annotations()),
 	}
 
 	// Extract exposure name and properties
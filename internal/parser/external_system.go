@@ -0,0 +1,11 @@
+package parser
+
+// ExternalSystem returns the external system with the given name, or nil when it is not declared
+func (m *DSLModel) ExternalSystem(name string) *ExternalSystem {
+	for i := range m.ExternalSystems {
+		if m.ExternalSystems[i].Name == name {
+			return &m.ExternalSystems[i]
+		}
+	}
+	return nil
+}
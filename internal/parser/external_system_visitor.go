@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// External System Visitors
+// =============================================================================
+
+// Visit external system: 'external_system' name '{' properties '}'
+func (b *DSLModelBuilder) VisitExternal_system(ctx *parser.External_systemContext) interface{} {
+	system := ExternalSystem{
+		Name:     ctx.External_system_name().GetText(),
+		Metadata: b.extractMetadata(ctx.Annotations()),
+		Line:     ctx.External_system_name().GetStart().GetLine(),
+	}
+
+	if properties := ctx.External_system_properties(); properties != nil {
+		for _, property := range properties.(*parser.External_system_propertiesContext).AllExternal_system_property() {
+			propertyCtx := property.(*parser.External_system_propertyContext)
+
+			// The keyword opening the property tells the alternatives apart
+			switch propertyCtx.GetStart().GetText() {
+			case "protocol":
+				system.Protocol = propertyCtx.Identifier().GetText()
+			case "owner":
+				system.Owner = propertyCtx.Identifier().GetText()
+			}
+		}
+	}
+
+	b.model.ExternalSystems = append(b.model.ExternalSystems, system)
+	return nil
+}
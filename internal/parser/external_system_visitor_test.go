@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_ExternalSystems(t *testing.T) {
+	dsl := `external_system Stripe { protocol: https, owner: vendor }
+
+	external_system SendGrid {
+		protocol: smtp
+		owner: marketing
+	}
+
+	external_system LegacyLedger {}
+
+	use_case "Checkout" {
+		when Customer places order
+			Payments asks Stripe to charge card
+			Stripe returns receipt
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []ExternalSystem{
+		{Name: "Stripe", Protocol: "https", Owner: "vendor", Line: 1},
+		{Name: "SendGrid", Protocol: "smtp", Owner: "marketing", Line: 3},
+		{Name: "LegacyLedger", Line: 8},
+	}
+	if !reflect.DeepEqual(model.ExternalSystems, expected) {
+		t.Errorf("Unexpected external systems:\nExpected %+v\nGot      %+v", expected, model.ExternalSystems)
+	}
+
+	if system := model.ExternalSystem("Stripe"); system == nil || system.Protocol != "https" {
+		t.Errorf("Expected to find Stripe, got %+v", system)
+	}
+	if system := model.ExternalSystem("Payments"); system != nil {
+		t.Errorf("Expected no external system named Payments, got %+v", system)
+	}
+
+	call := model.UseCases[0].Scenarios[0].Actions[0]
+	if call.Type != ActionTypeSync || call.TargetDomain != "Stripe" {
+		t.Errorf("Expected a sync call to Stripe, got %+v", call)
+	}
+}
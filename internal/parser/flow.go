@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// UseCaseSelection selects a use case, or some of its scenarios
+type UseCaseSelection struct {
+	UseCase   string   `json:"useCase"`
+	Scenarios []string `json:"scenarios,omitempty"` // IDs, trigger descriptions or indices; all scenarios when empty
+}
+
+// ParseUseCaseSelection reads a use case name, optionally followed by #scenario, e.g.
+// "Money Transfer" or "Money Transfer#1"
+func ParseUseCaseSelection(value string) UseCaseSelection {
+	name, scenario, found := strings.Cut(value, "#")
+	selection := UseCaseSelection{UseCase: strings.TrimSpace(name)}
+	if found && strings.TrimSpace(scenario) != "" {
+		selection.Scenarios = []string{strings.TrimSpace(scenario)}
+	}
+	return selection
+}
+
+// WithUseCases returns the model restricted to the selected flows, with the services and actors taking
+// part in them. Selections of the same use case add up. Without selections the model is returned
+// unchanged.
+func (m *DSLModel) WithUseCases(selections ...UseCaseSelection) (*DSLModel, error) {
+	if len(selections) == 0 {
+		return m, nil
+	}
+
+	useCases, err := m.SelectUseCases(selections...)
+	if err != nil {
+		return nil, err
+	}
+	return m.WithFlows(useCases), nil
+}
+
+// SelectUseCases returns the selected use cases in declaration order, holding only their selected
+// scenarios. An unknown use case or scenario is an error.
+func (m *DSLModel) SelectUseCases(selections ...UseCaseSelection) ([]UseCase, error) {
+	selected := make(map[string]map[int]bool)
+	for _, selection := range selections {
+		i := slices.IndexFunc(m.UseCases, func(useCase UseCase) bool { return useCase.Name == selection.UseCase })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown use case %s", selection.UseCase)
+		}
+		useCase := m.UseCases[i]
+
+		if selected[useCase.Name] == nil {
+			selected[useCase.Name] = make(map[int]bool)
+		}
+		if len(selection.Scenarios) == 0 {
+			for index := range useCase.Scenarios {
+				selected[useCase.Name][index] = true
+			}
+		}
+		for _, name := range selection.Scenarios {
+			index := scenarioIndex(useCase, name)
+			if index < 0 {
+				return nil, fmt.Errorf("use case %s has no scenario %s", useCase.Name, name)
+			}
+			selected[useCase.Name][index] = true
+		}
+	}
+
+	useCases := make([]UseCase, 0, len(selected))
+	for _, useCase := range m.UseCases {
+		indices, ok := selected[useCase.Name]
+		if !ok {
+			continue
+		}
+		scenarios := make([]Scenario, 0, len(indices))
+		for index, scenario := range useCase.Scenarios {
+			if indices[index] {
+				scenarios = append(scenarios, scenario)
+			}
+		}
+		useCase.Scenarios = scenarios
+		useCases = append(useCases, useCase)
+	}
+	return useCases, nil
+}
+
+// WithFlows returns a copy of the model holding the use cases, with the services owning a domain they
+// involve and the actors triggering them
+func (m *DSLModel) WithFlows(useCases []UseCase) *DSLModel {
+	domains := make(map[string]bool)
+	actors := make(map[string]bool)
+	for _, useCase := range useCases {
+		for _, scenario := range useCase.Scenarios {
+			domains[scenario.Trigger.Domain] = true
+			actors[scenario.Trigger.Actor] = true
+			for _, action := range scenario.Actions {
+				domains[action.Domain] = true
+				domains[action.TargetDomain] = true
+			}
+		}
+	}
+
+	services := make([]Service, 0)
+	for _, service := range m.Services {
+		if slices.ContainsFunc(service.Domains, func(domain string) bool { return domains[domain] }) {
+			services = append(services, service)
+		}
+	}
+
+	result := m.WithServices(services)
+	result.UseCases = useCases
+	result.Actors = slices.DeleteFunc(slices.Clone(m.Actors), func(actor Actor) bool { return !actors[actor.Name] })
+	return result
+}
+
+// scenarioIndex returns the index of the scenario with the given ID, trigger description or index, or -1
+func scenarioIndex(useCase UseCase, name string) int {
+	for i, scenario := range useCase.Scenarios {
+		if scenario.ID == name || scenario.Trigger.Description == name {
+			return i
+		}
+	}
+	if index, err := strconv.Atoi(name); err == nil && index >= 0 && index < len(useCase.Scenarios) {
+		return index
+	}
+	return -1
+}
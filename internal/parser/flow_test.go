@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func flowModel() *DSLModel {
+	return &DSLModel{
+		Actors: []Actor{{Name: "Customer", Type: ActorTypeUser}, {Name: "Clerk", Type: ActorTypeUser}},
+		Services: []Service{
+			{Name: "OrderService", Domains: []string{"Orders"}, DataStores: []string{"shop_db"}},
+			{Name: "BillingService", Domains: []string{"Billing"}},
+			{Name: "StockService", Domains: []string{"Stock"}, DataStores: []string{"shop_db"}},
+		},
+		DataStores: []DataStore{{Name: "shop_db", Owner: "OrderService"}},
+		UseCases: []UseCase{
+			{
+				Name: "Checkout",
+				Scenarios: []Scenario{
+					{
+						ID:      "scenario_1",
+						Trigger: Trigger{Type: TriggerTypeExternal, Actor: "Customer", Description: "Customer places order"},
+						Actions: []Action{{Type: ActionTypeSync, Domain: "Orders", TargetDomain: "Billing"}},
+					},
+					{
+						ID:      "scenario_2",
+						Trigger: Trigger{Type: TriggerTypeDomainListen, Domain: "Stock", Event: "Order Placed", Description: "Stock listens Order Placed"},
+						Actions: []Action{{Type: ActionTypeInternal, Domain: "Stock", Verb: "reserves", Phrase: "items"}},
+					},
+				},
+			},
+			{
+				Name: "Restock",
+				Scenarios: []Scenario{{
+					ID:      "scenario_3",
+					Trigger: Trigger{Type: TriggerTypeExternal, Actor: "Clerk", Description: "Clerk restocks shelf"},
+					Actions: []Action{{Type: ActionTypeInternal, Domain: "Stock", Verb: "adds", Phrase: "items"}},
+				}},
+			},
+		},
+	}
+}
+
+func TestParseUseCaseSelection(t *testing.T) {
+	cases := map[string]UseCaseSelection{
+		"Checkout":               {UseCase: "Checkout"},
+		"Checkout#1":             {UseCase: "Checkout", Scenarios: []string{"1"}},
+		" Checkout # scenario_2": {UseCase: "Checkout", Scenarios: []string{"scenario_2"}},
+		"Checkout#":              {UseCase: "Checkout"},
+	}
+
+	for input, expected := range cases {
+		if got := ParseUseCaseSelection(input); !reflect.DeepEqual(got, expected) {
+			t.Errorf("ParseUseCaseSelection(%q) = %+v, expected %+v", input, got, expected)
+		}
+	}
+}
+
+func TestWithUseCases(t *testing.T) {
+	model, err := flowModel().WithUseCases(UseCaseSelection{UseCase: "Checkout"})
+	if err != nil {
+		t.Fatalf("WithUseCases failed: %v", err)
+	}
+
+	if len(model.UseCases) != 1 || len(model.UseCases[0].Scenarios) != 2 {
+		t.Fatalf("Expected Checkout with both scenarios, got %+v", model.UseCases)
+	}
+	names := make([]string, 0)
+	for _, service := range model.Services {
+		names = append(names, service.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"OrderService", "BillingService", "StockService"}) {
+		t.Errorf("Unexpected services: %v", names)
+	}
+	if len(model.Actors) != 1 || model.Actors[0].Name != "Customer" {
+		t.Errorf("Expected only Customer, got %+v", model.Actors)
+	}
+}
+
+func TestWithUseCases_Scenarios(t *testing.T) {
+	for _, scenario := range []string{"scenario_1", "Customer places order", "0"} {
+		model, err := flowModel().WithUseCases(
+			UseCaseSelection{UseCase: "Checkout", Scenarios: []string{scenario}},
+			UseCaseSelection{UseCase: "Restock"},
+		)
+		if err != nil {
+			t.Fatalf("WithUseCases(%q) failed: %v", scenario, err)
+		}
+
+		if len(model.UseCases) != 2 || len(model.UseCases[0].Scenarios) != 1 || model.UseCases[0].Scenarios[0].ID != "scenario_1" {
+			t.Errorf("Expected the first Checkout scenario and Restock for %q, got %+v", scenario, model.UseCases)
+		}
+	}
+
+	model, _ := flowModel().WithUseCases(UseCaseSelection{UseCase: "Checkout", Scenarios: []string{"0"}})
+	if len(model.Services) != 2 {
+		t.Errorf("Expected StockService to be dropped, got %+v", model.Services)
+	}
+	if owner := model.DataStores[0].Owner; owner != "OrderService" {
+		t.Errorf("Expected shop_db to stay with OrderService, got %q", owner)
+	}
+}
+
+func TestWithUseCases_Errors(t *testing.T) {
+	selections := []UseCaseSelection{
+		{UseCase: "Refund"},
+		{UseCase: "Checkout", Scenarios: []string{"scenario_3"}},
+		{UseCase: "Checkout", Scenarios: []string{"2"}},
+	}
+	for _, selection := range selections {
+		if _, err := flowModel().WithUseCases(selection); err == nil {
+			t.Errorf("Expected %+v to fail", selection)
+		}
+	}
+}
+
+func TestWithUseCases_None(t *testing.T) {
+	model := flowModel()
+	if got, err := model.WithUseCases(); err != nil || got != model {
+		t.Errorf("Expected no selection to return the model unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"slices"
+)
+
+// Metadata keys with a meaning of their own; any other annotation is kept as written
+const (
+	MetadataOwner       = "owner"
+	MetadataTag         = "tag"
+	MetadataDescription = "description"
+)
+
+// Metadata holds the annotations of an element by name, e.g. @tag(pci, gdpr) gives {"tag": ["pci", "gdpr"]}.
+// An annotation without arguments holds no values.
+type Metadata map[string][]string
+
+// Value returns the first value of an annotation, or "" when it is missing
+func (m Metadata) Value(key string) string {
+	if len(m[key]) == 0 {
+		return ""
+	}
+	return m[key][0]
+}
+
+// Owner returns the @owner of the element
+func (m Metadata) Owner() string {
+	return m.Value(MetadataOwner)
+}
+
+// Description returns the @description of the element
+func (m Metadata) Description() string {
+	return m.Value(MetadataDescription)
+}
+
+// Tags returns the @tag values of the element
+func (m Metadata) Tags() []string {
+	return m[MetadataTag]
+}
+
+// HasTag reports whether any of the tags is set on the element
+func (m Metadata) HasTag(tags ...string) bool {
+	for _, tag := range tags {
+		if slices.Contains(m[MetadataTag], tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Inherit returns the metadata with the annotations of a parent added: the element's own values win,
+// and tags add up
+func (m Metadata) Inherit(parent Metadata) Metadata {
+	if len(parent) == 0 {
+		return m
+	}
+
+	result := make(Metadata, len(m)+len(parent))
+	for key, values := range parent {
+		result[key] = values
+	}
+	for key, values := range m {
+		result[key] = values
+	}
+	tags := slices.Clone(parent.Tags())
+	for _, tag := range m.Tags() {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		result[MetadataTag] = tags
+	}
+	return result
+}
+
+// DomainMetadata returns the metadata of a top-level domain, or of a subdomain together with that of
+// its top-level domain
+func (m *DSLModel) DomainMetadata(name string) Metadata {
+	for _, domain := range m.Domains {
+		if domain.Name == name {
+			return domain.Metadata
+		}
+		if slices.Contains(domain.SubDomains, name) {
+			return domain.SubDomainMetadata[name].Inherit(domain.Metadata)
+		}
+	}
+	return nil
+}
+
+// EventMetadata returns the annotations of an event, gathered from every action notifying it; the first
+// action setting a value wins
+func (m *DSLModel) EventMetadata(event string) Metadata {
+	var result Metadata
+	for _, useCase := range m.UseCases {
+		for _, scenario := range useCase.Scenarios {
+			for _, action := range scenario.Actions {
+				if action.Type == ActionTypeAsync && action.Event == event && len(action.Metadata) > 0 {
+					result = result.Inherit(action.Metadata)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// WithTags returns the model narrowed to the elements carrying any of the tags. Without tags the
+// model is returned unchanged.
+func (m *DSLModel) WithTags(tags ...string) *DSLModel {
+	if len(tags) == 0 {
+		return m
+	}
+	return m.Narrow(func(metadata Metadata) bool { return metadata.HasTag(tags...) })
+}
+
+// Narrow returns the model narrowed to the elements whose metadata matches: the services matching
+// or owning a matching domain, and the scenarios involving one of their domains or a matching event.
+// Matching use cases are kept whole.
+func (m *DSLModel) Narrow(match func(Metadata) bool) *DSLModel {
+	domains := make(map[string]bool)
+	services := make([]Service, 0)
+	for _, service := range m.Services {
+		selected := match(service.Metadata)
+		for _, domain := range service.Domains {
+			selected = selected || match(m.DomainMetadata(domain))
+		}
+		if !selected {
+			continue
+		}
+		services = append(services, service)
+		for _, domain := range service.Domains {
+			domains[domain] = true
+		}
+	}
+
+	useCases := make([]UseCase, 0)
+	for _, useCase := range m.UseCases {
+		if match(useCase.Metadata) {
+			useCases = append(useCases, useCase)
+			continue
+		}
+
+		scenarios := make([]Scenario, 0)
+		for _, scenario := range useCase.Scenarios {
+			if m.scenarioInvolves(scenario, domains, match) {
+				scenarios = append(scenarios, scenario)
+			}
+		}
+		if len(scenarios) > 0 {
+			useCase.Scenarios = scenarios
+			useCases = append(useCases, useCase)
+		}
+	}
+
+	narrowed := m.WithServices(services)
+	narrowed.UseCases = useCases
+	return narrowed
+}
+
+// scenarioInvolves reports whether a scenario touches one of the domains or a matching event
+func (m *DSLModel) scenarioInvolves(scenario Scenario, domains map[string]bool, match func(Metadata) bool) bool {
+	if domains[scenario.Trigger.Domain] || (scenario.Trigger.Event != "" && match(m.EventMetadata(scenario.Trigger.Event))) {
+		return true
+	}
+	for _, action := range scenario.Actions {
+		if domains[action.Domain] || domains[action.TargetDomain] || (action.Type == ActionTypeAsync && match(action.Metadata)) {
+			return true
+		}
+	}
+	return false
+}
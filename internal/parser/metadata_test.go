@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func taggedModel() *DSLModel {
+	return &DSLModel{
+		Domains: []Domain{{
+			Name:              "Finance",
+			SubDomains:        []string{"Payments", "Ledger"},
+			Metadata:          Metadata{"owner": {"team-finance"}},
+			SubDomainMetadata: map[string]Metadata{"Payments": {"tag": {"pci"}}},
+		}},
+		Services: []Service{
+			{Name: "PaymentService", Domains: []string{"Payments"}},
+			{Name: "LedgerService", Domains: []string{"Ledger"}, DataStores: []string{"ledger_db"}},
+			{Name: "CatalogService", Domains: []string{"Catalog"}, Metadata: Metadata{"tag": {"public"}}},
+		},
+		DataStores: []DataStore{{Name: "ledger_db", Owner: "LedgerService"}},
+		UseCases: []UseCase{
+			{
+				Name: "Checkout",
+				Scenarios: []Scenario{
+					{Actions: []Action{{Type: ActionTypeSync, Domain: "Catalog", TargetDomain: "Payments"}}},
+					{Actions: []Action{{Type: ActionTypeInternal, Domain: "Catalog", Verb: "lists", Phrase: "items"}}},
+				},
+			},
+			{
+				Name:      "Close Books",
+				Metadata:  Metadata{"tag": {"pci"}},
+				Scenarios: []Scenario{{Actions: []Action{{Type: ActionTypeInternal, Domain: "Ledger", Verb: "closes", Phrase: "month"}}}},
+			},
+			{
+				Name: "Audit",
+				Scenarios: []Scenario{
+					{Actions: []Action{{Type: ActionTypeAsync, Domain: "Ledger", Event: "Entry Posted", Metadata: Metadata{"tag": {"pci"}}}}},
+					{Trigger: Trigger{Type: TriggerTypeDomainListen, Domain: "Catalog", Event: "Entry Posted"}},
+				},
+			},
+		},
+	}
+}
+
+func TestMetadata_Inherit(t *testing.T) {
+	child := Metadata{"owner": {"team-a"}, "tag": {"pci"}}
+	parent := Metadata{"owner": {"team-b"}, "tag": {"gdpr", "pci"}, "description": {"Parent"}}
+
+	expected := Metadata{"owner": {"team-a"}, "tag": {"gdpr", "pci"}, "description": {"Parent"}}
+	if got := child.Inherit(parent); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := Metadata(nil).Inherit(nil); got != nil {
+		t.Errorf("Expected no metadata, got %v", got)
+	}
+	if got := Metadata(nil); got.Owner() != "" || got.HasTag("pci") {
+		t.Errorf("Expected empty metadata to have no owner or tags")
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	model := taggedModel()
+
+	narrowed := model.WithTags("pci")
+	services := make([]string, 0)
+	for _, service := range narrowed.Services {
+		services = append(services, service.Name)
+	}
+	if !reflect.DeepEqual(services, []string{"PaymentService"}) {
+		t.Errorf("Expected only the service owning the pci-tagged Payments, got %v", services)
+	}
+
+	useCases := make(map[string]int)
+	for _, useCase := range narrowed.UseCases {
+		useCases[useCase.Name] = len(useCase.Scenarios)
+	}
+	// Checkout keeps the scenario calling Payments, Close Books is tagged, and Audit keeps both
+	// scenarios of the tagged event
+	expected := map[string]int{"Checkout": 1, "Close Books": 1, "Audit": 2}
+	if !reflect.DeepEqual(useCases, expected) {
+		t.Errorf("Expected use cases %v, got %v", expected, useCases)
+	}
+	if narrowed.DataStores[0].Owner != "" || model.DataStores[0].Owner != "LedgerService" {
+		t.Errorf("Expected the narrowed model to relink data stores without touching the original")
+	}
+
+	if got := model.WithTags(); got != model {
+		t.Errorf("Expected the model unchanged without tags")
+	}
+	if got := model.WithTags("public").Services; len(got) != 1 || got[0].Name != "CatalogService" {
+		t.Errorf("Expected only CatalogService for the public tag, got %+v", got)
+	}
+}
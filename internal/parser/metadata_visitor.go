@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Metadata Visitors
+// =============================================================================
+
+// extractMetadata collects the annotations before an element: @owner("team") @tag(pci, gdpr)
+func (b *DSLModelBuilder) extractMetadata(ctx parser.IAnnotationsContext) Metadata {
+	if ctx == nil {
+		return nil
+	}
+	return b.extractAnnotations(ctx.(*parser.AnnotationsContext).AllAnnotation())
+}
+
+// extractAnnotations collects annotations into metadata; repeated annotations add up
+func (b *DSLModelBuilder) extractAnnotations(annotations []parser.IAnnotationContext) Metadata {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	metadata := make(Metadata)
+	for _, annotation := range annotations {
+		annotationCtx := annotation.(*parser.AnnotationContext)
+		key := annotationCtx.Identifier().GetText()
+
+		values := metadata[key]
+		for _, value := range annotationCtx.AllAnnotation_value() {
+			values = append(values, strings.Trim(value.GetText(), "\""))
+		}
+		metadata[key] = values
+	}
+	return metadata
+}
+
+func (b *DSLModelBuilder) VisitAnnotations(ctx *parser.AnnotationsContext) interface{} { return nil }
+func (b *DSLModelBuilder) VisitAnnotation(ctx *parser.AnnotationContext) interface{}   { return nil }
+func (b *DSLModelBuilder) VisitAnnotation_value(ctx *parser.Annotation_valueContext) interface{} {
+	return nil
+}
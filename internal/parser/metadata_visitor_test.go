@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParser_Metadata(t *testing.T) {
+	dsl := `@owner("team-payments") @tag(pci)
+	@description("Moves money between accounts")
+	service PaymentService {
+		domains: Payments
+	}
+
+	services {
+		@tag(internal)
+		LedgerService {
+			domains: Ledger
+		}
+	}
+
+	domains {
+		@owner(team-finance)
+		Finance {
+			@tag(pci, gdpr) Payments
+			Ledger
+		}
+	}
+
+	actor user Customer
+
+	@tag(pci)
+	external_system Stripe { protocol: https }
+
+	@tag(pci)
+	use_case "Pay Invoice" {
+		when Customer pays invoice
+			Payments notifies "Invoice Paid" @tag(audit) @owner("team-payments")
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	payment := model.Services[0].Metadata
+	expected := Metadata{"owner": {"team-payments"}, "tag": {"pci"}, "description": {"Moves money between accounts"}}
+	if !reflect.DeepEqual(payment, expected) {
+		t.Errorf("Unexpected service metadata:\nExpected %v\nGot      %v", expected, payment)
+	}
+	if got := model.Services[1].Metadata.Tags(); !reflect.DeepEqual(got, []string{"internal"}) {
+		t.Errorf("Expected LedgerService to be tagged internal, got %v", got)
+	}
+
+	if got := model.DomainMetadata("Payments"); got.Owner() != "team-finance" || !reflect.DeepEqual(got.Tags(), []string{"pci", "gdpr"}) {
+		t.Errorf("Expected Payments to carry its own tags and the owner of Finance, got %v", got)
+	}
+	if got := model.DomainMetadata("Ledger"); got.Owner() != "team-finance" || len(got.Tags()) != 0 {
+		t.Errorf("Expected Ledger to inherit only the owner of Finance, got %v", got)
+	}
+
+	if system := model.ExternalSystem("Stripe"); system == nil || !system.Metadata.HasTag("pci") || system.Line != 25 {
+		t.Errorf("Expected a pci-tagged Stripe on line 25, got %+v", system)
+	}
+	if !model.UseCases[0].Metadata.HasTag("pci") {
+		t.Errorf("Expected the use case to be tagged pci, got %v", model.UseCases[0].Metadata)
+	}
+	if got := model.EventMetadata("Invoice Paid"); got.Owner() != "team-payments" || !got.HasTag("audit") {
+		t.Errorf("Unexpected event metadata: %v", got)
+	}
+}
@@ -27,6 +27,9 @@ func NewParser() *Parser {
 }
 
 func (p *Parser) ParseString(dslContent string) (*DSLModel, error) {
+	// Errors from a previous parse must not leak into this one
+	p.errorListener.Errors = nil
+
 	inputStream := antlr.NewInputStream(dslContent)
 	lexer := parser.NewCraftLexer(inputStream)
 	lexer.RemoveErrorListeners()
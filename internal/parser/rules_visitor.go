@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Rules Visitors
+// =============================================================================
+
+// Visit rules block
+func (b *DSLModelBuilder) VisitRules_def(ctx *parser.Rules_defContext) interface{} {
+	if ruleList := ctx.Rule_list(); ruleList != nil {
+		b.VisitRule_list(ruleList.(*parser.Rule_listContext))
+	}
+	return nil
+}
+
+// Visit rule list
+func (b *DSLModelBuilder) VisitRule_list(ctx *parser.Rule_listContext) interface{} {
+	for _, fitnessRule := range ctx.AllFitness_rule() {
+		b.VisitFitness_rule(fitnessRule.(*parser.Fitness_ruleContext))
+	}
+	return nil
+}
+
+// Visit a single fitness rule
+func (b *DSLModelBuilder) VisitFitness_rule(ctx *parser.Fitness_ruleContext) interface{} {
+	rule := Rule{
+		Line: ctx.GetStart().GetLine(),
+	}
+
+	scopes := ctx.AllRule_scope()
+
+	switch ctx.GetStart().GetText() {
+	case "no_sync":
+		// 'no_sync' 'from' rule_scope 'to' rule_scope
+		rule.Type = RuleTypeNoSync
+		if len(scopes) == 2 {
+			rule.From = scopes[0].GetText()
+			rule.To = scopes[1].GetText()
+		}
+	case "calls":
+		// 'calls' 'to' rule_scope 'only' 'through' exposure_name
+		rule.Type = RuleTypeCallsThrough
+		if len(scopes) == 1 {
+			rule.To = scopes[0].GetText()
+		}
+		if exposureName := ctx.Exposure_name(); exposureName != nil {
+			rule.Through = exposureName.GetText()
+		}
+	case "max_sync_hops":
+		// 'max_sync_hops' rule_limit
+		rule.Type = RuleTypeMaxSyncHops
+		rule.Limit = -1
+		if ruleLimit := ctx.Rule_limit(); ruleLimit != nil {
+			if limit, err := strconv.Atoi(ruleLimit.GetText()); err == nil && limit >= 0 {
+				rule.Limit = limit
+			}
+		}
+	}
+
+	rule.Text = b.generateRuleText(ctx)
+
+	b.model.Rules = append(b.model.Rules, rule)
+	return nil
+}
+
+// Generate the rule text as written, with single spaces between words
+func (b *DSLModelBuilder) generateRuleText(ctx *parser.Fitness_ruleContext) string {
+	words := make([]string, 0, ctx.GetChildCount())
+	for i := 0; i < ctx.GetChildCount(); i++ {
+		if child, ok := ctx.GetChild(i).(antlr.ParseTree); ok {
+			words = append(words, child.GetText())
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Rules visitor stubs
+func (b *DSLModelBuilder) VisitRule_scope(ctx *parser.Rule_scopeContext) interface{} { return nil }
+func (b *DSLModelBuilder) VisitRule_limit(ctx *parser.Rule_limitContext) interface{} { return nil }
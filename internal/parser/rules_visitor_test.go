@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParser_RulesBlock(t *testing.T) {
+	dsl := `rules {
+		no_sync from Notification to any
+		calls to Payments only through PublicAPI
+		max_sync_hops 3
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %d", len(model.Rules))
+	}
+
+	noSync := model.Rules[0]
+	if noSync.Type != RuleTypeNoSync || noSync.From != "Notification" || noSync.To != RuleScopeAny {
+		t.Errorf("Unexpected no_sync rule %+v", noSync)
+	}
+	if noSync.Line != 2 {
+		t.Errorf("Expected rule on line 2, got %d", noSync.Line)
+	}
+	if noSync.Text != "no_sync from Notification to any" {
+		t.Errorf("Unexpected rule text '%s'", noSync.Text)
+	}
+
+	callsThrough := model.Rules[1]
+	if callsThrough.Type != RuleTypeCallsThrough || callsThrough.To != "Payments" || callsThrough.Through != "PublicAPI" {
+		t.Errorf("Unexpected calls_through rule %+v", callsThrough)
+	}
+
+	maxHops := model.Rules[2]
+	if maxHops.Type != RuleTypeMaxSyncHops || maxHops.Limit != 3 {
+		t.Errorf("Unexpected max_sync_hops rule %+v", maxHops)
+	}
+}
+
+func TestParser_RulesInvalidLimit(t *testing.T) {
+	dsl := `rules {
+		max_sync_hops many
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Rules) != 1 || model.Rules[0].Limit != -1 {
+		t.Errorf("Expected invalid limit to be recorded as -1, got %+v", model.Rules)
+	}
+}
+
+func TestParser_RuleKeywordsInPhrases(t *testing.T) {
+	dsl := `use_case "Keywords" {
+		when Customer calls support
+			Support checks any open tickets
+			Support asks Billing to verify only active rules
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	scenario := model.UseCases[0].Scenarios[0]
+	if scenario.Trigger.Verb != "calls" {
+		t.Errorf("Expected verb 'calls', got '%s'", scenario.Trigger.Verb)
+	}
+	if scenario.Actions[1].Phrase != "verify only active rules" {
+		t.Errorf("Unexpected phrase '%s'", scenario.Actions[1].Phrase)
+	}
+	if scenario.Actions[0].Line != 3 {
+		t.Errorf("Expected action on line 3, got %d", scenario.Actions[0].Line)
+	}
+}
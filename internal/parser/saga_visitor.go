@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/tcarcao/craft/pkg/parser"
+)
+
+// =============================================================================
+// Saga Visitors
+// =============================================================================
+
+// Visit saga block
+func (b *DSLModelBuilder) VisitSaga(ctx *parser.SagaContext) interface{} {
+	saga := Saga{
+		Steps: make([]SagaStep, 0),
+		Line:  ctx.GetStart().GetLine(),
+	}
+
+	// The grammar rule: saga: 'saga' string '{' NEWLINE* saga_step* '}' NEWLINE*;
+	for i := 0; i < ctx.GetChildCount(); i++ {
+		switch c := ctx.GetChild(i).(type) {
+		case *parser.StringContext:
+			saga.Name = strings.Trim(c.GetText(), "\"")
+		case *parser.Saga_stepContext:
+			saga.Steps = append(saga.Steps, b.extractSagaStep(c))
+		}
+	}
+
+	b.model.Sagas = append(b.model.Sagas, saga)
+	return nil
+}
+
+// Extract a saga step and its compensation
+func (b *DSLModelBuilder) extractSagaStep(ctx *parser.Saga_stepContext) SagaStep {
+	step := SagaStep{}
+
+	if sagaAction := ctx.Saga_action(); sagaAction != nil {
+		step.Action = b.extractSagaAction(sagaAction.(*parser.Saga_actionContext))
+	}
+
+	if compensation := ctx.Compensation(); compensation != nil {
+		if sagaAction := compensation.(*parser.CompensationContext).Saga_action(); sagaAction != nil {
+			action := b.extractSagaAction(sagaAction.(*parser.Saga_actionContext))
+			step.Compensation = &action
+		}
+	}
+
+	return step
+}
+
+// Extract a saga action: a sync action, or an internal action with optional modifiers
+func (b *DSLModelBuilder) extractSagaAction(ctx *parser.Saga_actionContext) Action {
+	action := Action{
+		ID:   b.generateID("action"),
+		Line: ctx.GetStart().GetLine(),
+	}
+
+	for i := 0; i < ctx.GetChildCount(); i++ {
+		switch c := ctx.GetChild(i).(type) {
+		case *parser.Sync_actionContext:
+			b.processSyncAction(c, &action)
+		case *parser.Internal_actionContext:
+			b.processInternalAction(c, &action)
+		case *parser.Component_modifiersContext:
+			action.Modifiers = b.extractComponentModifiers(c)
+		}
+	}
+
+	action.Description = b.generateActionDescription(action)
+	return action
+}
+
+// Saga visitor stubs
+func (b *DSLModelBuilder) VisitSaga_step(ctx *parser.Saga_stepContext) interface{}       { return nil }
+func (b *DSLModelBuilder) VisitCompensation(ctx *parser.CompensationContext) interface{} { return nil }
+func (b *DSLModelBuilder) VisitSaga_action(ctx *parser.Saga_actionContext) interface{}   { return nil }
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParser_Saga(t *testing.T) {
+	dsl := `saga "Money Transfer" {
+		step Payments asks Limits to check transfer limits [readonly]
+		step Balances reserves funds
+			compensate with Balances releases funds
+		step Payments executes the transfer
+			compensate with Payments asks Ledger to reverse transfer
+		step Notification sends confirmation [retriable]
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(model.Sagas) != 1 {
+		t.Fatalf("Expected 1 saga, got %d", len(model.Sagas))
+	}
+
+	saga := model.Sagas[0]
+	if saga.Name != "Money Transfer" || saga.Line != 1 {
+		t.Errorf("Unexpected saga %q on line %d", saga.Name, saga.Line)
+	}
+	if len(saga.Steps) != 4 {
+		t.Fatalf("Expected 4 steps, got %d", len(saga.Steps))
+	}
+
+	check := saga.Steps[0]
+	if check.Action.Type != ActionTypeSync || check.Action.Domain != "Payments" || check.Action.TargetDomain != "Limits" || check.Action.Phrase != "check transfer limits" {
+		t.Errorf("Unexpected sync step %+v", check.Action)
+	}
+	if len(check.Action.Modifiers) != 1 || check.Action.Modifiers[0].Key != SagaModifierReadOnly {
+		t.Errorf("Expected the readonly modifier, got %v", check.Action.Modifiers)
+	}
+	if check.Compensation != nil {
+		t.Errorf("Expected no compensation, got %+v", check.Compensation)
+	}
+
+	reserve := saga.Steps[1]
+	if reserve.Action.Type != ActionTypeInternal || reserve.Action.Verb != "reserves" || reserve.Action.Line != 3 {
+		t.Errorf("Unexpected internal step %+v", reserve.Action)
+	}
+	if reserve.Compensation == nil || reserve.Compensation.Description != "Balances releases funds" || reserve.Compensation.Line != 4 {
+		t.Errorf("Unexpected compensation %+v", reserve.Compensation)
+	}
+
+	transfer := saga.Steps[2]
+	if transfer.Compensation == nil || transfer.Compensation.Type != ActionTypeSync || transfer.Compensation.TargetDomain != "Ledger" {
+		t.Errorf("Expected a sync compensation, got %+v", transfer.Compensation)
+	}
+
+	confirm := saga.Steps[3]
+	if len(confirm.Action.Modifiers) != 1 || confirm.Action.Modifiers[0].Key != SagaModifierRetriable {
+		t.Errorf("Expected the retriable modifier on an internal step, got %v", confirm.Action.Modifiers)
+	}
+}
+
+func TestParser_SagaKeywordsAsIdentifiers(t *testing.T) {
+	dsl := `use_case "Onboarding" {
+		when User completes step
+			saga compensate with the step
+	}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	action := model.UseCases[0].Scenarios[0].Actions[0]
+	if action.Domain != "saga" || action.Verb != "compensate" || action.Connector != "with" || action.Phrase != "the step" {
+		t.Errorf("Unexpected action %+v", action)
+	}
+}
@@ -1,18 +1,21 @@
 package parser
 
 import (
+	"maps"
 	"slices"
 )
 
 // ServiceMerger handles merging of services with the same name from multiple sources
 type ServiceMerger struct {
 	services map[string]*Service
+	order    []string // Service names in first-seen order
 }
 
 // NewServiceMerger creates a new service merger
 func NewServiceMerger() *ServiceMerger {
 	return &ServiceMerger{
 		services: make(map[string]*Service),
+		order:    make([]string, 0),
 	}
 }
 
@@ -24,17 +27,19 @@ func (sm *ServiceMerger) AddService(service Service) {
 		// Create a copy to avoid modifying original
 		merged := service
 		merged.Domains = slices.Clone(service.Domains)
+		merged.DomainModifiers = maps.Clone(service.DomainModifiers)
 		merged.DataStores = slices.Clone(service.DataStores)
 		merged.Deployment.Rules = slices.Clone(service.Deployment.Rules)
 		sm.services[service.Name] = &merged
+		sm.order = append(sm.order, service.Name)
 	}
 }
 
-// GetMergedServices returns all merged services as a slice
+// GetMergedServices returns all merged services as a slice, in the order they were first declared
 func (sm *ServiceMerger) GetMergedServices() []Service {
 	result := make([]Service, 0, len(sm.services))
-	for _, service := range sm.services {
-		result = append(result, *service)
+	for _, name := range sm.order {
+		result = append(result, *sm.services[name])
 	}
 	return result
 }
@@ -44,6 +49,16 @@ func (sm *ServiceMerger) mergeService(existing *Service, new Service) {
 	// Merge domains (deduplicate)
 	existing.Domains = mergeStringSlices(existing.Domains, new.Domains)
 	
+	// Merge domain modifiers, keeping those declared first
+	for domain, modifiers := range new.DomainModifiers {
+		if existing.DomainModifiers == nil {
+			existing.DomainModifiers = make(map[string][]ComponentModifier)
+		}
+		if _, declared := existing.DomainModifiers[domain]; !declared {
+			existing.DomainModifiers[domain] = modifiers
+		}
+	}
+
 	// Merge data stores (deduplicate)
 	existing.DataStores = mergeStringSlices(existing.DataStores, new.DataStores)
 	
@@ -57,6 +72,9 @@ func (sm *ServiceMerger) mergeService(existing *Service, new Service) {
 	
 	// Merge deployment strategies
 	sm.mergeDeploymentStrategy(&existing.Deployment, new.Deployment)
+
+	// Merge metadata, keeping the values declared first
+	existing.Metadata = existing.Metadata.Inherit(new.Metadata)
 }
 
 // mergeStringSlices merges two string slices, removing duplicates
@@ -35,7 +35,9 @@ func (b *DSLModelBuilder) VisitService_def(ctx *parser.Service_defContext) inter
 	// Extract service name and properties
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
-		if serviceName, ok := child.(*parser.Service_nameContext); ok {
+		if annotations, ok := child.(*parser.AnnotationsContext); ok {
+			service.Metadata = b.extractMetadata(annotations)
+		} else if serviceName, ok := child.(*parser.Service_nameContext); ok {
 			service.Name = b.extractServiceName(serviceName)
 		} else if serviceProps, ok := child.(*parser.Service_propertiesContext); ok {
 			b.currentService = &service
@@ -72,7 +74,9 @@ func (b *DSLModelBuilder) VisitService_block(ctx *parser.Service_blockContext) i
 	// Extract service name and properties
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
-		if serviceName, ok := child.(*parser.Service_nameContext); ok {
+		if annotations, ok := child.(*parser.AnnotationsContext); ok {
+			service.Metadata = b.extractMetadata(annotations)
+		} else if serviceName, ok := child.(*parser.Service_nameContext); ok {
 			service.Name = b.extractServiceName(serviceName)
 		} else if serviceProps, ok := child.(*parser.Service_propertiesContext); ok {
 			b.currentService = &service
@@ -251,6 +255,16 @@ func (b *DSLModelBuilder) VisitDomain_list(ctx *parser.Domain_listContext) inter
 			if domainName != "" {
 				b.currentService.Domains = append(b.currentService.Domains, domainName)
 			}
+
+			// Modifiers such as [p99:30ms] describe the domain within this service
+			for j := 0; j < domainRef.GetChildCount(); j++ {
+				if modifiers, ok := domainRef.GetChild(j).(*parser.Component_modifiersContext); ok && domainName != "" {
+					if b.currentService.DomainModifiers == nil {
+						b.currentService.DomainModifiers = make(map[string][]ComponentModifier)
+					}
+					b.currentService.DomainModifiers[domainName] = b.extractComponentModifiers(modifiers)
+				}
+			}
 		}
 	}
 	return nil
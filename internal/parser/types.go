@@ -2,12 +2,18 @@ package parser
 
 // DSLModel represents the entire parsed DSL document
 type DSLModel struct {
-	Architectures []Architecture `json:"architectures,omitempty"`
-	Exposures     []Exposure     `json:"exposures,omitempty"`
-	Services      []Service      `json:"services,omitempty"`
-	UseCases      []UseCase      `json:"useCases"`
-	Domains       []Domain       `json:"domains,omitempty"`
-	Actors        []Actor        `json:"actors,omitempty"`
+	Architectures   []Architecture    `json:"architectures,omitempty"`
+	Exposures       []Exposure        `json:"exposures,omitempty"`
+	Services        []Service         `json:"services,omitempty"`
+	UseCases        []UseCase         `json:"useCases"`
+	Domains         []Domain          `json:"domains,omitempty"`
+	Actors          []Actor           `json:"actors,omitempty"`
+	Rules           []Rule            `json:"rules,omitempty"`
+	Sagas           []Saga            `json:"sagas,omitempty"`
+	ContextMap      []ContextRelation `json:"contextMap,omitempty"`
+	Environments    []Environment     `json:"environments,omitempty"`
+	DataStores      []DataStore       `json:"dataStores,omitempty"`
+	ExternalSystems []ExternalSystem  `json:"externalSystems,omitempty"`
 }
 
 // Architecture represents an architecture definition
@@ -17,6 +23,13 @@ type Architecture struct {
 	Gateway      []Component `json:"gateway"`
 }
 
+// Environment overrides the services and arch blocks of the model for one deployment target
+type Environment struct {
+	Name          string         `json:"name"`
+	Services      []Service      `json:"services,omitempty"`      // Properties set here replace those of the service with the same name
+	Architectures []Architecture `json:"architectures,omitempty"` // Replace the arch block with the same name
+}
+
 // Component represents a component in an architecture
 type Component struct {
 	Name      string              `json:"name"`
@@ -41,19 +54,42 @@ type ComponentModifier struct {
 
 // Exposure represents an exposure definition
 type Exposure struct {
-	Name    string   `json:"name"`
-	To      []string `json:"to,omitempty"`      // Targets
-	Of      []string `json:"of,omitempty"`      // Domains
-	Through []string `json:"through,omitempty"` // Gateways
+	Name     string   `json:"name"`
+	To       []string `json:"to,omitempty"`      // Targets
+	Of       []string `json:"of,omitempty"`      // Domains
+	Through  []string `json:"through,omitempty"` // Gateways
+	Metadata Metadata `json:"metadata,omitempty"`
 }
 
 // Service represents a service definition with enhanced deployment support
 type Service struct {
-	Name       string             `json:"name"`
-	Domains    []string           `json:"domains,omitempty"`
-	DataStores []string           `json:"dataStores,omitempty"`
-	Language   string             `json:"language,omitempty"`
-	Deployment DeploymentStrategy `json:"deployment,omitempty"`
+	Name            string                         `json:"name"`
+	Domains         []string                       `json:"domains,omitempty"`
+	DomainModifiers map[string][]ComponentModifier `json:"domainModifiers,omitempty"` // Per domain, e.g. [p99:30ms]
+	DataStores      []string                       `json:"dataStores,omitempty"`
+	Language        string                         `json:"language,omitempty"`
+	Deployment      DeploymentStrategy             `json:"deployment,omitempty"`
+	Metadata        Metadata                       `json:"metadata,omitempty"`
+}
+
+// DataStore is a data store declared in a datastores block
+type DataStore struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type,omitempty"` // Technology, e.g. postgres, redis
+	EngineVersion string   `json:"engineVersion,omitempty"`
+	SharedBy      []string `json:"sharedBy,omitempty"` // Services using the store besides its owner
+	Owner         string   `json:"owner,omitempty"`    // First service listing the store in its data-stores
+	Metadata      Metadata `json:"metadata,omitempty"`
+	Line          int      `json:"line,omitempty"`
+}
+
+// ExternalSystem is a third party outside the architecture that domains call with asks
+type ExternalSystem struct {
+	Name     string   `json:"name"`
+	Protocol string   `json:"protocol,omitempty"` // e.g. https, grpc, smtp
+	Owner    string   `json:"owner,omitempty"`    // Who runs the system, e.g. vendor
+	Metadata Metadata `json:"metadata,omitempty"`
+	Line     int      `json:"line,omitempty"`
 }
 
 // DeploymentStrategy represents deployment configuration
@@ -70,8 +106,10 @@ type DeploymentRule struct {
 
 // UseCase represents a single use case with its scenarios
 type UseCase struct {
-	Name      string     `json:"name"`
-	Scenarios []Scenario `json:"scenarios"`
+	Name      string              `json:"name"`
+	Modifiers []ComponentModifier `json:"modifiers,omitempty"` // e.g. [slo:200ms]
+	Scenarios []Scenario          `json:"scenarios"`
+	Metadata  Metadata            `json:"metadata,omitempty"`
 }
 
 // Scenario represents a complete scenario with trigger and actions
@@ -90,6 +128,7 @@ type Trigger struct {
 	Domain      string      `json:"domain,omitempty"` // For domain listeners
 	Event       string      `json:"event,omitempty"`  // For events
 	Description string      `json:"description"`      // Human readable
+	Line        int         `json:"line,omitempty"`   // Source line of the trigger
 }
 
 // TriggerType defines the different types of triggers
@@ -103,15 +142,20 @@ const (
 
 // Action represents an action taken in response to a trigger
 type Action struct {
-	ID           string     `json:"id"`
-	Type         ActionType `json:"type"`
-	Domain       string     `json:"domain"`
-	Verb         string     `json:"verb,omitempty"`         // For internal actions
-	TargetDomain string     `json:"targetDomain,omitempty"` // For sync actions
-	Event        string     `json:"event,omitempty"`        // For async actions
-	Connector    string     `json:"connector,omitempty"`    // "to", "as", "the", etc.
-	Phrase       string     `json:"phrase,omitempty"`       // The action phrase
-	Description  string     `json:"description"`            // Full human readable action
+	ID           string              `json:"id"`
+	Type         ActionType          `json:"type"`
+	Domain       string              `json:"domain"`
+	Verb         string              `json:"verb,omitempty"`         // For internal actions
+	TargetDomain string              `json:"targetDomain,omitempty"` // For sync actions
+	Event        string              `json:"event,omitempty"`        // For async actions
+	DataStore    string              `json:"dataStore,omitempty"`    // For data access actions
+	Access       DataAccess          `json:"access,omitempty"`       // For data access actions
+	Connector    string              `json:"connector,omitempty"`    // "to", "as", "the", etc.
+	Phrase       string              `json:"phrase,omitempty"`       // The action phrase
+	Description  string              `json:"description"`            // Full human readable action
+	Line         int                 `json:"line,omitempty"`         // Source line of the action
+	Modifiers    []ComponentModifier `json:"modifiers,omitempty"`    // For sync actions and saga steps, e.g. [p99:40ms]
+	Metadata     Metadata            `json:"metadata,omitempty"`     // For async actions, describing the event
 }
 
 // ActionType defines the different types of actions
@@ -122,6 +166,18 @@ const (
 	ActionTypeAsync    ActionType = "async_action"    // "domain notifies 'event'"
 	ActionTypeInternal ActionType = "internal_action" // "domain verb [connector] phrase"
 	ActionTypeReturn   ActionType = "return_action"   // "domain returns phrase [to domain]"
+	// "domain reads from | writes to | creates in | deletes from datastore [phrase]"
+	ActionTypeDataAccess ActionType = "data_access_action"
+)
+
+// DataAccess is the mode in which a data access action uses its data store
+type DataAccess string
+
+const (
+	DataAccessCreate DataAccess = "create"
+	DataAccessRead   DataAccess = "read"
+	DataAccessWrite  DataAccess = "write" // An update of existing data
+	DataAccessDelete DataAccess = "delete"
 )
 
 // Interaction represents domain-to-domain interactions for sequence diagrams
@@ -136,14 +192,72 @@ type Interaction struct {
 
 // Domain represents a domain definition with its subdomains
 type Domain struct {
-	Name       string   `json:"name"`
-	SubDomains []string `json:"subDomains"`
+	Name              string              `json:"name"`
+	SubDomains        []string            `json:"subDomains"`
+	Metadata          Metadata            `json:"metadata,omitempty"`
+	SubDomainMetadata map[string]Metadata `json:"subDomainMetadata,omitempty"` // Per annotated subdomain
+}
+
+// ParentDomain returns the top-level domain declaring a subdomain, or "" when no domain does
+func (m *DSLModel) ParentDomain(subDomain string) string {
+	for _, domain := range m.Domains {
+		for _, name := range domain.SubDomains {
+			if name == subDomain {
+				return domain.Name
+			}
+		}
+	}
+	return ""
+}
+
+// SubDomainsOf returns the subdomains declared under a top-level domain
+func (m *DSLModel) SubDomainsOf(domain string) []string {
+	for _, d := range m.Domains {
+		if d.Name == domain {
+			return d.SubDomains
+		}
+	}
+	return nil
 }
 
-// Actor represents an actor definition with its type
+// ContextRelation is a context_map entry: From depends on To, so From is downstream and To upstream
+type ContextRelation struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Patterns []string `json:"patterns,omitempty"` // DDD patterns as written, e.g. acl, conformist
+	Line     int      `json:"line,omitempty"`
+}
+
+// ContextPattern is a DDD relationship pattern between bounded contexts
+type ContextPattern string
+
+const (
+	ContextPatternCustomerSupplier  ContextPattern = "customer-supplier"
+	ContextPatternConformist        ContextPattern = "conformist"
+	ContextPatternACL               ContextPattern = "acl"
+	ContextPatternOpenHostService   ContextPattern = "open-host-service"
+	ContextPatternPublishedLanguage ContextPattern = "published-language"
+	ContextPatternSharedKernel      ContextPattern = "shared-kernel"
+	ContextPatternPartnership       ContextPattern = "partnership"
+)
+
+// Actor represents an actor definition with its type and optional details
 type Actor struct {
-	Name string    `json:"name"`
-	Type ActorType `json:"type"`
+	Name        string             `json:"name"`
+	Type        ActorType          `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Persona     []PersonaAttribute `json:"persona,omitempty"`
+	Uses        []string           `json:"uses,omitempty"` // Exposures the actor goes through
+	Roles       []string           `json:"roles,omitempty"`
+	Permissions []string           `json:"permissions,omitempty"`
+	Metadata    Metadata           `json:"metadata,omitempty"`
+	Line        int                `json:"line,omitempty"`
+}
+
+// PersonaAttribute is a free-form persona detail of an actor, e.g. goal: "Pay quickly"
+type PersonaAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // ActorType defines the different types of actors
@@ -154,3 +268,46 @@ const (
 	ActorTypeSystem  ActorType = "system"
 	ActorTypeService ActorType = "service"
 )
+
+// Rule represents an architecture fitness rule from a rules block
+type Rule struct {
+	Type    RuleType `json:"type"`
+	From    string   `json:"from,omitempty"`    // no_sync: calling service or domain, or "any"
+	To      string   `json:"to,omitempty"`      // no_sync: called service or domain, or "any"; calls_through: protected service or domain
+	Through string   `json:"through,omitempty"` // calls_through: the only exposure allowed
+	Limit   int      `json:"limit,omitempty"`   // max_sync_hops: -1 when the limit is not a number
+	Text    string   `json:"text"`              // Rule as written, for reports
+	Line    int      `json:"line,omitempty"`
+}
+
+// RuleType defines the different kinds of fitness rules
+type RuleType string
+
+const (
+	RuleTypeNoSync       RuleType = "no_sync"       // "no_sync from X to Y"
+	RuleTypeCallsThrough RuleType = "calls_through" // "calls to X only through Exposure"
+	RuleTypeMaxSyncHops  RuleType = "max_sync_hops" // "max_sync_hops N"
+)
+
+// RuleScopeAny matches every service other than the one on the opposite side of the rule
+const RuleScopeAny = "any"
+
+// Saga represents a distributed transaction: ordered steps, each undone by its compensation
+// when a later step fails
+type Saga struct {
+	Name  string     `json:"name"`
+	Steps []SagaStep `json:"steps"`
+	Line  int        `json:"line,omitempty"`
+}
+
+// SagaStep is a forward action of a saga and the action compensating it, if any
+type SagaStep struct {
+	Action       Action  `json:"action"`
+	Compensation *Action `json:"compensation,omitempty"`
+}
+
+// Saga step modifiers for steps that never need to be undone
+const (
+	SagaModifierReadOnly  = "readonly"  // The step does not change state
+	SagaModifierRetriable = "retriable" // The step is retried until it succeeds
+)
@@ -21,7 +21,7 @@ func (b *DSLModelBuilder) VisitUse_case(ctx *parser.Use_caseContext) interface{}
 	b.currentUC = &useCase
 
 	// Try to extract use case name using the string context method
-	// The grammar rule: use_case: 'use_case' string '{' NEWLINE* scenario* '}' NEWLINE*;
+	// The grammar rule: use_case: 'use_case' string component_modifiers? '{' NEWLINE* scenario* '}' NEWLINE*;
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
 		if stringCtx, ok := child.(*parser.StringContext); ok {
@@ -38,11 +38,16 @@ func (b *DSLModelBuilder) VisitUse_case(ctx *parser.Use_caseContext) interface{}
 		}
 	}
 
-	// Process scenarios
+	// Process modifiers and scenarios
 	for i := 0; i < ctx.GetChildCount(); i++ {
 		child := ctx.GetChild(i)
-		if scenario, ok := child.(*parser.ScenarioContext); ok {
-			b.VisitScenario(scenario)
+		switch c := child.(type) {
+		case *parser.AnnotationsContext:
+			useCase.Metadata = b.extractMetadata(c)
+		case *parser.Component_modifiersContext:
+			useCase.Modifiers = b.extractComponentModifiers(c)
+		case *parser.ScenarioContext:
+			b.VisitScenario(c)
 		}
 	}
 
@@ -80,7 +85,9 @@ func (b *DSLModelBuilder) VisitScenario(ctx *parser.ScenarioContext) interface{}
 
 // Visit trigger
 func (b *DSLModelBuilder) VisitTrigger(ctx *parser.TriggerContext) interface{} {
-	trigger := Trigger{}
+	trigger := Trigger{
+		Line: ctx.GetStart().GetLine(),
+	}
 
 	// Handle the three trigger patterns properly
 	if externalTrigger := ctx.External_trigger(); externalTrigger != nil {
@@ -151,7 +158,8 @@ func (b *DSLModelBuilder) VisitAction_block(ctx *parser.Action_blockContext) int
 // Visit action
 func (b *DSLModelBuilder) VisitAction(ctx *parser.ActionContext) interface{} {
 	action := Action{
-		ID: b.generateID("action"),
+		ID:   b.generateID("action"),
+		Line: ctx.GetStart().GetLine(),
 	}
 
 	// Determine action type and extract data
@@ -166,6 +174,8 @@ func (b *DSLModelBuilder) VisitAction(ctx *parser.ActionContext) interface{} {
 			b.processInternalAction(c, &action)
 		case *parser.Return_actionContext:
 			b.processReturnAction(c, &action)
+		case *parser.Data_access_actionContext:
+			b.processDataAccessAction(c, &action)
 		}
 	}
 
@@ -194,6 +204,8 @@ func (b *DSLModelBuilder) processSyncAction(ctx *parser.Sync_actionContext, acti
 		case *parser.PhraseContext:
 			words := b.extractWordsFromPhrase(c)
 			action.Phrase = strings.Join(words, " ")
+		case *parser.Component_modifiersContext:
+			action.Modifiers = b.extractComponentModifiers(c)
 		}
 	}
 
@@ -220,6 +232,7 @@ func (b *DSLModelBuilder) processAsyncAction(ctx *parser.Async_actionContext, ac
 			action.Event = strings.Trim(eventText, "\"")
 		}
 	}
+	action.Metadata = b.extractAnnotations(ctx.AllAnnotation())
 }
 
 // Process internal action: domain verb [connector_word] phrase
@@ -265,6 +278,42 @@ func (b *DSLModelBuilder) processReturnAction(ctx *parser.Return_actionContext,
 	}
 }
 
+// Process data access action: domain reads from | writes to | creates in | deletes from datastore [phrase]
+func (b *DSLModelBuilder) processDataAccessAction(ctx *parser.Data_access_actionContext, action *Action) {
+	action.Type = ActionTypeDataAccess
+
+	for i := 0; i < ctx.GetChildCount(); i++ {
+		child := ctx.GetChild(i)
+		switch c := child.(type) {
+		case *parser.DomainContext:
+			action.Domain = c.GetText()
+		case *parser.DatastoreContext:
+			action.DataStore = c.GetText()
+		case *parser.PhraseContext:
+			words := b.extractWordsFromPhrase(c)
+			action.Phrase = strings.Join(words, " ")
+		case antlr.TerminalNode:
+			// The keywords: reads from | writes to | creates in | deletes from
+			if action.Verb == "" {
+				action.Verb = c.GetText()
+			} else {
+				action.Connector = c.GetText()
+			}
+		}
+	}
+
+	switch action.Verb {
+	case "writes":
+		action.Access = DataAccessWrite
+	case "creates":
+		action.Access = DataAccessCreate
+	case "deletes":
+		action.Access = DataAccessDelete
+	default:
+		action.Access = DataAccessRead
+	}
+}
+
 // Generate human-readable action description
 func (b *DSLModelBuilder) generateActionDescription(action Action) string {
 	switch action.Type {
@@ -287,6 +336,11 @@ func (b *DSLModelBuilder) generateActionDescription(action Action) string {
 			return fmt.Sprintf("%s returns %s to %s", action.Domain, action.Phrase, action.TargetDomain)
 		}
 		return fmt.Sprintf("%s returns %s", action.Domain, action.Phrase)
+	case ActionTypeDataAccess:
+		if action.Phrase != "" {
+			return fmt.Sprintf("%s %s %s %s %s", action.Domain, action.Verb, action.Connector, action.DataStore, action.Phrase)
+		}
+		return fmt.Sprintf("%s %s %s %s", action.Domain, action.Verb, action.Connector, action.DataStore)
 	}
 	return "unknown action"
 }
@@ -340,6 +394,10 @@ func (b *DSLModelBuilder) VisitInternal_action(ctx *parser.Internal_actionContex
 func (b *DSLModelBuilder) VisitReturn_action(ctx *parser.Return_actionContext) interface{} {
 	return nil
 }
+
+func (b *DSLModelBuilder) VisitData_access_action(ctx *parser.Data_access_actionContext) interface{} {
+	return nil
+}
 func (b *DSLModelBuilder) VisitPhrase(ctx *parser.PhraseContext) interface{} { return nil }
 func (b *DSLModelBuilder) VisitConnector_word(ctx *parser.Connector_wordContext) interface{} {
 	return nil
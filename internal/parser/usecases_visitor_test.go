@@ -1178,3 +1178,113 @@ func BenchmarkParser_ComplexCase(b *testing.B) {
 	}
 }
 
+func TestParser_LatencyModifiers(t *testing.T) {
+	dsl := `services {
+	OrderService {
+		domains: Orders, Billing [p99:30ms]
+	}
+}
+
+use_case "Checkout" [slo:200ms] {
+	when Customer places order
+		Orders asks Billing to charge card [p99:40ms, p50:12ms]
+		Orders asks Billing for receipt
+}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	service := model.Services[0]
+	if len(service.Domains) != 2 || service.Domains[1] != "Billing" {
+		t.Errorf("Expected domains Orders and Billing, got %v", service.Domains)
+	}
+	if modifiers := service.DomainModifiers["Billing"]; len(modifiers) != 1 || modifiers[0] != (ComponentModifier{Key: "p99", Value: "30ms"}) {
+		t.Errorf("Expected Billing to carry [p99:30ms], got %v", modifiers)
+	}
+	if _, ok := service.DomainModifiers["Orders"]; ok {
+		t.Errorf("Expected no modifiers on Orders")
+	}
+
+	useCase := model.UseCases[0]
+	if useCase.Name != "Checkout" {
+		t.Errorf("Expected use case 'Checkout', got '%s'", useCase.Name)
+	}
+	if len(useCase.Modifiers) != 1 || useCase.Modifiers[0] != (ComponentModifier{Key: "slo", Value: "200ms"}) {
+		t.Errorf("Expected [slo:200ms], got %v", useCase.Modifiers)
+	}
+
+	actions := useCase.Scenarios[0].Actions
+	if actions[0].Phrase != "charge card" {
+		t.Errorf("Expected the modifiers to stay out of the phrase, got '%s'", actions[0].Phrase)
+	}
+	expected := []ComponentModifier{{Key: "p99", Value: "40ms"}, {Key: "p50", Value: "12ms"}}
+	if len(actions[0].Modifiers) != 2 || actions[0].Modifiers[0] != expected[0] || actions[0].Modifiers[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, actions[0].Modifiers)
+	}
+	if len(actions[1].Modifiers) != 0 {
+		t.Errorf("Expected no modifiers on the second call, got %v", actions[1].Modifiers)
+	}
+}
+
+func TestParser_DataAccessActions(t *testing.T) {
+	dsl := `use_case "Update Profile" {
+	when User updates profile
+		Authentication reads from user_db
+		Profile writes to user_db "profile row"
+		Profile reads the cache
+		Profile creates in user_db audit entry
+		Profile deletes from session_cache
+		Profile creates user record
+}`
+
+	parser := NewParser()
+	model, err := parser.ParseString(dsl)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	actions := model.UseCases[0].Scenarios[0].Actions
+	if len(actions) != 6 {
+		t.Fatalf("Expected 6 actions, got %d", len(actions))
+	}
+
+	read := actions[0]
+	if read.Type != ActionTypeDataAccess || read.Access != DataAccessRead {
+		t.Errorf("Expected a read data access, got type '%s' access '%s'", read.Type, read.Access)
+	}
+	if read.Domain != "Authentication" || read.DataStore != "user_db" || read.Phrase != "" {
+		t.Errorf("Expected Authentication reading user_db, got %+v", read)
+	}
+	if read.Description != "Authentication reads from user_db" {
+		t.Errorf("Unexpected description '%s'", read.Description)
+	}
+
+	write := actions[1]
+	if write.Type != ActionTypeDataAccess || write.Access != DataAccessWrite {
+		t.Errorf("Expected a write data access, got type '%s' access '%s'", write.Type, write.Access)
+	}
+	if write.DataStore != "user_db" || write.Phrase != "profile row" {
+		t.Errorf("Expected a write of 'profile row' to user_db, got %+v", write)
+	}
+	if write.Description != "Profile writes to user_db profile row" {
+		t.Errorf("Unexpected description '%s'", write.Description)
+	}
+
+	// Without the connector the action stays internal
+	if actions[2].Type != ActionTypeInternal || actions[2].Verb != "reads" {
+		t.Errorf("Expected an internal action, got type '%s' verb '%s'", actions[2].Type, actions[2].Verb)
+	}
+
+	if actions[3].Access != DataAccessCreate || actions[3].DataStore != "user_db" || actions[3].Phrase != "audit entry" {
+		t.Errorf("Expected a create of 'audit entry' in user_db, got %+v", actions[3])
+	}
+	if actions[4].Access != DataAccessDelete || actions[4].DataStore != "session_cache" {
+		t.Errorf("Expected a delete from session_cache, got %+v", actions[4])
+	}
+	if actions[5].Type != ActionTypeInternal || actions[5].Verb != "creates" || actions[5].Phrase != "user record" {
+		t.Errorf("Expected an internal create, got %+v", actions[5])
+	}
+}
@@ -4,14 +4,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/tcarcao/craft/internal/analysis"
+	"github.com/tcarcao/craft/internal/crud"
+	"github.com/tcarcao/craft/internal/diff"
+	"github.com/tcarcao/craft/internal/export"
+	"github.com/tcarcao/craft/internal/filter"
+	"github.com/tcarcao/craft/internal/formatter"
+	"github.com/tcarcao/craft/internal/history"
+	"github.com/tcarcao/craft/internal/impact"
+	"github.com/tcarcao/craft/internal/importer"
+	"github.com/tcarcao/craft/internal/linter"
 	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/simulation"
+	"github.com/tcarcao/craft/internal/trace"
 	"github.com/tcarcao/craft/internal/visualizer"
 )
 
 type Processor struct {
-	parser     *parser.Parser
-	visualizer *visualizer.Visualizer
+	parser      *parser.Parser
+	visualizer  *visualizer.Visualizer
+	environment string                    // Environment input models are resolved for, empty for the top-level model
+	filter      *filter.Filter            // Filter input models are narrowed with, nil for the whole model
+	useCases    []parser.UseCaseSelection // Flows input models are restricted to, none for every use case
 }
 
 func New() (*Processor, error) {
@@ -25,21 +41,319 @@ func New() (*Processor, error) {
 	}, nil
 }
 
+// SetEnvironment resolves the models of later generators and exports for the named environment
+func (p *Processor) SetEnvironment(name string) {
+	p.environment = name
+}
+
+// SetFilter narrows the models of later generators and exports with the filter
+func (p *Processor) SetFilter(f *filter.Filter) {
+	p.filter = f
+}
+
+// SetUseCases restricts the models of later generators and exports to the selected use cases and scenarios
+func (p *Processor) SetUseCases(selections []parser.UseCaseSelection) {
+	p.useCases = selections
+}
+
 func (p *Processor) ProcessFile(inputPath, outputDir string) error {
+	arch, err := p.loadModel(inputPath)
+	if err != nil {
+		return err
+	}
+
+	if err := p.generateDiagrams(arch, outputDir); err != nil {
+		return fmt.Errorf("failed to generate diagrams: %v", err)
+	}
+
+	return nil
+}
+
+// ExportOpenAPI writes one OpenAPI skeleton per service found in the input file
+func (p *Processor) ExportOpenAPI(inputPath, outputDir string) error {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	documents := export.NewOpenAPIExporter(model).GenerateAll()
+	for serviceName, doc := range documents {
+		content, err := export.MarshalOpenAPI(doc)
+		if err != nil {
+			return fmt.Errorf("failed to encode OpenAPI document for %s: %v", serviceName, err)
+		}
+
+		filename := fmt.Sprintf("%s.openapi.json", strings.ReplaceAll(serviceName, " ", "_"))
+		if err := os.WriteFile(filepath.Join(outputDir, filename), content, 0644); err != nil {
+			return fmt.Errorf("failed to write OpenAPI document for %s: %v", serviceName, err)
+		}
+	}
+
+	return nil
+}
+
+// FormatFile parses the input file and returns its canonical Craft source, keeping its comments
+func (p *Processor) FormatFile(inputPath string) (string, error) {
 	content, err := os.ReadFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("failed to read input file: %v", err)
+		return "", fmt.Errorf("failed to read input file: %v", err)
 	}
 
-	arch, err := p.parser.ParseString(string(content))
+	model, err := p.parser.ParseString(string(content))
 	if err != nil {
-		return fmt.Errorf("failed to parse architecture: %v", err)
+		return "", fmt.Errorf("failed to parse architecture: %v", err)
 	}
 
-	if err := p.generateDiagrams(arch, outputDir); err != nil {
-		return fmt.Errorf("failed to generate diagrams: %v", err)
+	return formatter.FormatSource(model, string(content)), nil
+}
+
+// ImportSpec converts a local OpenAPI or AsyncAPI spec into starter Craft source
+func (p *Processor) ImportSpec(format importer.SpecFormat, specPath string) (string, error) {
+	source, err := importer.ImportToCraft(format, specPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to import %s spec: %v", format, err)
+	}
+	return source, nil
+}
+
+// DiffFiles compares two versions of a Craft file semantically
+func (p *Processor) DiffFiles(oldPath, newPath string) (*diff.ModelDiff, error) {
+	oldModel, err := p.loadModel(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", oldPath, err)
 	}
 
+	newModel, err := p.loadModel(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", newPath, err)
+	}
+
+	return diff.Compare(oldModel, newModel), nil
+}
+
+// History returns the architectural changelog of the Craft files under paths in a git repository
+func (p *Processor) History(repoDir, since string, paths []string) ([]history.Entry, error) {
+	changelog := history.NewChangelog(repoDir, paths, p.parser.ParseString)
+	entries, err := changelog.Since(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build history: %v", err)
+	}
+	return entries, nil
+}
+
+// AnalyzeFile reports dependency cycles, coupling, chatty service pairs and long sync call chains
+func (p *Processor) AnalyzeFile(inputPath string, options analysis.Options) (*analysis.Report, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.Analyze(model, options), nil
+}
+
+// RecommendBoundaries proposes a grouping of domains into services from their interactions
+func (p *Processor) RecommendBoundaries(inputPath string, options analysis.BoundaryOptions) (*analysis.BoundaryReport, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.RecommendBoundaries(model, options), nil
+}
+
+// ImpactOf reports the use cases, callers, consumers and routes affected by changing a domain
+func (p *Processor) ImpactOf(inputPath, domain string) (*impact.Report, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return impact.Analyze(model, domain)
+}
+
+// TraceFile follows the events published from a scenario of the input file across use cases
+func (p *Processor) TraceFile(inputPath, useCase string, scenario int) (*trace.Trace, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return trace.Follow(model, useCase, scenario)
+}
+
+// GenerateTraceDiagram writes the sequence diagram of an event trace
+func (p *Processor) GenerateTraceDiagram(t *trace.Trace, outputPath string, format visualizer.SupportedFormat) error {
+	content, _, err := p.visualizer.GenerateTraceDiagramWithFormat(t, format)
+	if err != nil {
+		return fmt.Errorf("failed to generate trace diagram: %v", err)
+	}
+	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write trace diagram: %v", err)
+	}
+	return nil
+}
+
+// SimulateFile estimates the latency of each externally triggered scenario in the input file
+func (p *Processor) SimulateFile(inputPath string, options simulation.Options) (*simulation.Report, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return simulation.Simulate(model, options), nil
+}
+
+// CRUDReport builds the data access matrices of the domains and use cases in the input file
+func (p *Processor) CRUDReport(inputPath string) (*crud.Report, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return crud.Build(model), nil
+}
+
+// LintFile checks the input file against its own fitness rules and those of the given rules files
+func (p *Processor) LintFile(inputPath string, rulesPaths []string) ([]linter.Diagnostic, error) {
+	model, err := p.loadModel(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", inputPath, err)
+	}
+
+	ruleFiles := make([]linter.Source, 0, len(rulesPaths))
+	for _, rulesPath := range rulesPaths {
+		rulesModel, err := p.parseFile(rulesPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", rulesPath, err)
+		}
+		ruleFiles = append(ruleFiles, linter.Source{File: rulesPath, Model: rulesModel})
+	}
+
+	return linter.Lint(linter.Source{File: inputPath, Model: model}, ruleFiles...), nil
+}
+
+// GenerateDiffDiagrams writes C4 and domain diagrams of both versions with added and removed elements coloured
+func (p *Processor) GenerateDiffDiagrams(modelDiff *diff.ModelDiff, outputDir string, format visualizer.SupportedFormat) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	merged := modelDiff.MergedModel()
+	highlights := modelDiff.Highlights()
+
+	c4Content, _, err := p.visualizer.GenerateC4WithHighlightsAndFormat(merged, highlights, visualizer.C4ModeBoundaries, true, format)
+	if err != nil {
+		return fmt.Errorf("failed to generate C4 diff diagram: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "diff_c4."+string(format)), c4Content, 0644); err != nil {
+		return fmt.Errorf("failed to write C4 diff diagram: %v", err)
+	}
+
+	domainContent, _, err := p.visualizer.GenerateDomainDiagramWithHighlightsAndFormat(merged, highlights, format)
+	if err != nil {
+		return fmt.Errorf("failed to generate domain diff diagram: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "diff_domain."+string(format)), domainContent, 0644); err != nil {
+		return fmt.Errorf("failed to write domain diff diagram: %v", err)
+	}
+
+	return nil
+}
+
+// GenerateImpactDiagrams writes a C4 diagram focused on the affected services and a domain diagram,
+// both with the changed domain and its blast radius coloured
+func (p *Processor) GenerateImpactDiagrams(report *impact.Report, outputDir string, format visualizer.SupportedFormat) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	highlights := report.Highlights()
+
+	c4Content, _, err := p.visualizer.GenerateC4WithFocusHighlightsAndFormat(report.Model(), report.Services, highlights, visualizer.C4ModeBoundaries, true, format)
+	if err != nil {
+		return fmt.Errorf("failed to generate C4 impact diagram: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "impact_c4."+string(format)), c4Content, 0644); err != nil {
+		return fmt.Errorf("failed to write C4 impact diagram: %v", err)
+	}
+
+	domainContent, _, err := p.visualizer.GenerateDomainDiagramWithHighlightsAndFormat(report.Model(), highlights, format)
+	if err != nil {
+		return fmt.Errorf("failed to generate domain impact diagram: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "impact_domain."+string(format)), domainContent, 0644); err != nil {
+		return fmt.Errorf("failed to write domain impact diagram: %v", err)
+	}
+
+	return nil
+}
+
+func (p *Processor) parseFile(inputPath string) (*parser.DSLModel, error) {
+	content, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file: %v", err)
+	}
+
+	model, err := p.parser.ParseString(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse architecture: %v", err)
+	}
+
+	return model, nil
+}
+
+// loadModel parses a file, resolves it for the selected environment and narrows it with the filter
+func (p *Processor) loadModel(inputPath string) (*parser.DSLModel, error) {
+	model, err := p.parseFile(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if p.filter != nil && p.filter.Env != "" && p.environment != "" && p.filter.Env != p.environment {
+		return nil, fmt.Errorf("filter environment %s conflicts with environment %s", p.filter.Env, p.environment)
+	}
+	resolved, err := model.ForEnvironment(p.environment)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err := p.filter.Apply(resolved)
+	if err != nil {
+		return nil, err
+	}
+	return filtered.WithUseCases(p.useCases...)
+}
+
+// ProcessFilePerUseCase generates a C4 and a domain diagram for each use case of the model,
+// named usecase_<name>_c4.png and usecase_<name>_domain.png
+func (p *Processor) ProcessFilePerUseCase(inputPath, outputDir string) error {
+	arch, err := p.loadModel(inputPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	for _, useCase := range arch.UseCases {
+		model, err := arch.WithUseCases(parser.UseCaseSelection{UseCase: useCase.Name})
+		if err != nil {
+			return err
+		}
+		prefix := fmt.Sprintf("usecase_%s", strings.ReplaceAll(useCase.Name, " ", "_"))
+
+		c4Content, _, err := p.visualizer.GenerateC4WithFormat(model, visualizer.C4ModeBoundaries, true, visualizer.FormatPNG)
+		if err != nil {
+			return fmt.Errorf("failed to generate C4 diagram for use case %s: %v", useCase.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, prefix+"_c4.png"), c4Content, 0644); err != nil {
+			return fmt.Errorf("failed to write C4 diagram for use case %s: %v", useCase.Name, err)
+		}
+
+		domainContent, _, err := p.visualizer.GenerateDomainDiagramWithModeAndFormat(model, visualizer.DomainModeDetailed, visualizer.FormatPNG)
+		if err != nil {
+			return fmt.Errorf("failed to generate domain diagram for use case %s: %v", useCase.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, prefix+"_domain.png"), domainContent, 0644); err != nil {
+			return fmt.Errorf("failed to write domain diagram for use case %s: %v", useCase.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -66,5 +380,57 @@ func (p *Processor) generateDiagrams(arch *parser.DSLModel, outputDir string) er
 		return fmt.Errorf("failed to write domain diagram: %v", err)
 	}
 
+	// Generate context map when the model declares bounded contexts
+	if len(arch.Domains) > 0 {
+		contextMapContent, err := p.visualizer.GenerateDomainDiagramWithMode(arch, visualizer.DomainModeContextMap)
+		if err != nil {
+			return fmt.Errorf("failed to generate context map: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, "context_map.png"), contextMapContent, 0644); err != nil {
+			return fmt.Errorf("failed to write context map: %v", err)
+		}
+	}
+
+	// Generate exposure view when the model declares exposures
+	if len(arch.Exposures) > 0 {
+		exposureContent, err := p.visualizer.GenerateDomainDiagramWithMode(arch, visualizer.DomainModeExposure)
+		if err != nil {
+			return fmt.Errorf("failed to generate exposure diagram: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, "exposures.png"), exposureContent, 0644); err != nil {
+			return fmt.Errorf("failed to write exposure diagram: %v", err)
+		}
+	}
+
+	// Generate one deployment diagram per named arch block; an unnamed first block gets deployment.png
+	for i, architecture := range arch.Architectures {
+		if architecture.Name == "" && i > 0 {
+			continue
+		}
+		deploymentContent, _, err := p.visualizer.GenerateC4DeploymentWithFormat(arch, architecture.Name, visualizer.FormatPNG)
+		if err != nil {
+			return fmt.Errorf("failed to generate deployment diagram: %v", err)
+		}
+		filename := "deployment.png"
+		if architecture.Name != "" {
+			filename = fmt.Sprintf("deployment_%s.png", strings.ReplaceAll(architecture.Name, " ", "_"))
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, filename), deploymentContent, 0644); err != nil {
+			return fmt.Errorf("failed to write deployment diagram: %v", err)
+		}
+	}
+
+	// Generate one diagram per saga
+	for _, saga := range arch.Sagas {
+		sagaContent, _, err := p.visualizer.GenerateSagaDiagramWithFormat(saga, visualizer.FormatPNG)
+		if err != nil {
+			return fmt.Errorf("failed to generate diagram for saga %s: %v", saga.Name, err)
+		}
+		filename := fmt.Sprintf("saga_%s.png", strings.ReplaceAll(saga.Name, " ", "_"))
+		if err := os.WriteFile(filepath.Join(outputDir, filename), sagaContent, 0644); err != nil {
+			return fmt.Errorf("failed to write diagram for saga %s: %v", saga.Name, err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,226 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Violation describes a place where the model breaks a fitness rule.
+// From/To name the offending edge (domains, or actor and entry domain) when there is one;
+// invalid rules are marked Invalid and reported without an edge at the rule's own line.
+type Violation struct {
+	Rule    parser.Rule `json:"rule"`
+	Message string      `json:"message"`
+	Invalid bool        `json:"invalid,omitempty"` // The rule itself cannot be evaluated against the model
+	UseCase string      `json:"use_case,omitempty"`
+	Line    int         `json:"line,omitempty"`
+	From    string      `json:"from,omitempty"`
+	To      string      `json:"to,omitempty"`
+}
+
+// Engine evaluates the fitness rules of a model against its dependency graph
+type Engine struct {
+	model *parser.DSLModel
+	graph *graph.Graph
+}
+
+// NewEngine creates a rule engine for the model
+func NewEngine(model *parser.DSLModel) *Engine {
+	return &Engine{model: model, graph: graph.Build(model)}
+}
+
+// Evaluate checks every rule of the model and returns the violations in rule order
+func (e *Engine) Evaluate() []Violation {
+	violations := make([]Violation, 0)
+
+	for _, rule := range e.model.Rules {
+		if invalid := e.validate(rule); invalid != "" {
+			violations = append(violations, Violation{Rule: rule, Message: invalid, Invalid: true, Line: rule.Line})
+			continue
+		}
+
+		switch rule.Type {
+		case parser.RuleTypeNoSync:
+			violations = append(violations, e.checkNoSync(rule)...)
+		case parser.RuleTypeCallsThrough:
+			violations = append(violations, e.checkCallsThrough(rule)...)
+		case parser.RuleTypeMaxSyncHops:
+			violations = append(violations, e.checkMaxSyncHops(rule)...)
+		}
+	}
+
+	return violations
+}
+
+// validate returns why a rule cannot be evaluated, or "" when it is valid
+func (e *Engine) validate(rule parser.Rule) string {
+	switch rule.Type {
+	case parser.RuleTypeNoSync:
+		for _, scope := range []string{rule.From, rule.To} {
+			if !e.isKnownScope(scope) {
+				return fmt.Sprintf("rule '%s' refers to unknown service or domain %s", rule.Text, scope)
+			}
+		}
+	case parser.RuleTypeCallsThrough:
+		if rule.To == parser.RuleScopeAny || !e.isKnownScope(rule.To) {
+			return fmt.Sprintf("rule '%s' refers to unknown service or domain %s", rule.Text, rule.To)
+		}
+		if e.findExposure(rule.Through) == nil {
+			return fmt.Sprintf("rule '%s' refers to unknown exposure %s", rule.Text, rule.Through)
+		}
+	case parser.RuleTypeMaxSyncHops:
+		if rule.Limit < 0 {
+			return fmt.Sprintf("rule '%s' needs a non-negative number", rule.Text)
+		}
+	default:
+		return fmt.Sprintf("unknown rule '%s'", rule.Text)
+	}
+	return ""
+}
+
+// checkNoSync reports sync calls from the first scope to the second.
+// When either side is "any", only calls crossing a service boundary count.
+func (e *Engine) checkNoSync(rule parser.Rule) []Violation {
+	violations := make([]Violation, 0)
+	crossServiceOnly := rule.From == parser.RuleScopeAny || rule.To == parser.RuleScopeAny
+
+	for _, call := range e.graph.Edges(graph.EdgeSync) {
+		from, to := call.From.Name(), call.To.Name()
+		if !e.inScope(rule.From, from) || !e.inScope(rule.To, to) {
+			continue
+		}
+		if crossServiceOnly && e.sameService(from, to) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Rule:    rule,
+			Message: fmt.Sprintf("sync call from %s to %s violates rule '%s'", from, to, rule.Text),
+			UseCase: call.UseCase,
+			Line:    call.Line,
+			From:    from,
+			To:      to,
+		})
+	}
+
+	return violations
+}
+
+// checkCallsThrough reports entries into the scope that bypass the exposure:
+// external triggers not covered by it and sync calls from other services
+func (e *Engine) checkCallsThrough(rule parser.Rule) []Violation {
+	violations := make([]Violation, 0)
+	exposure := e.findExposure(rule.Through)
+
+	for _, entry := range e.graph.Edges(graph.EdgeTrigger) {
+		actor, entryDomain := entry.From.Name(), entry.To.Name()
+		if !e.inScope(rule.To, entryDomain) {
+			continue
+		}
+
+		exposed := slices.Contains(exposure.Of, entryDomain) &&
+			(len(exposure.To) == 0 || slices.Contains(exposure.To, actor))
+		if exposed {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Rule:    rule,
+			Message: fmt.Sprintf("%s reaches %s without exposure %s", actor, entryDomain, rule.Through),
+			UseCase: entry.UseCase,
+			Line:    entry.Line,
+			From:    actor,
+			To:      entryDomain,
+		})
+	}
+
+	for _, call := range e.graph.Edges(graph.EdgeSync) {
+		from, to := call.From.Name(), call.To.Name()
+		if !e.inScope(rule.To, to) || e.inScope(rule.To, from) || e.sameService(from, to) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Rule:    rule,
+			Message: fmt.Sprintf("sync call from %s to %s bypasses exposure %s", from, to, rule.Through),
+			UseCase: call.UseCase,
+			Line:    call.Line,
+			From:    from,
+			To:      to,
+		})
+	}
+
+	return violations
+}
+
+// checkMaxSyncHops reports scenarios with more sync calls than the limit, at the first call over it
+func (e *Engine) checkMaxSyncHops(rule parser.Rule) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, useCase := range e.model.UseCases {
+		syncCalls := make(map[int][]graph.Edge)
+		for _, call := range e.graph.Edges(graph.EdgeSync) {
+			if call.UseCase == useCase.Name {
+				syncCalls[call.Scenario] = append(syncCalls[call.Scenario], call)
+			}
+		}
+
+		for i, scenario := range useCase.Scenarios {
+			calls := syncCalls[i]
+			if len(calls) <= rule.Limit {
+				continue
+			}
+
+			first := calls[rule.Limit]
+			violations = append(violations, Violation{
+				Rule: rule,
+				Message: fmt.Sprintf("scenario '%s' makes %d sync hops, limit is %d",
+					scenario.Trigger.Description, len(calls), rule.Limit),
+				UseCase: useCase.Name,
+				Line:    first.Line,
+				From:    first.From.Name(),
+				To:      first.To.Name(),
+			})
+		}
+	}
+
+	return violations
+}
+
+// inScope reports whether a domain belongs to a scope: "any", a service name or a domain name
+func (e *Engine) inScope(scope, domain string) bool {
+	if scope == parser.RuleScopeAny {
+		return true
+	}
+	if e.graph.Has(graph.ID(graph.NodeService, scope)) {
+		return e.graph.ServiceOf(domain) == scope
+	}
+	return scope == domain
+}
+
+func (e *Engine) isKnownScope(scope string) bool {
+	return scope == parser.RuleScopeAny ||
+		e.graph.Has(graph.ID(graph.NodeService, scope)) ||
+		e.graph.Has(graph.ID(graph.NodeDomain, scope))
+}
+
+// sameService reports whether two domains are deployed together; unowned domains only match themselves
+func (e *Engine) sameService(domainA, domainB string) bool {
+	ownerA, ownerB := e.graph.ServiceOf(domainA), e.graph.ServiceOf(domainB)
+	if ownerA == "" || ownerB == "" {
+		return domainA == domainB
+	}
+	return ownerA == ownerB
+}
+
+func (e *Engine) findExposure(name string) *parser.Exposure {
+	for i := range e.model.Exposures {
+		if e.model.Exposures[i].Name == name {
+			return &e.model.Exposures[i]
+		}
+	}
+	return nil
+}
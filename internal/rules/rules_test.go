@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func paymentsModel(rules ...parser.Rule) *parser.DSLModel {
+	return &parser.DSLModel{
+		Services: []parser.Service{
+			{Name: "NotificationService", Domains: []string{"Notification", "Templates"}},
+			{Name: "PaymentService", Domains: []string{"Payments", "Refunds"}},
+			{Name: "OrderService", Domains: []string{"Orders"}},
+		},
+		Exposures: []parser.Exposure{
+			{Name: "PaymentsAPI", To: []string{"Customer"}, Of: []string{"Payments"}},
+		},
+		UseCases: []parser.UseCase{
+			{
+				Name: "Checkout",
+				Scenarios: []parser.Scenario{
+					{
+						Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Customer", Verb: "pays", Phrase: "order", Description: "Customer pays order", Line: 10},
+						Actions: []parser.Action{
+							{Type: parser.ActionTypeSync, Domain: "Payments", TargetDomain: "Orders", Phrase: "load order", Line: 11},
+							{Type: parser.ActionTypeSync, Domain: "Orders", TargetDomain: "Notification", Phrase: "send receipt", Line: 12},
+							{Type: parser.ActionTypeSync, Domain: "Notification", TargetDomain: "Templates", Phrase: "render receipt", Line: 13},
+							{Type: parser.ActionTypeSync, Domain: "Notification", TargetDomain: "Orders", Phrase: "confirm delivery", Line: 14},
+						},
+					},
+					{
+						Trigger: parser.Trigger{Type: parser.TriggerTypeExternal, Actor: "Admin", Verb: "issues", Phrase: "refund", Line: 16},
+						Actions: []parser.Action{
+							{Type: parser.ActionTypeSync, Domain: "Refunds", TargetDomain: "Payments", Phrase: "reverse charge", Line: 17},
+						},
+					},
+				},
+			},
+		},
+		Rules: rules,
+	}
+}
+
+func TestEvaluate_NoSyncToAnyIgnoresCallsWithinService(t *testing.T) {
+	model := paymentsModel(parser.Rule{Type: parser.RuleTypeNoSync, From: "NotificationService", To: parser.RuleScopeAny, Text: "no_sync from NotificationService to any", Line: 2})
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %+v", len(violations), violations)
+	}
+
+	v := violations[0]
+	if v.From != "Notification" || v.To != "Orders" || v.Line != 14 || v.UseCase != "Checkout" {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+}
+
+func TestEvaluate_NoSyncBetweenDomains(t *testing.T) {
+	model := paymentsModel(parser.Rule{Type: parser.RuleTypeNoSync, From: "Notification", To: "Templates", Text: "no_sync from Notification to Templates"})
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 1 || violations[0].Line != 13 {
+		t.Errorf("Expected the call within the service to be reported when domains are named, got %+v", violations)
+	}
+}
+
+func TestEvaluate_CallsOnlyThroughExposure(t *testing.T) {
+	model := paymentsModel(parser.Rule{Type: parser.RuleTypeCallsThrough, To: "PaymentService", Through: "PaymentsAPI", Text: "calls to PaymentService only through PaymentsAPI"})
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %+v", len(violations), violations)
+	}
+
+	// Customer enters Payments through the exposure; Admin reaches Refunds without one
+	v := violations[0]
+	if v.From != "Admin" || v.To != "Refunds" || v.Line != 16 {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+}
+
+func TestEvaluate_CallsThroughReportsSyncCallsFromOtherServices(t *testing.T) {
+	model := paymentsModel(parser.Rule{Type: parser.RuleTypeCallsThrough, To: "Orders", Through: "PaymentsAPI", Text: "calls to Orders only through PaymentsAPI"})
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d: %+v", len(violations), violations)
+	}
+	if violations[0].From != "Payments" || violations[1].From != "Notification" {
+		t.Errorf("Unexpected violations: %+v", violations)
+	}
+}
+
+func TestEvaluate_MaxSyncHops(t *testing.T) {
+	model := paymentsModel(parser.Rule{Type: parser.RuleTypeMaxSyncHops, Limit: 2, Text: "max_sync_hops 2"})
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %+v", len(violations), violations)
+	}
+
+	v := violations[0]
+	if v.Line != 13 || v.From != "Notification" || v.To != "Templates" {
+		t.Errorf("Expected violation at the third sync call, got %+v", v)
+	}
+	if !strings.Contains(v.Message, "4 sync hops") {
+		t.Errorf("Expected hop count in message, got %q", v.Message)
+	}
+}
+
+func TestEvaluate_InvalidRules(t *testing.T) {
+	model := paymentsModel(
+		parser.Rule{Type: parser.RuleTypeNoSync, From: "Ghost", To: parser.RuleScopeAny, Text: "no_sync from Ghost to any", Line: 2},
+		parser.Rule{Type: parser.RuleTypeCallsThrough, To: "Payments", Through: "Missing", Text: "calls to Payments only through Missing", Line: 3},
+		parser.Rule{Type: parser.RuleTypeMaxSyncHops, Limit: -1, Text: "max_sync_hops lots", Line: 4},
+	)
+
+	violations := NewEngine(model).Evaluate()
+	if len(violations) != 3 {
+		t.Fatalf("Expected 3 violations, got %d: %+v", len(violations), violations)
+	}
+
+	for i, v := range violations {
+		if !v.Invalid || v.Line != i+2 || v.From != "" || v.UseCase != "" {
+			t.Errorf("Expected invalid rule reported at its own line without an edge, got %+v", v)
+		}
+	}
+	if !strings.Contains(violations[1].Message, "unknown exposure Missing") {
+		t.Errorf("Unexpected message: %q", violations[1].Message)
+	}
+}
@@ -0,0 +1,62 @@
+package saga
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// CodeMissingCompensation marks saga steps that change state without a compensation
+const CodeMissingCompensation = "missing_compensation"
+
+// Issue is a saga step breaking a saga invariant
+type Issue struct {
+	Saga    string `json:"saga"`
+	Step    int    `json:"step"` // Index of the step within its saga
+	Line    int    `json:"line,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NeedsCompensation reports whether a step changes state that must be undone when a later step
+// fails. Every step does unless it is marked [readonly] or [retriable].
+func NeedsCompensation(step parser.SagaStep) bool {
+	return !slices.ContainsFunc(step.Action.Modifiers, func(modifier parser.ComponentModifier) bool {
+		return modifier.Key == parser.SagaModifierReadOnly || modifier.Key == parser.SagaModifierRetriable
+	})
+}
+
+// Validate checks that every state-changing step of the model's sagas has a compensation
+func Validate(model *parser.DSLModel) []Issue {
+	issues := make([]Issue, 0)
+
+	for _, saga := range model.Sagas {
+		for i, step := range saga.Steps {
+			if step.Compensation != nil || !NeedsCompensation(step) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Saga:    saga.Name,
+				Step:    i,
+				Line:    step.Action.Line,
+				Code:    CodeMissingCompensation,
+				Message: fmt.Sprintf("step %d of saga '%s' changes state but has no compensation: %s (mark it [readonly] or [retriable] if it needs none)", i+1, saga.Name, step.Action.Description),
+			})
+		}
+	}
+
+	return issues
+}
+
+// Compensations returns, for a failure at the given step, the steps to compensate in the order
+// their compensations run: the earlier steps that have one, most recent first
+func Compensations(saga parser.Saga, failed int) []int {
+	steps := make([]int, 0)
+	for i := min(failed, len(saga.Steps)) - 1; i >= 0; i-- {
+		if saga.Steps[i].Compensation != nil {
+			steps = append(steps, i)
+		}
+	}
+	return steps
+}
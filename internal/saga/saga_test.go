@@ -0,0 +1,68 @@
+package saga
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func step(description string, compensated bool, modifiers ...string) parser.SagaStep {
+	s := parser.SagaStep{Action: parser.Action{Type: parser.ActionTypeInternal, Description: description}}
+	for _, key := range modifiers {
+		s.Action.Modifiers = append(s.Action.Modifiers, parser.ComponentModifier{Key: key})
+	}
+	if compensated {
+		s.Compensation = &parser.Action{Type: parser.ActionTypeInternal, Description: "undo " + description}
+	}
+	return s
+}
+
+func transfer() parser.Saga {
+	return parser.Saga{
+		Name: "Money Transfer",
+		Steps: []parser.SagaStep{
+			step("Limits checks transfer", false, parser.SagaModifierReadOnly),
+			step("Balances reserves funds", true),
+			step("Payments debits account", false),
+			step("Ledger records transfer", true),
+			step("Notification sends confirmation", false, parser.SagaModifierRetriable),
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	model := &parser.DSLModel{Sagas: []parser.Saga{transfer()}}
+	model.Sagas[0].Steps[2].Action.Line = 7
+
+	issues := Validate(model)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %+v", issues)
+	}
+
+	issue := issues[0]
+	if issue.Saga != "Money Transfer" || issue.Step != 2 || issue.Line != 7 || issue.Code != CodeMissingCompensation {
+		t.Errorf("Unexpected issue %+v", issue)
+	}
+	if !strings.Contains(issue.Message, "step 3 of saga 'Money Transfer' changes state but has no compensation: Payments debits account") {
+		t.Errorf("Unexpected message %q", issue.Message)
+	}
+}
+
+func TestCompensations(t *testing.T) {
+	saga := transfer()
+
+	cases := map[int][]int{
+		0: {},
+		1: {},
+		3: {1},
+		4: {3, 1},
+		9: {3, 1},
+	}
+	for failed, expected := range cases {
+		if got := Compensations(saga, failed); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Failure at step %d: expected compensations %v, got %v", failed, expected, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var waterfallTemplate = template.Must(template.New("waterfall").Funcs(template.FuncMap{
+	"ms":      formatMillis,
+	"percent": percent,
+	"indent":  func(depth int) int { return depth * 16 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+td { padding: 2px 8px; vertical-align: middle; }
+td.label { width: 35%; white-space: nowrap; }
+td.num { width: 6em; text-align: right; }
+.track { position: relative; height: 14px; background: #f5f5f5; }
+.span { position: absolute; height: 14px; background: #BBDEFB; }
+.own { position: absolute; height: 14px; background: #1E88E5; }
+.warn { color: #C62828; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Report.Warnings}}<p class="warn">{{.}}</p>
+{{end}}{{$percentile := .Report.Percentile}}{{range .Report.UseCases}}{{$scale := .Critical}}
+<h2>{{.Name}}</h2>
+<p>Critical path {{ms .Critical}} {{$percentile}}{{if .SLO}}, SLO {{ms .SLO}}{{if .Exceeded}} <span class="warn">exceeded</span>{{end}}{{end}}</p>
+{{range .Runs}}<h3>{{.Trigger}} ({{ms .Total}})</h3>
+<table>{{range .Spans}}
+<tr><td class="label" style="padding-left: {{indent .Depth}}px">{{.Label}}</td><td><div class="track"><div class="span" style="left: {{percent .Start $scale}}%; width: {{percent .Duration $scale}}%"></div><div class="own" style="left: {{percent .Start $scale}}%; width: {{percent .Own $scale}}%"></div></div></td><td class="num">{{ms .Duration}}</td></tr>{{end}}
+</table>
+{{end}}{{else}}<p>No externally triggered scenarios to simulate</p>{{end}}
+</body>
+</html>
+`))
+
+// Duration is the time from the span's start until its call returns
+func (s Span) Duration() float64 {
+	return s.End - s.Start
+}
+
+// HTML renders the report as a standalone page with a waterfall chart per scenario
+func (r *Report) HTML(title string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := waterfallTemplate.Execute(&buf, struct {
+		Title  string
+		Report *Report
+	}{Title: title, Report: r})
+	return buf.Bytes(), err
+}
+
+func percent(value, scale float64) float64 {
+	if scale <= 0 {
+		return 0
+	}
+	return value / scale * 100
+}
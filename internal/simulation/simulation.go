@@ -0,0 +1,284 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// SLOModifier is the use case modifier holding its latency budget, e.g. [slo:200ms]
+const SLOModifier = "slo"
+
+// Options select the latency figures to simulate with
+type Options struct {
+	Percentile string // Modifier key read from sync actions and domains, e.g. p99
+}
+
+// DefaultOptions returns the options used by the command line
+func DefaultOptions() Options {
+	return Options{Percentile: "p99"}
+}
+
+// Span is one step of a scenario on the waterfall. Own is the latency of the step itself;
+// a span ends when its call returns, so it also covers the calls made while it is open.
+type Span struct {
+	Label string  `json:"label"`
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+	Depth int     `json:"depth"`
+	Start float64 `json:"start_ms"`
+	Own   float64 `json:"own_ms"`
+	End   float64 `json:"end_ms"`
+	Line  int     `json:"line,omitempty"`
+}
+
+// Run is the simulation of one externally triggered scenario
+type Run struct {
+	Scenario int     `json:"scenario"` // Index of the scenario within its use case
+	Trigger  string  `json:"trigger"`
+	Spans    []Span  `json:"spans"`
+	Total    float64 `json:"total_ms"` // Critical-path latency
+}
+
+// UseCaseResult holds the runs of a use case and how its slowest run compares with its SLO
+type UseCaseResult struct {
+	Name     string  `json:"name"`
+	SLO      float64 `json:"slo_ms,omitempty"` // Zero when the use case declares none
+	Critical float64 `json:"critical_ms"`
+	Exceeded bool    `json:"exceeded"`
+	Runs     []Run   `json:"runs"`
+}
+
+// Report is the result of simulating every externally triggered scenario of a model
+type Report struct {
+	Percentile string          `json:"percentile"`
+	UseCases   []UseCaseResult `json:"use_cases"`
+	Warnings   []string        `json:"warnings,omitempty"` // Latencies that could not be read
+}
+
+// simulator holds the latencies of the model
+type simulator struct {
+	options  Options
+	domains  map[string]float64
+	warnings []string
+}
+
+// Simulate walks the call stack of each externally triggered scenario, adding the latency of the
+// entry domain and of every sync call; a call without its own latency costs its target domain's.
+// Events are published without waiting, so they do not extend the critical path.
+func Simulate(model *parser.DSLModel, options Options) *Report {
+	s := &simulator{options: options, domains: make(map[string]float64), warnings: make([]string, 0)}
+
+	for _, service := range model.Services {
+		for _, domain := range service.Domains {
+			if latency, ok := s.latency(service.DomainModifiers[domain], options.Percentile, fmt.Sprintf("domain %s", domain)); ok {
+				s.domains[domain] = latency
+			}
+		}
+	}
+
+	report := &Report{Percentile: options.Percentile, UseCases: make([]UseCaseResult, 0)}
+	for _, useCase := range model.UseCases {
+		result := UseCaseResult{Name: useCase.Name, Runs: make([]Run, 0)}
+		if slo, ok := s.latency(useCase.Modifiers, SLOModifier, fmt.Sprintf("use case '%s'", useCase.Name)); ok {
+			result.SLO = slo
+		}
+
+		for i, scenario := range useCase.Scenarios {
+			if scenario.Trigger.Type != parser.TriggerTypeExternal || graph.EntryDomain(scenario) == "" {
+				continue
+			}
+			run := s.run(i, scenario)
+			result.Runs = append(result.Runs, run)
+			result.Critical = max(result.Critical, run.Total)
+		}
+
+		if len(result.Runs) == 0 {
+			continue
+		}
+		result.Exceeded = result.SLO > 0 && result.Critical > result.SLO
+		report.UseCases = append(report.UseCases, result)
+	}
+
+	report.Warnings = s.warnings
+	return report
+}
+
+// run simulates a scenario. Spans stay open on a call stack like the domain diagram's: a sync call
+// is nested in the open call made to its caller, and a return closes the calls back to its target.
+func (s *simulator) run(index int, scenario parser.Scenario) Run {
+	trigger := scenario.Trigger
+	entry := graph.EntryDomain(scenario)
+
+	run := Run{Scenario: index, Trigger: trigger.Description, Spans: make([]Span, 0)}
+	cursor := s.domains[entry]
+	run.Spans = append(run.Spans, Span{
+		Label: strings.TrimSpace(fmt.Sprintf("%s %s %s", trigger.Actor, trigger.Verb, trigger.Phrase)),
+		From:  trigger.Actor,
+		To:    entry,
+		Own:   cursor,
+		Line:  trigger.Line,
+	})
+	stack := []int{0}
+
+	closeTop := func() int {
+		top := stack[len(stack)-1]
+		run.Spans[top].End = cursor
+		stack = stack[:len(stack)-1]
+		return top
+	}
+
+	for _, action := range scenario.Actions {
+		switch action.Type {
+		case parser.ActionTypeSync:
+			if action.TargetDomain == "" {
+				continue
+			}
+			// Calls made by someone other than the current callee finish the calls in between
+			for len(stack) > 1 && run.Spans[stack[len(stack)-1]].To != action.Domain {
+				closeTop()
+			}
+
+			own, ok := s.latency(action.Modifiers, s.options.Percentile, fmt.Sprintf("line %d", action.Line))
+			if !ok {
+				own = s.domains[action.TargetDomain]
+			}
+			run.Spans = append(run.Spans, Span{
+				Label: fmt.Sprintf("%s -> %s: %s", action.Domain, action.TargetDomain, action.Phrase),
+				From:  action.Domain,
+				To:    action.TargetDomain,
+				Depth: len(stack),
+				Start: cursor,
+				Own:   own,
+				Line:  action.Line,
+			})
+			stack = append(stack, len(run.Spans)-1)
+			cursor += own
+		case parser.ActionTypeReturn:
+			for len(stack) > 1 {
+				if closed := closeTop(); action.TargetDomain == "" || run.Spans[closed].From == action.TargetDomain {
+					break
+				}
+			}
+		}
+	}
+
+	for len(stack) > 0 {
+		closeTop()
+	}
+	run.Total = cursor
+	return run
+}
+
+// latency reads the modifier with the given key as a duration in milliseconds
+func (s *simulator) latency(modifiers []parser.ComponentModifier, key, subject string) (float64, bool) {
+	for _, modifier := range modifiers {
+		if modifier.Key != key {
+			continue
+		}
+		duration, err := time.ParseDuration(modifier.Value)
+		if err != nil || duration < 0 {
+			s.warnings = append(s.warnings, fmt.Sprintf("%s: invalid latency %s:%s", subject, modifier.Key, modifier.Value))
+			return 0, false
+		}
+		return float64(duration) / float64(time.Millisecond), true
+	}
+	return 0, false
+}
+
+// Exceeded returns the use cases whose critical path is over their SLO
+func (r *Report) Exceeded() []UseCaseResult {
+	exceeded := make([]UseCaseResult, 0)
+	for _, useCase := range r.UseCases {
+		if useCase.Exceeded {
+			exceeded = append(exceeded, useCase)
+		}
+	}
+	return exceeded
+}
+
+// JSON renders the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// waterfallWidth is the number of characters the longest run spans in text output
+const waterfallWidth = 40
+
+// Text renders each use case with a text waterfall of its runs
+func (r *Report) Text() string {
+	var sb strings.Builder
+
+	for _, warning := range r.Warnings {
+		sb.WriteString(fmt.Sprintf("warning: %s\n", warning))
+	}
+	if len(r.Warnings) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if len(r.UseCases) == 0 {
+		sb.WriteString("No externally triggered scenarios to simulate\n")
+	}
+
+	for i, useCase := range r.UseCases {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s %s", useCase.Name, formatMillis(useCase.Critical), r.Percentile))
+		if useCase.SLO > 0 {
+			status := "within"
+			if useCase.Exceeded {
+				status = "EXCEEDS"
+			}
+			sb.WriteString(fmt.Sprintf(", %s SLO %s", status, formatMillis(useCase.SLO)))
+		}
+		sb.WriteString("\n")
+
+		for _, run := range useCase.Runs {
+			sb.WriteString(fmt.Sprintf("  %s (%s)\n", run.Trigger, formatMillis(run.Total)))
+			for _, span := range run.Spans {
+				sb.WriteString(fmt.Sprintf("    %s %s %s\n", bar(span, useCase.Critical),
+					strings.Repeat("  ", span.Depth)+span.Label, formatMillis(span.Duration())))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// bar draws a span on a scale where the critical path fills the waterfall width
+func bar(span Span, scale float64) string {
+	if scale <= 0 {
+		return strings.Repeat(" ", waterfallWidth)
+	}
+	start := int(span.Start / scale * waterfallWidth)
+	end := int(span.End / scale * waterfallWidth)
+	ownEnd := int((span.Start + span.Own) / scale * waterfallWidth)
+	if end == start {
+		end = start + 1
+	}
+	if ownEnd == start && span.Own > 0 {
+		ownEnd = start + 1
+	}
+
+	var sb strings.Builder
+	for i := 0; i < waterfallWidth; i++ {
+		switch {
+		case i >= start && i < ownEnd:
+			sb.WriteString("█")
+		case i >= start && i < end:
+			sb.WriteString("░")
+		default:
+			sb.WriteString("·")
+		}
+	}
+	return sb.String()
+}
+
+func formatMillis(ms float64) string {
+	return fmt.Sprintf("%gms", ms)
+}
@@ -0,0 +1,118 @@
+package simulation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// checkoutModel parses the checkout model with the given latency modifiers on the capture call
+func checkoutModel(t *testing.T, capture string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(`services {
+  OrderService {
+    domains: Orders [p99:10ms], Billing [p99:30ms]
+  }
+  PaymentService {
+    domains: Payments
+  }
+}
+
+use_case "Checkout" [slo:100ms] {
+  when Customer places order
+    Orders asks Billing to charge card
+    Billing asks Payments to capture [` + capture + `]
+    Payments returns receipt
+    Billing returns to Orders ok
+    Orders asks Billing to draft invoice [p99:20ms]
+    Orders notifies "Order Placed"
+
+  when Payments listens "Order Placed"
+    Payments asks Billing to settle [p99:500ms]
+}
+
+use_case "Browse" [slo:1s] {
+  when Customer lists orders
+    Orders asks Payments to load history [p99:fast]
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestSimulate_CriticalPath(t *testing.T) {
+	report := Simulate(checkoutModel(t, "p99:50ms"), DefaultOptions())
+
+	if len(report.UseCases) != 2 {
+		t.Fatalf("Expected 2 simulated use cases, got %d", len(report.UseCases))
+	}
+
+	checkout := report.UseCases[0]
+	if len(checkout.Runs) != 1 {
+		t.Fatalf("Expected only the external scenario to run, got %d runs", len(checkout.Runs))
+	}
+	if checkout.Critical != 110 || checkout.SLO != 100 || !checkout.Exceeded {
+		t.Errorf("Expected 110ms against a 100ms SLO to be exceeded, got %+v", checkout)
+	}
+
+	// Entry 10ms, charge card 30ms (Billing's default) wrapping capture 50ms, then draft invoice 20ms
+	expected := []Span{
+		{Label: "Customer places order", From: "Customer", To: "Orders", Depth: 0, Start: 0, Own: 10, End: 110},
+		{Label: "Orders -> Billing: charge card", From: "Orders", To: "Billing", Depth: 1, Start: 10, Own: 30, End: 90},
+		{Label: "Billing -> Payments: capture", From: "Billing", To: "Payments", Depth: 2, Start: 40, Own: 50, End: 90},
+		{Label: "Orders -> Billing: draft invoice", From: "Orders", To: "Billing", Depth: 1, Start: 90, Own: 20, End: 110},
+	}
+	if !reflect.DeepEqual(checkout.Runs[0].Spans, expected) {
+		t.Errorf("Unexpected spans:\nExpected %+v\nGot      %+v", expected, checkout.Runs[0].Spans)
+	}
+
+	browse := report.UseCases[1]
+	if browse.Critical != 10 || browse.Exceeded {
+		t.Errorf("Expected Browse to fall back to 10ms within its SLO, got %+v", browse)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "line 25: invalid latency p99:fast") {
+		t.Errorf("Expected a warning for the invalid latency, got %v", report.Warnings)
+	}
+
+	if exceeded := report.Exceeded(); len(exceeded) != 1 || exceeded[0].Name != "Checkout" {
+		t.Errorf("Expected only Checkout to exceed its SLO, got %+v", exceeded)
+	}
+}
+
+func TestSimulate_Percentile(t *testing.T) {
+	model := checkoutModel(t, "p99:50ms, p50:5ms")
+
+	report := Simulate(model, Options{Percentile: "p50"})
+	if got := report.UseCases[0].Critical; got != 5 {
+		t.Errorf("Expected only the p50 figure to count, got %gms", got)
+	}
+}
+
+func TestReport_Render(t *testing.T) {
+	report := Simulate(checkoutModel(t, "p99:50ms"), DefaultOptions())
+
+	text := report.Text()
+	for _, expected := range []string{
+		"Checkout: 110ms p99, EXCEEDS SLO 100ms",
+		"Browse: 10ms p99, within SLO 1000ms",
+		"    Billing -> Payments: capture 50ms",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected text to contain %q, got:\n%s", expected, text)
+		}
+	}
+
+	html, err := report.HTML("Latency <budget>")
+	if err != nil {
+		t.Fatalf("Failed to render HTML: %v", err)
+	}
+	for _, expected := range []string{"Latency &lt;budget&gt;", "left: 36.36", "exceeded"} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", expected, html)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/graph"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// Step is a scenario reached by the trace. The root is the scenario the trace starts from;
+// every other step is a scenario listening to an event published by its parent.
+type Step struct {
+	UseCase   string         `json:"use_case"`
+	Scenario  int            `json:"scenario"` // Index of the scenario within its use case
+	ID        string         `json:"id,omitempty"`
+	Trigger   parser.Trigger `json:"trigger"`
+	Entry     string         `json:"entry,omitempty"`     // Domain the scenario starts in, the listener for event triggers
+	Event     string         `json:"event,omitempty"`     // Event delivered to the step, empty for the root
+	Publisher string         `json:"publisher,omitempty"` // Domain of the parent that published the event
+	Cycle     bool           `json:"cycle,omitempty"`     // The event was already delivered on the way here, so the step is not followed
+	Children  []*Step        `json:"children,omitempty"`
+
+	Actions []parser.Action `json:"-"`
+}
+
+// Trace follows the events published from a scenario through every scenario listening to them,
+// across use cases, until no new events are published or an event loops back
+type Trace struct {
+	Root   *Step      `json:"root"`
+	Events []string   `json:"events"` // Events delivered along the trace, in order of first delivery
+	Cycles [][]string `json:"cycles"` // Event chains leading back to their first event
+
+	ExternalSystems []string `json:"external_systems,omitempty"` // Declared external systems called along the trace
+}
+
+// listener is a scenario started by an event
+type listener struct {
+	useCase  string
+	index    int
+	scenario parser.Scenario
+}
+
+// Follow traces a scenario of a use case. A negative index starts from the use case's first
+// externally triggered scenario.
+func Follow(model *parser.DSLModel, useCase string, index int) (*Trace, error) {
+	var start *parser.UseCase
+	for i := range model.UseCases {
+		if model.UseCases[i].Name == useCase {
+			start = &model.UseCases[i]
+			break
+		}
+	}
+	if start == nil {
+		return nil, fmt.Errorf("unknown use case %s", useCase)
+	}
+
+	if index < 0 {
+		index = slices.IndexFunc(start.Scenarios, func(scenario parser.Scenario) bool {
+			return scenario.Trigger.Type == parser.TriggerTypeExternal
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("use case %s has no externally triggered scenario", useCase)
+		}
+	} else if index >= len(start.Scenarios) {
+		return nil, fmt.Errorf("use case %s has no scenario %d", useCase, index)
+	}
+
+	listeners := make(map[string][]listener)
+	for _, uc := range model.UseCases {
+		for i, scenario := range uc.Scenarios {
+			trigger := scenario.Trigger
+			if (trigger.Type == parser.TriggerTypeDomainListen || trigger.Type == parser.TriggerTypeEvent) && trigger.Event != "" {
+				listeners[trigger.Event] = append(listeners[trigger.Event], listener{useCase: uc.Name, index: i, scenario: scenario})
+			}
+		}
+	}
+
+	t := &Trace{Events: make([]string, 0), Cycles: make([][]string, 0)}
+	t.Root = newStep(start.Name, index, start.Scenarios[index])
+	t.follow(t.Root, listeners, nil)
+	t.ExternalSystems = externalSystems(model, t.Root)
+	return t, nil
+}
+
+// follow expands the scenarios listening to the events a step publishes; path holds the events
+// delivered on the way to the step
+func (t *Trace) follow(step *Step, listeners map[string][]listener, path []string) {
+	for _, action := range step.Actions {
+		if action.Type != parser.ActionTypeAsync || action.Event == "" {
+			continue
+		}
+		if !slices.Contains(t.Events, action.Event) {
+			t.Events = append(t.Events, action.Event)
+		}
+
+		loop := slices.Index(path, action.Event)
+		if loop >= 0 {
+			t.addCycle(append(slices.Clone(path[loop:]), action.Event))
+		}
+
+		for _, l := range listeners[action.Event] {
+			child := newStep(l.useCase, l.index, l.scenario)
+			child.Event = action.Event
+			child.Publisher = action.Domain
+			step.Children = append(step.Children, child)
+
+			if loop >= 0 {
+				child.Cycle = true
+				continue
+			}
+			t.follow(child, listeners, append(slices.Clone(path), action.Event))
+		}
+	}
+}
+
+// externalSystems returns the declared external systems called from a step and the steps below it,
+// in order of first call
+func externalSystems(model *parser.DSLModel, step *Step) []string {
+	systems := make([]string, 0)
+	for _, action := range step.Actions {
+		if action.Type == parser.ActionTypeSync && model.ExternalSystem(action.TargetDomain) != nil && !slices.Contains(systems, action.TargetDomain) {
+			systems = append(systems, action.TargetDomain)
+		}
+	}
+	for _, child := range step.Children {
+		for _, system := range externalSystems(model, child) {
+			if !slices.Contains(systems, system) {
+				systems = append(systems, system)
+			}
+		}
+	}
+	return systems
+}
+
+// addCycle records an event chain unless the same loop is already known
+func (t *Trace) addCycle(cycle []string) {
+	for _, known := range t.Cycles {
+		if slices.Equal(known, cycle) {
+			return
+		}
+	}
+	t.Cycles = append(t.Cycles, cycle)
+}
+
+func newStep(useCase string, index int, scenario parser.Scenario) *Step {
+	step := &Step{
+		UseCase:  useCase,
+		Scenario: index,
+		ID:       scenario.ID,
+		Trigger:  scenario.Trigger,
+		Entry:    scenario.Trigger.Domain,
+		Actions:  scenario.Actions,
+	}
+	if step.Entry == "" {
+		step.Entry = graph.EntryDomain(scenario)
+	}
+	return step
+}
+
+// JSON renders the trace as indented JSON
+func (t *Trace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// Text renders the trace as an indented tree of scenarios
+func (t *Trace) Text() string {
+	var sb strings.Builder
+
+	var walk func(step *Step, depth int)
+	walk = func(step *Step, depth int) {
+		indent := strings.Repeat("  ", depth)
+		if step.Event == "" {
+			sb.WriteString(fmt.Sprintf("%s%s: %s\n", indent, step.UseCase, step.Trigger.Description))
+		} else {
+			line := fmt.Sprintf("%s%q from %s -> %s: %s", indent, step.Event, step.Publisher, step.UseCase, step.Trigger.Description)
+			if step.Cycle {
+				line += " (cycle, not followed)"
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, child := range step.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(t.Root, 0)
+
+	if len(t.Cycles) > 0 {
+		sb.WriteString("\nEvent cycles:\n")
+		for _, cycle := range t.Cycles {
+			quoted := make([]string, 0, len(cycle))
+			for _, event := range cycle {
+				quoted = append(quoted, fmt.Sprintf("%q", event))
+			}
+			sb.WriteString(fmt.Sprintf("  %s\n", strings.Join(quoted, " -> ")))
+		}
+	}
+
+	return sb.String()
+}
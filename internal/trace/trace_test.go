@@ -0,0 +1,155 @@
+package trace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+const order = `use_case "Checkout" {
+  when Audit listens "Order Placed"
+
+  when Customer places order
+    Order asks Payment to charge card
+    Payment returns receipt
+    Order notifies "Order Placed"
+}
+
+use_case "Fulfilment" {
+  when Inventory listens "Order Placed"
+    Inventory notifies "Stock Reserved"
+
+  when Shipping listens "Stock Reserved"
+    Shipping notifies "Shipment Created"
+
+  when Billing listens "Stock Reserved"
+    Billing notifies "Order Placed"
+
+  when "Shipment Created"
+    Notification asks Email to send tracking link
+}
+
+use_case "Marketplace Sale" {
+  when Seller sells item
+    Marketplace notifies "Order Placed"
+}
+`
+
+func orderModel(t *testing.T, extra string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(order + extra)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+// shape flattens a trace to "publisher>event>entry" lines with their depth
+func shape(step *Step, depth int) []string {
+	lines := []string{strings.Repeat(" ", depth) + step.Publisher + ">" + step.Event + ">" + step.Entry}
+	if step.Cycle {
+		lines[0] += " (cycle)"
+	}
+	for _, child := range step.Children {
+		lines = append(lines, shape(child, depth+1)...)
+	}
+	return lines
+}
+
+func TestFollow(t *testing.T) {
+	trace, err := Follow(orderModel(t, ""), "Checkout", -1)
+	if err != nil {
+		t.Fatalf("Failed to trace: %v", err)
+	}
+
+	if trace.Root.Scenario != 1 || trace.Root.Entry != "Order" {
+		t.Errorf("Expected to start from the externally triggered scenario in Order, got %+v", trace.Root)
+	}
+
+	expected := []string{
+		">>Order",
+		" Order>Order Placed>Audit",
+		" Order>Order Placed>Inventory",
+		"  Inventory>Stock Reserved>Shipping",
+		"   Shipping>Shipment Created>Notification",
+		"  Inventory>Stock Reserved>Billing",
+		"   Billing>Order Placed>Audit (cycle)",
+		"   Billing>Order Placed>Inventory (cycle)",
+	}
+	if got := shape(trace.Root, 0); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected trace:\nExpected %q\nGot      %q", expected, got)
+	}
+
+	if expectedEvents := []string{"Order Placed", "Stock Reserved", "Shipment Created"}; !reflect.DeepEqual(trace.Events, expectedEvents) {
+		t.Errorf("Expected events %v, got %v", expectedEvents, trace.Events)
+	}
+	if expectedCycles := [][]string{{"Order Placed", "Stock Reserved", "Order Placed"}}; !reflect.DeepEqual(trace.Cycles, expectedCycles) {
+		t.Errorf("Expected cycles %v, got %v", expectedCycles, trace.Cycles)
+	}
+
+	text := trace.Text()
+	for _, line := range []string{
+		"Checkout: when Customer places order",
+		"    \"Shipment Created\" from Shipping -> Fulfilment: when \"Shipment Created\"",
+		"(cycle, not followed)",
+		"\"Order Placed\" -> \"Stock Reserved\" -> \"Order Placed\"",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected text to contain %q, got:\n%s", line, text)
+		}
+	}
+}
+
+func TestFollow_ExternalSystems(t *testing.T) {
+	model := orderModel(t, `
+external_system Email {
+  protocol: smtp
+}
+
+external_system Stripe {}
+`)
+
+	trace, err := Follow(model, "Checkout", -1)
+	if err != nil {
+		t.Fatalf("Failed to trace: %v", err)
+	}
+	if expected := []string{"Email"}; !reflect.DeepEqual(trace.ExternalSystems, expected) {
+		t.Errorf("Expected external systems %v, got %v", expected, trace.ExternalSystems)
+	}
+
+	trace, _ = Follow(orderModel(t, ""), "Checkout", -1)
+	if len(trace.ExternalSystems) != 0 {
+		t.Errorf("Expected no external systems without declarations, got %v", trace.ExternalSystems)
+	}
+}
+
+func TestFollow_OtherPublisher(t *testing.T) {
+	trace, err := Follow(orderModel(t, ""), "Marketplace Sale", 0)
+	if err != nil {
+		t.Fatalf("Failed to trace: %v", err)
+	}
+
+	if len(trace.Root.Children) != 2 || trace.Root.Children[1].Publisher != "Marketplace" || trace.Root.Children[1].Entry != "Inventory" {
+		t.Errorf("Expected the Order Placed listeners to be reached from Marketplace, got %q", shape(trace.Root, 0))
+	}
+}
+
+func TestFollow_Errors(t *testing.T) {
+	cases := []struct {
+		useCase  string
+		scenario int
+		expected string
+	}{
+		{"Returns", -1, "unknown use case Returns"},
+		{"Fulfilment", -1, "use case Fulfilment has no externally triggered scenario"},
+		{"Checkout", 5, "use case Checkout has no scenario 5"},
+	}
+
+	for _, c := range cases {
+		if _, err := Follow(orderModel(t, ""), c.useCase, c.scenario); err == nil || err.Error() != c.expected {
+			t.Errorf("Expected error %q, got %v", c.expected, err)
+		}
+	}
+}
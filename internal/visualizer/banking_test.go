@@ -0,0 +1,62 @@
+package visualizer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden diagrams in testdata")
+
+// bankingModel parses the banking fixture shared by the package tests
+func bankingModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	source, err := os.ReadFile(filepath.Join("..", "..", "testdata", "banking.craft"))
+	if err != nil {
+		t.Fatalf("Failed to read banking fixture: %v", err)
+	}
+	model, err := parser.ParseDSLToModel(string(source))
+	if err != nil {
+		t.Fatalf("Failed to parse banking fixture: %v", err)
+	}
+	return model
+}
+
+// assertGolden compares a diagram with testdata/<name>, rewriting the file with -update
+func assertGolden(t *testing.T, name, diagram string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(diagram), 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if diagram != string(expected) {
+		t.Errorf("Diagram differs from %s, rerun with -update after checking the change.\nGot:\n%s", path, diagram)
+	}
+}
+
+func TestBanking_C4ContainerBoundaries(t *testing.T) {
+	assertGolden(t, "banking_c4_boundaries.puml", GenerateC4ContainerDiagram(bankingModel(t), C4ModeBoundaries, true))
+}
+
+func TestBanking_C4ContainerTransparent(t *testing.T) {
+	assertGolden(t, "banking_c4_transparent.puml", GenerateC4ContainerDiagram(bankingModel(t), C4ModeTransparent, true))
+}
+
+func TestBanking_DomainFlow(t *testing.T) {
+	assertGolden(t, "banking_domain_flow.puml", GenerateDomainFlowDiagram(bankingModel(t)))
+}
+
+func TestBanking_Architecture(t *testing.T) {
+	assertGolden(t, "banking_architecture.puml", NewPlantUMLArchitectureGenerator().GenerateArchitecturePlantUML(bankingModel(t)))
+}
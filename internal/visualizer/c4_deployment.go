@@ -0,0 +1,217 @@
+package visualizer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// SetArchitecture selects the arch block drawn by deployment diagrams; empty selects the first one
+func (g *C4DiagramGenerator) SetArchitecture(name string) {
+	g.architecture = name
+}
+
+// FindArchitecture returns the arch block with the given name, or the first one when name is empty
+func FindArchitecture(model *parser.DSLModel, name string) *parser.Architecture {
+	for i := range model.Architectures {
+		if name == "" || model.Architectures[i].Name == name {
+			return &model.Architectures[i]
+		}
+	}
+	return nil
+}
+
+// buildDeploymentDiagram draws the selected arch block as an environment: deployment nodes for the
+// presentation and gateway chains, then one node per service holding an instance per rollout
+// target. Edges carry the traffic split of each service's deployment rules.
+func (g *C4DiagramGenerator) buildDeploymentDiagram(sb *strings.Builder) {
+	arch := FindArchitecture(g.model, g.architecture)
+	environment := "default"
+	if arch != nil && arch.Name != "" {
+		environment = arch.Name
+	}
+	env := plantUMLAlias("env", environment)
+
+	sb.WriteString(fmt.Sprintf("Deployment_Node(%s, \"%s\", \"Environment\") {\n", env, environment))
+
+	// Entry and exit containers of each chain, in component order, to connect the tiers
+	var presentationExits, gatewayExits, gatewayEntries, chainRels []string
+	if arch != nil {
+		presentationExits, _ = g.writeDeploymentTier(sb, env, "Presentation", "Client", arch.Presentation, &chainRels, g.inferPresentationTechnology)
+		gatewayExits, gatewayEntries = g.writeDeploymentTier(sb, env, "Gateway", "Edge", arch.Gateway, &chainRels, g.inferGatewayTechnology)
+	}
+
+	serviceEntries := make(map[string][]deploymentTarget)
+	for _, service := range g.model.Services {
+		serviceEntries[service.Name] = g.writeServiceNode(sb, env, service)
+	}
+	sb.WriteString("}\n\n")
+
+	for _, rel := range chainRels {
+		sb.WriteString(rel)
+	}
+
+	// Presentation reaches the gateways, or the services directly when there are none
+	for _, from := range distinct(presentationExits) {
+		for _, to := range distinct(gatewayEntries) {
+			sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"API Requests\", \"HTTPS\")\n", from, to))
+		}
+		if len(gatewayEntries) == 0 {
+			g.writeTrafficRels(sb, from, g.model.Services, serviceEntries)
+		}
+	}
+
+	if arch == nil {
+		return
+	}
+	for i, component := range arch.Gateway {
+		g.writeTrafficRels(sb, gatewayExits[i], g.routedServices(component), serviceEntries)
+	}
+}
+
+// deploymentTarget is a service instance and the share of traffic it receives
+type deploymentTarget struct {
+	alias      string
+	percentage string // Empty when the instance takes all traffic
+}
+
+// writeDeploymentTier writes a deployment node holding a tier's components; a flow becomes a chain
+// of containers whose links are appended to rels. A component appearing in several chains is
+// declared once. It returns the exit and entry container of each component, in component order.
+func (g *C4DiagramGenerator) writeDeploymentTier(sb *strings.Builder, env, tier, technology string, components []parser.Component, rels *[]string, infer func(parser.Component) string) ([]string, []string) {
+	if len(components) == 0 {
+		return nil, nil
+	}
+
+	node := plantUMLAlias(env, tier)
+	sb.WriteString(fmt.Sprintf("    Deployment_Node(%s, \"%s\", \"%s\") {\n", node, tier, technology))
+
+	exitAliases := make([]string, 0, len(components))
+	entryAliases := make([]string, 0, len(components))
+	declared := make(map[string]bool)
+	for _, component := range components {
+		chain := []parser.Component{component}
+		if component.Type == parser.ComponentTypeFlow && len(component.Chain) > 0 {
+			chain = component.Chain
+		}
+
+		previous := ""
+		for _, member := range chain {
+			alias := plantUMLAlias(node, member.Name)
+			if !declared[alias] {
+				declared[alias] = true
+				sb.WriteString(fmt.Sprintf("        Container(%s, \"%s\", \"%s\", \"%s\")\n",
+					alias, member.Name, infer(member), strings.TrimSpace(g.buildComponentDescription(member, tier))))
+			}
+			if previous != "" {
+				rel := fmt.Sprintf("Rel(%s, %s, \"Forwards\")\n", previous, alias)
+				if !slices.Contains(*rels, rel) {
+					*rels = append(*rels, rel)
+				}
+			}
+			previous = alias
+		}
+
+		entryAliases = append(entryAliases, plantUMLAlias(node, chain[0].Name))
+		exitAliases = append(exitAliases, previous)
+	}
+	sb.WriteString("    }\n")
+
+	return exitAliases, entryAliases
+}
+
+// writeServiceNode writes a service as a deployment node labelled with its rollout strategy, holding
+// one instance per deployment rule target, and returns the instances
+func (g *C4DiagramGenerator) writeServiceNode(sb *strings.Builder, env string, service parser.Service) []deploymentTarget {
+	node := plantUMLAlias(env, service.Name)
+	technology := g.getServiceTechnology(service.Language)
+	strategy := service.Deployment.Type
+	if strategy == "" {
+		strategy = "single instance"
+	}
+
+	sb.WriteString(fmt.Sprintf("    Deployment_Node(%s, \"%s\", \"%s\") {\n", node, service.Name, strategy))
+
+	targets := make([]deploymentTarget, 0)
+	if len(service.Deployment.Rules) == 0 {
+		alias := node + "_app"
+		sb.WriteString(fmt.Sprintf("        Container(%s, \"%s\", \"%s\", \"%s\"%s)\n",
+			alias, service.Name, technology, strings.Join(service.Domains, ", "), g.getServiceIcon(service.Language)))
+		targets = append(targets, deploymentTarget{alias: alias})
+	}
+	for _, rule := range service.Deployment.Rules {
+		alias := plantUMLAlias(node, rule.Target)
+		sb.WriteString(fmt.Sprintf("        Container(%s, \"%s (%s)\", \"%s\", \"%s of traffic\"%s)\n",
+			alias, service.Name, rule.Target, technology, rule.Percentage, g.getServiceIcon(service.Language)))
+		targets = append(targets, deploymentTarget{alias: alias, percentage: rule.Percentage})
+	}
+
+	sb.WriteString("    }\n")
+	return targets
+}
+
+// writeTrafficRels connects a container to the instances of the given services, labelling each edge
+// with the share of the service's traffic it carries
+func (g *C4DiagramGenerator) writeTrafficRels(sb *strings.Builder, from string, services []parser.Service, instances map[string][]deploymentTarget) {
+	if from == "" {
+		return
+	}
+	for _, service := range services {
+		for _, target := range instances[service.Name] {
+			label := "Routes requests"
+			if target.percentage != "" {
+				label = target.percentage
+			}
+			sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\")\n", from, target.alias, label))
+		}
+	}
+}
+
+// distinct returns the aliases without repeats, in order
+func distinct(aliases []string) []string {
+	result := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		if !slices.Contains(result, alias) {
+			result = append(result, alias)
+		}
+	}
+	return result
+}
+
+// routedServices returns the services a gateway component routes to: those owning the domains of
+// the exposures going through it, or every service when no exposure names it
+func (g *C4DiagramGenerator) routedServices(component parser.Component) []parser.Service {
+	names := []string{component.Name}
+	for _, member := range component.Chain {
+		names = append(names, member.Name)
+	}
+
+	owners := make(map[string]bool)
+	named := false
+	for _, exposure := range g.model.Exposures {
+		for _, gateway := range exposure.Through {
+			if !g.containsString(names, gateway) {
+				continue
+			}
+			named = true
+			for _, domain := range g.exposedDomains(exposure) {
+				if service := g.findServiceForDomain(domain); service != "" {
+					owners[service] = true
+				}
+			}
+		}
+	}
+	if !named {
+		return g.model.Services
+	}
+
+	services := make([]parser.Service, 0, len(owners))
+	for _, service := range g.model.Services {
+		if owners[service.Name] {
+			services = append(services, service)
+		}
+	}
+	return services
+}
@@ -0,0 +1,80 @@
+package visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func gatewayFlowModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(`services {
+  OrderService {
+    domains: Orders
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+exposure PublicAPI {
+  to: Customer
+  of: Orders
+  through: PublicGW
+}
+
+exposure AdminAPI {
+  to: Clerk
+  of: Billing
+  through: AdminGW
+}
+
+arch prod {
+  gateway:
+    LB > PublicGW
+    LB > AdminGW
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestDeploymentDiagram_RoutesEachGatewayFlowFromItsOwnExit(t *testing.T) {
+	diagram := GenerateC4DeploymentDiagram(gatewayFlowModel(t), "prod")
+
+	for _, expected := range []string{
+		`Rel(env_prod_gateway_publicgw, env_prod_orderservice_app, "Routes requests")`,
+		`Rel(env_prod_gateway_admingw, env_prod_billingservice_app, "Routes requests")`,
+	} {
+		if !strings.Contains(diagram, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, diagram)
+		}
+	}
+	for _, unexpected := range []string{
+		`Rel(env_prod_gateway_admingw, env_prod_orderservice_app`,
+		`Rel(env_prod_gateway_publicgw, env_prod_billingservice_app`,
+	} {
+		if strings.Contains(diagram, unexpected) {
+			t.Errorf("Unexpected %q in:\n%s", unexpected, diagram)
+		}
+	}
+}
+
+func TestDeploymentDiagram_DeclaresSharedChainMembersOnce(t *testing.T) {
+	diagram := GenerateC4DeploymentDiagram(gatewayFlowModel(t), "prod")
+
+	if count := strings.Count(diagram, "Container(env_prod_gateway_lb,"); count != 1 {
+		t.Errorf("Expected the shared load balancer to be declared once, got %d times in:\n%s", count, diagram)
+	}
+	for _, rel := range []string{
+		`Rel(env_prod_gateway_lb, env_prod_gateway_publicgw, "Forwards")`,
+		`Rel(env_prod_gateway_lb, env_prod_gateway_admingw, "Forwards")`,
+	} {
+		if count := strings.Count(diagram, rel); count != 1 {
+			t.Errorf("Expected %q once, got %d times in:\n%s", rel, count, diagram)
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/tcarcao/craft/internal/graph"
 	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/rules"
 )
 
 // C4GenerationMode determines how domains are represented
@@ -18,20 +20,25 @@ const (
 
 // C4DiagramGenerator generates C4 diagrams with proper system separation
 type C4DiagramGenerator struct {
-	model              *parser.DSLModel
-	mode               C4GenerationMode
-	systems            map[string]*C4System
-	containers         map[string]*C4Container
-	relations          []C4Relation
-	actors             map[string]bool
-	systemRelations    []C4Relation
-	userInteractionMap map[string][]string
-	presentationSystem *C4System
-	gatewaySystem      *C4System
-	focusedServices    map[string]bool // Services to show as internal
-	focusedSubDomains  map[string]bool // SubDomains to show as internal
-	hasFocus           bool            // Whether focus mode is enabled
-	showDatabases      bool            // Whether to show database containers
+	model               *parser.DSLModel
+	mode                C4GenerationMode
+	systems             map[string]*C4System
+	containers          map[string]*C4Container
+	relations           []C4Relation
+	actors              map[string]bool
+	systemRelations     []C4Relation
+	userInteractionMap  map[string][]string
+	presentationSystem  *C4System
+	gatewaySystem       *C4System
+	focusedServices     map[string]bool // Services to show as internal
+	focusedSubDomains   map[string]bool // SubDomains to show as internal
+	hasFocus            bool            // Whether focus mode is enabled
+	showDatabases       bool            // Whether to show database containers
+	requestedHighlights *Highlights     // Caller supplied highlights, nil for none
+	highlights          *Highlights     // Requested highlights plus rule violations of the current model
+	graph               *graph.Graph    // Dependency graph of the current model
+	architecture        string          // Arch block drawn by deployment diagrams, empty for the first
+	externalSystems     map[string][]string // Declared external systems called, with the services calling them
 }
 
 // NewC4DiagramGenerator creates a new redesigned generator
@@ -100,10 +107,20 @@ func NewC4DiagramGeneratorWithFocusAndSubDomains(mode C4GenerationMode, focusedS
 	}
 }
 
+// SetHighlights colours the given elements and relationships in generated diagrams
+func (g *C4DiagramGenerator) SetHighlights(highlights *Highlights) {
+	g.requestedHighlights = highlights
+}
+
 // GenerateC4Diagram creates a redesigned C4 diagram
 func (g *C4DiagramGenerator) GenerateC4Diagram(model *parser.DSLModel, diagramType C4DiagramType) string {
 	g.model = model
+	g.graph = graph.Build(model)
 	g.reset()
+	g.focusSubDomainServices()
+
+	// Relationships breaking the model's fitness rules are drawn as violations
+	g.highlights = g.requestedHighlights.withViolations(rules.NewEngine(model).Evaluate())
 
 	// Analyze and build systems
 	g.analyzeModel()
@@ -112,6 +129,16 @@ func (g *C4DiagramGenerator) GenerateC4Diagram(model *parser.DSLModel, diagramTy
 	return g.buildC4PlantUML(diagramType)
 }
 
+// focusSubDomainServices focuses the services owning the focused subdomains, so their containers
+// are drawn as internal
+func (g *C4DiagramGenerator) focusSubDomainServices() {
+	for subDomain := range g.focusedSubDomains {
+		if service := g.findServiceForDomain(subDomain); service != "" {
+			g.focusedServices[service] = true
+		}
+	}
+}
+
 // reset clears the generator state
 func (g *C4DiagramGenerator) reset() {
 	g.systems = make(map[string]*C4System)
@@ -119,6 +146,7 @@ func (g *C4DiagramGenerator) reset() {
 	g.relations = make([]C4Relation, 0)
 	g.actors = make(map[string]bool)
 	g.systemRelations = make([]C4Relation, 0)
+	g.externalSystems = make(map[string][]string)
 	g.userInteractionMap = make(map[string][]string)
 	g.presentationSystem = nil
 	g.gatewaySystem = nil
@@ -197,7 +225,7 @@ func (g *C4DiagramGenerator) createServiceSystems() {
 
 		system := &C4System{
 			Name:        service.Name,
-			Description: fmt.Sprintf("%s Service - Handles business logic", service.Name),
+			Description: describe(fmt.Sprintf("%s Service - Handles business logic", service.Name), service.Metadata),
 			Containers:  make([]string, 0),
 			IsExternal:  isExternal,
 		}
@@ -227,7 +255,7 @@ func (g *C4DiagramGenerator) createDomainContainers(service parser.Service, syst
 			Name:        domain,
 			System:      service.Name,
 			Technology:  g.getServiceTechnology(service.Language),
-			Description: fmt.Sprintf("%s domain logic", domain),
+			Description: describe(fmt.Sprintf("%s domain logic", domain), g.model.DomainMetadata(domain)),
 			Domains:     []string{domain},
 			DataStores:  make([]string, 0),
 		}
@@ -261,8 +289,8 @@ func (g *C4DiagramGenerator) createDatabaseContainers(service parser.Service, sy
 		container := &C4Container{
 			Name:        containerName,
 			System:      service.Name,
-			Technology:  g.inferDatabaseType(dataStore),
-			Description: fmt.Sprintf("Stores %s data", dataStore),
+			Technology:  g.databaseTechnology(dataStore),
+			Description: g.dataStoreDescription(dataStore),
 			Domains:     make([]string, 0),
 			DataStores:  []string{dataStore},
 		}
@@ -367,41 +395,20 @@ func (g *C4DiagramGenerator) createGatewaySystem() {
 
 // createEventSystemIfNeeded creates event system with queue container
 func (g *C4DiagramGenerator) createEventSystemIfNeeded() {
-	// Check if any async actions exist in focused services (or all if no focus)
+	// Check if any events are published by focused services (or all if no focus)
 	hasRelevantAsyncActions := false
 
-	for _, useCase := range g.model.UseCases {
-		for _, scenario := range useCase.Scenarios {
-			for _, action := range scenario.Actions {
-				if action.Type == parser.ActionTypeAsync {
-					if !g.hasFocus {
-						// No focus mode - include all async actions
-						hasRelevantAsyncActions = true
-						break
-					}
-
-					// Focus mode - only include if action involves focused services
-					actionService := g.findServiceForDomain(action.Domain)
-					if actionService != "" && g.focusedServices[actionService] {
-						hasRelevantAsyncActions = true
-						break
-					}
-
-					// Also check target domain
-					if action.TargetDomain != "" {
-						targetService := g.findServiceForDomain(action.TargetDomain)
-						if targetService != "" && g.focusedServices[targetService] {
-							hasRelevantAsyncActions = true
-							break
-						}
-					}
-				}
-			}
-			if hasRelevantAsyncActions {
-				break
-			}
+	for _, publish := range g.graph.Edges(graph.EdgePublish) {
+		if !g.hasFocus {
+			// No focus mode - include all async actions
+			hasRelevantAsyncActions = true
+			break
 		}
-		if hasRelevantAsyncActions {
+
+		// Focus mode - only include if the publisher belongs to a focused service
+		actionService := g.findServiceForDomain(publish.From.Name())
+		if actionService != "" && g.focusedServices[actionService] {
+			hasRelevantAsyncActions = true
 			break
 		}
 	}
@@ -466,6 +473,7 @@ func (g *C4DiagramGenerator) deduplicateRelationships() {
 // deduplicateRelationshipSlice removes duplicates from a relationship slice
 func (g *C4DiagramGenerator) deduplicateRelationshipSlice(relationships []C4Relation) []C4Relation {
 	seen := make(map[string]C4Relation)
+	order := make([]string, 0, len(relationships))
 	
 	for _, relation := range relationships {
 		// Create a unique key based on From->To pair
@@ -474,18 +482,27 @@ func (g *C4DiagramGenerator) deduplicateRelationshipSlice(relationships []C4Rela
 		// If we haven't seen this relationship before, or if the current one has more detail, keep it
 		if existing, exists := seen[key]; !exists {
 			seen[key] = relation
+			order = append(order, key)
 		} else {
 			// If the new relation has a more detailed description, prefer it
 			if len(relation.Description) > len(existing.Description) {
 				seen[key] = relation
 			}
+			// Keep the highlight of whichever duplicate carried one
+			if merged := seen[key]; merged.Tag == "" {
+				merged.Tag = existing.Tag
+				if merged.Tag == "" {
+					merged.Tag = relation.Tag
+				}
+				seen[key] = merged
+			}
 		}
 	}
 	
-	// Convert map back to slice
+	// Convert map back to slice, in the order the relationships were first created
 	result := make([]C4Relation, 0, len(seen))
-	for _, relation := range seen {
-		result = append(result, relation)
+	for _, key := range order {
+		result = append(result, seen[key])
 	}
 	
 	return result
@@ -530,12 +547,7 @@ func (g *C4DiagramGenerator) containsString(slice []string, item string) bool {
 }
 
 func (g *C4DiagramGenerator) findServiceForDomain(domain string) string {
-	for _, service := range g.model.Services {
-		if slices.Contains(service.Domains, domain) {
-			return service.Name
-		}
-	}
-	return ""
+	return g.graph.ServiceOf(domain)
 }
 
 // analyzeDirectlyAccessibleDomains identifies domains that should be directly accessible via gateway
@@ -580,3 +592,9 @@ func GenerateC4ComponentDiagram(model *parser.DSLModel, mode C4GenerationMode, s
 	generator := NewC4DiagramGenerator(mode, showDatabases)
 	return generator.GenerateC4Diagram(model, C4Components)
 }
+
+func GenerateC4DeploymentDiagram(model *parser.DSLModel, architecture string) string {
+	generator := NewC4DiagramGenerator(C4ModeTransparent, false)
+	generator.SetArchitecture(architecture)
+	return generator.GenerateC4Diagram(model, C4Deployment)
+}
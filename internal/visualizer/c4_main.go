@@ -38,3 +38,33 @@ func (v *Visualizer) GenerateC4WithFocusSubDomainsAndFormat(arch *parser.DSLMode
 	fmt.Println(diagram)
 	return generatePlantUMLWithFormat(diagram, format)
 }
+
+// GenerateC4WithHighlightsAndFormat renders the container diagram with tagged elements and relationships coloured
+func (v *Visualizer) GenerateC4WithHighlightsAndFormat(arch *parser.DSLModel, highlights *Highlights, boundariesMode C4GenerationMode, showDatabases bool, format SupportedFormat) ([]byte, string, error) {
+	generator := NewC4DiagramGenerator(boundariesMode, showDatabases)
+	generator.SetHighlights(highlights)
+	diagram := generator.GenerateC4Diagram(arch, C4Containers)
+
+	return generatePlantUMLWithFormat(diagram, format)
+}
+
+// GenerateC4WithFocusHighlightsAndFormat renders the container diagram focused on the given services,
+// with tagged elements and relationships coloured
+func (v *Visualizer) GenerateC4WithFocusHighlightsAndFormat(arch *parser.DSLModel, focusedServiceNames []string, highlights *Highlights, boundariesMode C4GenerationMode, showDatabases bool, format SupportedFormat) ([]byte, string, error) {
+	generator := NewC4DiagramGeneratorWithFocus(boundariesMode, focusedServiceNames, showDatabases)
+	generator.SetHighlights(highlights)
+	diagram := generator.GenerateC4Diagram(arch, C4Containers)
+
+	return generatePlantUMLWithFormat(diagram, format)
+}
+
+// GenerateC4DeploymentWithFormat renders the deployment diagram of the named arch block, or of the
+// first one when the name is empty
+func (v *Visualizer) GenerateC4DeploymentWithFormat(arch *parser.DSLModel, architecture string, format SupportedFormat) ([]byte, string, error) {
+	if architecture != "" && FindArchitecture(arch, architecture) == nil {
+		return nil, "", fmt.Errorf("unknown architecture %s", architecture)
+	}
+	diagram := GenerateC4DeploymentDiagram(arch, architecture)
+
+	return generatePlantUMLWithFormat(diagram, format)
+}
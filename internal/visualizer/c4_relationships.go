@@ -2,9 +2,11 @@ package visualizer
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/tcarcao/craft/internal/graph"
 	"github.com/tcarcao/craft/internal/parser"
 )
 
@@ -16,7 +18,7 @@ func (g *C4DiagramGenerator) createUserToPresentationRelations() {
 		return
 	}
 
-	for actor := range g.actors {
+	for _, actor := range g.getSortedActors() {
 		for _, containerName := range g.presentationSystem.Containers {
 			relation := C4Relation{
 				From:        actor,
@@ -36,29 +38,87 @@ func (g *C4DiagramGenerator) createPresentationToGatewayRelations() {
 		return
 	}
 
-	for _, presContainer := range g.presentationSystem.Containers {
-		for _, gwContainer := range g.gatewaySystem.Containers {
-			relation := C4Relation{
-				From:        presContainer,
-				To:          gwContainer,
-				Description: "API Requests",
-				Technology:  "HTTPS/REST",
-				Type:        "uses",
+	// Once exposures name gateways, clients reach the services only through the gateways of the
+	// exposures serving them
+	exposed := g.exposedGateways()
+
+	connected := make(map[[2]string]bool)
+	for _, arch := range g.model.Architectures {
+		for i, component := range arch.Presentation {
+			presContainer := g.generatePresentationContainerName(component, i)
+			serving := g.presentationGateways(component, exposed)
+			for _, gwContainer := range g.gatewaySystem.Containers {
+				key := [2]string{presContainer, gwContainer}
+				if connected[key] || (len(exposed) > 0 && !serving[gwContainer]) {
+					continue
+				}
+				connected[key] = true
+				relation := C4Relation{
+					From:        presContainer,
+					To:          gwContainer,
+					Description: "API Requests",
+					Technology:  "HTTPS/REST",
+					Type:        "uses",
+				}
+				g.relations = append(g.relations, relation)
 			}
-			g.relations = append(g.relations, relation)
 		}
 	}
 }
 
+// presentationGateways returns the gateway containers of the exposures serving a presentation
+// component: those whose to: lists the component, a component of its flow, or its [audience:...].
+// A component no exposure lists and without an audience serves everyone, so it reaches every
+// exposed gateway.
+func (g *C4DiagramGenerator) presentationGateways(component parser.Component, exposed map[string]bool) map[string]bool {
+	names := make([]string, 0)
+	audience := ""
+	for _, member := range append([]parser.Component{component}, component.Chain...) {
+		if member.Name != "" {
+			names = append(names, member.Name)
+		}
+		for _, modifier := range member.Modifiers {
+			if modifier.Key == "audience" {
+				audience = modifier.Value
+			}
+		}
+	}
+
+	gateways := make(map[string]bool)
+	listed := false
+	for _, exposure := range g.model.Exposures {
+		if !slices.ContainsFunc(exposure.To, func(to string) bool { return slices.Contains(names, to) || (audience != "" && to == audience) }) {
+			continue
+		}
+		listed = true
+		for _, gateway := range exposure.Through {
+			if gwContainer := g.gatewayContainerOf(gateway); gwContainer != "" {
+				gateways[gwContainer] = true
+			}
+		}
+	}
+	if !listed && audience == "" {
+		return exposed
+	}
+	return gateways
+}
+
+// createGatewayToServiceRelations routes gateways named by exposures to the domains they expose;
+// any other gateway is routed to the domains users reach
 func (g *C4DiagramGenerator) createGatewayToServiceRelations() {
 	if g.gatewaySystem == nil {
 		return
 	}
 
+	routed := g.createExposureRoutes()
+
 	for _, gwContainer := range g.gatewaySystem.Containers {
+		if routed[gwContainer] {
+			continue
+		}
 		if g.mode == C4ModeBoundaries {
 			// In boundaries mode, connect only to directly accessible domains
-			for domain := range g.userInteractionMap {
+			for _, domain := range g.getUserInteractionDomains() {
 				if domainContainer, exists := g.containers[domain]; exists && !g.isDatabaseContainer(domainContainer) {
 					relation := C4Relation{
 						From:        gwContainer,
@@ -94,6 +154,87 @@ func (g *C4DiagramGenerator) createGatewayToServiceRelations() {
 	}
 }
 
+// createExposureRoutes connects each gateway an exposure goes through to the containers of the
+// domains it exposes, and returns the gateway containers routed this way
+func (g *C4DiagramGenerator) createExposureRoutes() map[string]bool {
+	routed := make(map[string]bool)
+
+	for _, exposure := range g.model.Exposures {
+		for _, gateway := range exposure.Through {
+			gwContainer := g.gatewayContainerOf(gateway)
+			if gwContainer == "" {
+				continue
+			}
+			routed[gwContainer] = true
+
+			for _, domain := range g.exposedDomains(exposure) {
+				target := g.findDomainContainer(domain)
+				if target == "" {
+					continue
+				}
+				g.relations = append(g.relations, C4Relation{
+					From:        gwContainer,
+					To:          target,
+					Description: fmt.Sprintf("Routes %s requests", exposure.Name),
+					Technology:  "HTTP/gRPC",
+					Type:        "uses",
+				})
+			}
+		}
+	}
+
+	return routed
+}
+
+// exposedGateways returns the gateway containers some exposure goes through
+func (g *C4DiagramGenerator) exposedGateways() map[string]bool {
+	exposed := make(map[string]bool)
+	for _, exposure := range g.model.Exposures {
+		for _, gateway := range exposure.Through {
+			if gwContainer := g.gatewayContainerOf(gateway); gwContainer != "" {
+				exposed[gwContainer] = true
+			}
+		}
+	}
+	return exposed
+}
+
+// gatewayContainerOf returns the gateway container of a gateway component, which may be any
+// component of a gateway flow, or "" when no architecture declares it
+func (g *C4DiagramGenerator) gatewayContainerOf(gateway string) string {
+	if g.gatewaySystem == nil {
+		return ""
+	}
+
+	for _, arch := range g.model.Architectures {
+		for i, component := range arch.Gateway {
+			containerName := g.generateGatewayContainerName(component, i)
+			if component.Name == gateway {
+				return containerName
+			}
+			for _, chained := range component.Chain {
+				if chained.Name == gateway {
+					return containerName
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// exposedDomains returns the domains of an exposure, a parent domain standing for its subdomains
+func (g *C4DiagramGenerator) exposedDomains(exposure parser.Exposure) []string {
+	domains := make([]string, 0, len(exposure.Of))
+	for _, domain := range exposure.Of {
+		if subDomains := g.model.SubDomainsOf(domain); len(subDomains) > 0 {
+			domains = append(domains, subDomains...)
+		} else {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
 func (g *C4DiagramGenerator) analyzeServiceCapabilities() map[string]string {
 	capabilities := make(map[string]string)
 
@@ -136,13 +277,23 @@ func (g *C4DiagramGenerator) getServicesWithUserInteractions() []string {
 	return result
 }
 
+// getUserInteractionDomains returns the sorted domains users interact with
+func (g *C4DiagramGenerator) getUserInteractionDomains() []string {
+	domains := make([]string, 0, len(g.userInteractionMap))
+	for domain := range g.userInteractionMap {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
 // createDirectUserToServiceRelations creates direct relationships from users to services
 func (g *C4DiagramGenerator) createDirectUserToServiceRelations() {
-	for _, services := range g.userInteractionMap {
-		for _, serviceName := range services {
+	for _, domain := range g.getUserInteractionDomains() {
+		for _, serviceName := range g.userInteractionMap[domain] {
 			serviceContainers := g.getServiceContainers(serviceName)
 
-			for actor := range g.actors {
+			for _, actor := range g.getSortedActors() {
 				for _, serviceContainer := range serviceContainers {
 					// Skip database containers
 					if !g.isDatabaseContainer(g.containers[serviceContainer]) {
@@ -152,6 +303,7 @@ func (g *C4DiagramGenerator) createDirectUserToServiceRelations() {
 							Description: "Interacts directly",
 							Technology:  "Direct API",
 							Type:        "uses",
+							Tag:         g.actorRelationTag(actor, serviceContainer),
 						}
 						g.systemRelations = append(g.systemRelations, relation)
 					}
@@ -163,86 +315,155 @@ func (g *C4DiagramGenerator) createDirectUserToServiceRelations() {
 
 // createServiceRelationships creates relationships between services
 func (g *C4DiagramGenerator) createServiceRelationships() {
-	for _, useCase := range g.model.UseCases {
-		for _, scenario := range useCase.Scenarios {
-			for _, action := range scenario.Actions {
-				if action.Type == parser.ActionTypeSync {
-					g.handleSyncAction(action)
-				} else if action.Type == parser.ActionTypeReturn {
-					g.handleReturnAction(action)
-				}
-			}
+	for _, edge := range g.graph.Edges(graph.EdgeSync, graph.EdgeReturn) {
+		if edge.Kind == graph.EdgeSync {
+			g.handleSyncCall(edge)
+		} else {
+			g.handleReturn(edge)
 		}
 	}
 }
 
-// handleSyncAction processes synchronous actions between domains/services
-func (g *C4DiagramGenerator) handleSyncAction(action parser.Action) {
-	if action.Domain == "" || action.TargetDomain == "" {
+// handleSyncCall processes synchronous calls between domains/services
+func (g *C4DiagramGenerator) handleSyncCall(call graph.Edge) {
+	domain, targetDomain := call.From.Name(), call.To.Name()
+
+	// Handle "asks Database" pattern
+	if targetDomain == "Database" {
+		g.createDatabaseRelationship(domain, call.Label)
 		return
 	}
 
-	// Handle "asks Database" pattern
-	if action.TargetDomain == "Database" {
-		g.createDatabaseRelationship(action.Domain, action.Phrase)
+	if call.To.Kind() == graph.NodeExternal {
+		g.createExternalSystemRelationship(domain, targetDomain, call.Label)
 		return
 	}
 
-	fromService := g.findServiceForDomain(action.Domain)
-	toService := g.findServiceForDomain(action.TargetDomain)
+	fromService := g.findServiceForDomain(domain)
+	toService := g.findServiceForDomain(targetDomain)
 
 	// Only create relationships for domains that belong to services
 	if fromService != "" && toService != "" && fromService != toService {
-		fromContainer := g.findDomainContainer(action.Domain)
-		toContainer := g.findDomainContainer(action.TargetDomain)
+		fromContainer := g.findDomainContainer(domain)
+		toContainer := g.findDomainContainer(targetDomain)
 
 		if fromContainer != "" && toContainer != "" {
 			relation := C4Relation{
 				From:        fromContainer,
 				To:          toContainer,
-				Description: action.Phrase,
+				Description: call.Label,
 				Technology:  "Service API",
 				Type:        "uses",
+				Tag:         g.highlights.RelationTag(domain, targetDomain),
 			}
 			g.relations = append(g.relations, relation)
 		}
 	}
 }
 
-// handleReturnAction processes return actions between domains/services
-func (g *C4DiagramGenerator) handleReturnAction(action parser.Action) {
-	if action.Domain == "" {
+// createExternalSystemRelationship connects the container of a domain, and its service at system
+// level, to an external system it calls
+func (g *C4DiagramGenerator) createExternalSystemRelationship(domain, name, phrase string) {
+	service := g.findServiceForDomain(domain)
+	fromContainer := g.findDomainContainer(domain)
+	if service == "" || fromContainer == "" {
+		return
+	}
+
+	technology := "External API"
+	if system := g.model.ExternalSystem(name); system != nil && system.Protocol != "" {
+		technology = strings.ToUpper(system.Protocol)
+	}
+
+	g.externalSystems[name] = appendUnique(g.externalSystems[name], service)
+	g.relations = append(g.relations, C4Relation{
+		From:        fromContainer,
+		To:          externalSystemAlias(name),
+		Description: phrase,
+		Technology:  technology,
+		Type:        "uses",
+		Tag:         g.highlights.RelationTag(domain, name),
+	})
+}
+
+// writeExternalSystems declares the external systems the drawn services call
+func (g *C4DiagramGenerator) writeExternalSystems(sb *strings.Builder) {
+	names := g.getCalledExternalSystems()
+	if len(names) == 0 {
 		return
 	}
 
-	fromService := g.findServiceForDomain(action.Domain)
+	for _, name := range names {
+		description := "External system"
+		if system := g.model.ExternalSystem(name); system != nil {
+			details := make([]string, 0, 2)
+			if system.Protocol != "" {
+				details = append(details, system.Protocol)
+			}
+			metadata := system.Metadata
+			if system.Owner != "" {
+				metadata = parser.Metadata{parser.MetadataOwner: {system.Owner}}.Inherit(metadata)
+			}
+			description = describe(description, metadata, details...)
+		}
+		sb.WriteString(fmt.Sprintf("System_Ext(%s, \"%s\", \"%s\")\n", g.sanitizeIdentifier(externalSystemAlias(name)), name, description))
+	}
+	sb.WriteString("\n")
+}
+
+// writeExternalSystemRelationships connects the services to the external systems they call
+func (g *C4DiagramGenerator) writeExternalSystemRelationships(sb *strings.Builder) {
+	for _, name := range g.getCalledExternalSystems() {
+		for _, service := range g.externalSystems[name] {
+			sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"Uses\")\n", g.sanitizeIdentifier(service), g.sanitizeIdentifier(externalSystemAlias(name))))
+		}
+	}
+}
+
+// getCalledExternalSystems returns the sorted external systems the services call
+func (g *C4DiagramGenerator) getCalledExternalSystems() []string {
+	names := make([]string, 0, len(g.externalSystems))
+	for name := range g.externalSystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// externalSystemAlias is the element name of an external system, kept apart from domain containers
+func externalSystemAlias(name string) string {
+	return "ext_" + name
+}
+
+// handleReturn processes returns to a specific domain in another service.
+// Returns to the external trigger have no edge and need no service relationship in C4 diagrams.
+func (g *C4DiagramGenerator) handleReturn(ret graph.Edge) {
+	domain, targetDomain := ret.From.Name(), ret.To.Name()
+
+	fromService := g.findServiceForDomain(domain)
 	if fromService == "" {
 		return
 	}
 
-	if action.TargetDomain != "" {
-		// Return to specific domain
-		toService := g.findServiceForDomain(action.TargetDomain)
-		if toService != "" && fromService != toService {
-			fromContainer := g.findDomainContainer(action.Domain)
-			toContainer := g.findDomainContainer(action.TargetDomain)
+	toService := g.findServiceForDomain(targetDomain)
+	if toService != "" && fromService != toService {
+		fromContainer := g.findDomainContainer(domain)
+		toContainer := g.findDomainContainer(targetDomain)
 
-			relation := C4Relation{
-				From:        fromContainer,
-				To:          toContainer,
-				Description: "returns " + action.Phrase,
-				Technology:  "Return Data",
-				Type:        "returns",
-			}
-			g.relations = append(g.relations, relation)
+		relation := C4Relation{
+			From:        fromContainer,
+			To:          toContainer,
+			Description: "returns " + ret.Label,
+			Technology:  "Return Data",
+			Type:        "returns",
 		}
+		g.relations = append(g.relations, relation)
 	}
-	// Note: If no target domain, it's a return to external trigger - 
-	// this doesn't need a service relationship in C4 diagrams
 }
 
 // createDatabaseRelationships creates relationships from services to databases
 func (g *C4DiagramGenerator) createDatabaseRelationships() {
+	accessed := g.createDataAccessRelationships()
 	if g.mode == C4ModeBoundaries {
 		// In boundaries mode, we rely on specific database relationships created 
 		// during service relationship analysis (createDatabaseRelationship calls)
@@ -251,12 +472,94 @@ func (g *C4DiagramGenerator) createDatabaseRelationships() {
 		return
 	} else {
 		// In transparent mode, connect all service containers to databases
-		g.createServiceLevelDatabaseRelationships()
+		g.createServiceLevelDatabaseRelationships(accessed)
+	}
+}
+
+// createDataAccessRelationships creates one relationship per container and data store pair from the
+// reads and writes of the use cases, listing what is read and written. It returns the pairs created.
+func (g *C4DiagramGenerator) createDataAccessRelationships() map[string]bool {
+	type access struct {
+		from, to      string
+		reads, writes []string
+	}
+	accesses := make(map[string]*access)
+	order := make([]string, 0)
+
+	for _, edge := range g.graph.Edges(graph.EdgeRead, graph.EdgeWrite) {
+		domain, dataStore := edge.From.Name(), edge.To.Name()
+		fromContainer := g.findDomainContainer(domain)
+		toContainer := g.findDataStoreContainer(domain, dataStore)
+		if fromContainer == "" || toContainer == "" {
+			continue
+		}
+
+		key := fromContainer + "->" + toContainer
+		if accesses[key] == nil {
+			accesses[key] = &access{from: fromContainer, to: toContainer}
+			order = append(order, key)
+		}
+		what := edge.Label
+		if what == "" {
+			what = dataStore
+		}
+		if edge.Kind == graph.EdgeRead {
+			accesses[key].reads = appendUnique(accesses[key].reads, what)
+		} else {
+			accesses[key].writes = appendUnique(accesses[key].writes, what)
+		}
+	}
+
+	created := make(map[string]bool)
+	for _, key := range order {
+		a := accesses[key]
+		parts := make([]string, 0, 2)
+		if len(a.reads) > 0 {
+			parts = append(parts, "Reads "+strings.Join(a.reads, ", "))
+		}
+		if len(a.writes) > 0 {
+			parts = append(parts, "Writes "+strings.Join(a.writes, ", "))
+		}
+		g.relations = append(g.relations, C4Relation{
+			From:        a.from,
+			To:          a.to,
+			Description: strings.Join(parts, "; "),
+			Technology:  "Database Query",
+			Type:        "uses",
+			Tag:         g.containerTag(a.to),
+		})
+		created[key] = true
+	}
+	return created
+}
+
+// findDataStoreContainer returns the container of a data store accessed by a domain: the one of the
+// domain's own service, or else of the first service listing the store
+func (g *C4DiagramGenerator) findDataStoreContainer(domain, dataStore string) string {
+	services := []string{g.findServiceForDomain(domain)}
+	for _, service := range g.model.Services {
+		services = append(services, service.Name)
+	}
+	for _, service := range services {
+		containerName := fmt.Sprintf("%s_%s", service, dataStore)
+		if service != "" && g.containers[containerName] != nil {
+			return containerName
+		}
 	}
+	return ""
+}
+
+// appendUnique appends value to values unless already present
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
 }
 
-// createServiceLevelDatabaseRelationships creates connections for transparent mode
-func (g *C4DiagramGenerator) createServiceLevelDatabaseRelationships() {
+// createServiceLevelDatabaseRelationships creates connections for transparent mode, except for the
+// pairs already connected by explicit data access
+func (g *C4DiagramGenerator) createServiceLevelDatabaseRelationships(accessed map[string]bool) {
 	for _, service := range g.model.Services {
 		serviceContainers := g.getServiceContainers(service.Name)
 		dbContainers := g.getDatabaseContainers(service.Name)
@@ -264,12 +567,16 @@ func (g *C4DiagramGenerator) createServiceLevelDatabaseRelationships() {
 		for _, serviceContainer := range serviceContainers {
 			if !g.isDatabaseContainer(g.containers[serviceContainer]) {
 				for _, dbContainer := range dbContainers {
+					if accessed[serviceContainer+"->"+dbContainer] {
+						continue
+					}
 					relation := C4Relation{
 						From:        serviceContainer,
 						To:          dbContainer,
 						Description: "Reads/Writes data",
 						Technology:  "Database Protocol",
 						Type:        "uses",
+						Tag:         g.containerTag(dbContainer),
 					}
 					g.relations = append(g.relations, relation)
 				}
@@ -299,6 +606,7 @@ func (g *C4DiagramGenerator) createDatabaseRelationship(domain, phrase string) {
 			Description: phrase,
 			Technology:  "Database Query",
 			Type:        "uses",
+			Tag:         g.containerTag(dbContainer),
 		}
 		g.relations = append(g.relations, relation)
 	}
@@ -312,46 +620,36 @@ func (g *C4DiagramGenerator) createEventRelationships() {
 	}
 
 	// 1. Create relationships from domains that publish events TO the event queue
-	for _, useCase := range g.model.UseCases {
-		for _, scenario := range useCase.Scenarios {
-			for _, action := range scenario.Actions {
-				if action.Type == parser.ActionTypeAsync && action.Domain != "" {
-					fromContainer := g.findDomainContainer(action.Domain)
-					if fromContainer != "" {
-						relation := C4Relation{
-							From:        fromContainer,
-							To:          eventQueue,
-							Description: action.Event,
-							Technology:  "Event Publishing",
-							Type:        "triggers",
-						}
-						g.relations = append(g.relations, relation)
-					}
-				}
+	for _, publish := range g.graph.Edges(graph.EdgePublish) {
+		domain, event := publish.From.Name(), publish.To.Name()
+		fromContainer := g.findDomainContainer(domain)
+		if fromContainer != "" {
+			relation := C4Relation{
+				From:        fromContainer,
+				To:          eventQueue,
+				Description: event,
+				Technology:  "Event Publishing",
+				Type:        "triggers",
+				Tag:         g.highlights.RelationTag(domain, event),
 			}
+			g.relations = append(g.relations, relation)
 		}
 	}
 
 	// 2. Create relationships FROM the event queue to domains that listen to events
-	for _, useCase := range g.model.UseCases {
-		for _, scenario := range useCase.Scenarios {
-			if scenario.Trigger.Type == parser.TriggerTypeDomainListen {
-				// Extract the listening domain from the trigger
-				listeningDomain := scenario.Trigger.Domain
-				if listeningDomain != "" {
-					toContainer := g.findDomainContainer(listeningDomain)
-					if toContainer != "" {
-						relation := C4Relation{
-							From:        eventQueue,
-							To:          toContainer,
-							Description: scenario.Trigger.Event,
-							Technology:  "Event Delivery",
-							Type:        "delivers",
-						}
-						g.relations = append(g.relations, relation)
-					}
-				}
+	for _, consume := range g.graph.Edges(graph.EdgeConsume) {
+		event, listeningDomain := consume.From.Name(), consume.To.Name()
+		toContainer := g.findDomainContainer(listeningDomain)
+		if toContainer != "" {
+			relation := C4Relation{
+				From:        eventQueue,
+				To:          toContainer,
+				Description: event,
+				Technology:  "Event Delivery",
+				Type:        "delivers",
+				Tag:         g.highlights.RelationTag(event, listeningDomain),
 			}
+			g.relations = append(g.relations, relation)
 		}
 	}
 }
@@ -402,6 +700,39 @@ func (g *C4DiagramGenerator) findDomainContainer(domain string) string {
 	return ""
 }
 
+// containerTag returns the highlight of a container: its datastore, its domain in boundaries mode, or its service
+func (g *C4DiagramGenerator) containerTag(containerName string) HighlightTag {
+	container := g.containers[containerName]
+	if container == nil {
+		return ""
+	}
+
+	if g.isDatabaseContainer(container) {
+		return g.highlights.Tag(HighlightDataStore, container.DataStores[0])
+	}
+	if g.mode == C4ModeBoundaries && len(container.Domains) == 1 {
+		if tag := g.highlights.Tag(HighlightDomain, container.Domains[0]); tag != "" {
+			return tag
+		}
+	}
+	return g.highlights.Tag(HighlightService, container.System)
+}
+
+// actorRelationTag returns the highlight of an actor reaching a container through any of its domains
+func (g *C4DiagramGenerator) actorRelationTag(actor, containerName string) HighlightTag {
+	container := g.containers[containerName]
+	if container == nil {
+		return ""
+	}
+
+	for _, domain := range container.Domains {
+		if tag := g.highlights.RelationTag(actor, domain); tag != "" {
+			return tag
+		}
+	}
+	return ""
+}
+
 // isDatabaseContainer checks if container is a database
 func (g *C4DiagramGenerator) isDatabaseContainer(container *C4Container) bool {
 	return len(container.DataStores) > 0
@@ -565,6 +896,71 @@ func (g *C4DiagramGenerator) getServiceTechnology(language string) string {
 	return fmt.Sprintf("%s Application", strings.Title(language))
 }
 
+// dataStoreTechnologies names the declared datastore types Craft knows; other types are shown as written
+var dataStoreTechnologies = map[string]string{
+	"postgres":      "PostgreSQL Database",
+	"postgresql":    "PostgreSQL Database",
+	"mysql":         "MySQL Database",
+	"mariadb":       "MariaDB Database",
+	"sqlite":        "SQLite Database",
+	"mongo":         "MongoDB Database",
+	"mongodb":       "MongoDB Database",
+	"redis":         "Redis Cache",
+	"memcached":     "Memcached Cache",
+	"dynamodb":      "DynamoDB Table",
+	"cassandra":     "Cassandra Database",
+	"elasticsearch": "Elasticsearch Index",
+	"s3":            "S3 Bucket",
+	"rabbitmq":      "RabbitMQ Broker",
+	"kafka":         "Kafka Topic",
+}
+
+// databaseTechnology returns the declared type and engine version of a datastore, or the
+// technology inferred from its name when the model does not declare one
+func (g *C4DiagramGenerator) databaseTechnology(dataStore string) string {
+	declared := g.model.DataStore(dataStore)
+	if declared == nil || declared.Type == "" {
+		return g.inferDatabaseType(dataStore)
+	}
+
+	technology, known := dataStoreTechnologies[strings.ToLower(declared.Type)]
+	if !known {
+		technology = declared.Type
+	}
+	if declared.EngineVersion != "" {
+		technology += " " + declared.EngineVersion
+	}
+	return technology
+}
+
+// dataStoreDescription describes a datastore container, with the metadata of its declaration
+func (g *C4DiagramGenerator) dataStoreDescription(dataStore string) string {
+	description := fmt.Sprintf("Stores %s data", dataStore)
+	if declared := g.model.DataStore(dataStore); declared != nil {
+		return describe(description, declared.Metadata)
+	}
+	return description
+}
+
+// describe returns the @description of an element, or the fallback, followed by its owner and tags
+func describe(fallback string, metadata parser.Metadata, details ...string) string {
+	description := fallback
+	if metadata.Description() != "" {
+		description = metadata.Description()
+	}
+
+	if owner := metadata.Owner(); owner != "" {
+		details = append(details, "owned by "+owner)
+	}
+	if tags := metadata.Tags(); len(tags) > 0 {
+		details = append(details, "tags: "+strings.Join(tags, ", "))
+	}
+	if len(details) == 0 {
+		return description
+	}
+	return description + " (" + strings.Join(details, ", ") + ")"
+}
+
 // inferDatabaseType determines database technology from datastore name
 func (g *C4DiagramGenerator) inferDatabaseType(dataStore string) string {
 	lowerStore := strings.ToLower(dataStore)
@@ -597,13 +993,17 @@ func (g *C4DiagramGenerator) buildC4PlantUML(diagramType C4DiagramType) string {
 	case C4Context:
 		sb.WriteString("!include <C4/C4_Context.puml>\n")
 		g.addIconIncludes(&sb)
-		sb.WriteString("\n\nLAYOUT_WITH_LEGEND()\n\n")
+		sb.WriteString("\n")
+		g.highlights.writeC4TagDefinitions(&sb)
+		sb.WriteString("\nLAYOUT_WITH_LEGEND()\n\n")
 		sb.WriteString("title System Context Diagram - Architecture\n\n")
 		g.buildContextDiagram(&sb)
 	case C4Containers:
 		sb.WriteString("!include <C4/C4_Container.puml>\n")
 		g.addIconIncludes(&sb)
-		sb.WriteString("\n\nLAYOUT_WITH_LEGEND()\n\n")
+		sb.WriteString("\n")
+		g.highlights.writeC4TagDefinitions(&sb)
+		sb.WriteString("\nLAYOUT_WITH_LEGEND()\n\n")
 		sb.WriteString(fmt.Sprintf("title Container Diagram - Architecture (%s mode)\n\n", g.mode))
 		g.buildContainerDiagram(&sb)
 	case C4Components:
@@ -611,6 +1011,12 @@ func (g *C4DiagramGenerator) buildC4PlantUML(diagramType C4DiagramType) string {
 		sb.WriteString("LAYOUT_WITH_LEGEND()\n\n")
 		sb.WriteString("title Component Diagram - Architecture\n\n")
 		g.buildComponentDiagram(&sb)
+	case C4Deployment:
+		sb.WriteString("!include <C4/C4_Deployment.puml>\n")
+		g.addIconIncludes(&sb)
+		sb.WriteString("\nLAYOUT_WITH_LEGEND()\n\n")
+		sb.WriteString("title Deployment Diagram - Architecture\n\n")
+		g.buildDeploymentDiagram(&sb)
 	}
 
 	sb.WriteString("\n@enduml")
@@ -656,6 +1062,9 @@ func (g *C4DiagramGenerator) buildContextDiagram(sb *strings.Builder) {
 		sb.WriteString("\n")
 	}
 
+	// Add third party systems
+	g.writeExternalSystems(sb)
+
 	// Add external systems
 	externalSystems := g.getExternalSystems()
 	for _, systemName := range externalSystems {
@@ -672,18 +1081,21 @@ func (g *C4DiagramGenerator) buildContextDiagram(sb *strings.Builder) {
 	for _, systemName := range internalSystems {
 		system := g.systems[systemName]
 		icon := g.getSystemIcon(systemName)
-		sb.WriteString(fmt.Sprintf("System(%s, \"%s\", \"%s\"%s)\n",
-			g.sanitizeIdentifier(systemName), systemName, system.Description, icon))
+		sb.WriteString(fmt.Sprintf("System(%s, \"%s\", \"%s\"%s%s)\n",
+			g.sanitizeIdentifier(systemName), systemName, system.Description, icon,
+			c4TagsArg(g.highlights.Tag(HighlightService, systemName))))
 	}
 	sb.WriteString("\n")
 
 	// Add system-level relationships
 	for _, relation := range g.systemRelations {
-		sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\")\n",
+		sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\"%s)\n",
 			g.sanitizeIdentifier(relation.From),
 			g.sanitizeIdentifier(relation.To),
-			relation.Description))
+			relation.Description,
+			c4TagsArg(relation.Tag)))
 	}
+	g.writeExternalSystemRelationships(sb)
 }
 
 // buildContainerDiagram builds container diagram with proper system separation
@@ -700,6 +1112,9 @@ func (g *C4DiagramGenerator) buildContainerDiagram(sb *strings.Builder) {
 		sb.WriteString("\n")
 	}
 
+	// Add third party systems
+	g.writeExternalSystems(sb)
+
 	// Add external systems with their containers
 	externalSystems := g.getExternalSystems()
 	for _, systemName := range externalSystems {
@@ -731,8 +1146,9 @@ func (g *C4DiagramGenerator) buildSystemBoundary(sb *strings.Builder, systemName
 		boundaryType = "System_Boundary"      // Internal systems get system boundary
 	}
 
-	sb.WriteString(fmt.Sprintf("%s(%s_boundary, \"%s\") {\n",
-		boundaryType, g.sanitizeIdentifier(systemName), systemName))
+	sb.WriteString(fmt.Sprintf("%s(%s_boundary, \"%s\"%s) {\n",
+		boundaryType, g.sanitizeIdentifier(systemName), systemName,
+		c4TagsArg(g.highlights.Tag(HighlightService, systemName))))
 
 	if g.mode == C4ModeBoundaries && g.isServiceSystem(systemName) {
 		// For service systems in boundaries mode, group domains
@@ -745,14 +1161,16 @@ func (g *C4DiagramGenerator) buildSystemBoundary(sb *strings.Builder, systemName
 	sb.WriteString("}\n\n")
 }
 
-// buildDomainBoundaries creates Container_Boundary for each domain in boundaries mode
+// buildDomainBoundaries creates a Container_Boundary for each domain in boundaries mode. Subdomains
+// declared under a parent domain share the parent's boundary.
 func (g *C4DiagramGenerator) buildDomainBoundaries(sb *strings.Builder, serviceName string, isExternal bool) {
 	service := g.findService(serviceName)
 	if service == nil {
 		return
 	}
 
-	// Group containers by domain
+	// Group containers by domain, in declaration order
+	domains := make([]string, 0)
 	domainContainers := make(map[string][]string)
 	dbContainers := make([]string, 0)
 
@@ -766,7 +1184,11 @@ func (g *C4DiagramGenerator) buildDomainBoundaries(sb *strings.Builder, serviceN
 			dbContainers = append(dbContainers, containerName)
 		} else if len(container.Domains) > 0 {
 			domain := container.Domains[0] // Each domain container has one domain
+			if parent := g.model.ParentDomain(domain); parent != "" {
+				domain = parent
+			}
 			if domainContainers[domain] == nil {
+				domains = append(domains, domain)
 				domainContainers[domain] = make([]string, 0)
 			}
 			domainContainers[domain] = append(domainContainers[domain], containerName)
@@ -774,7 +1196,8 @@ func (g *C4DiagramGenerator) buildDomainBoundaries(sb *strings.Builder, serviceN
 	}
 
 	// Create boundaries for each domain
-	for domain, containers := range domainContainers {
+	for _, domain := range domains {
+		containers := domainContainers[domain]
 		sb.WriteString(fmt.Sprintf("    Container_Boundary(%s_%s_boundary, \"%s Domain\") {\n",
 			g.sanitizeIdentifier(serviceName), g.sanitizeIdentifier(domain), domain))
 
@@ -788,9 +1211,9 @@ func (g *C4DiagramGenerator) buildDomainBoundaries(sb *strings.Builder, serviceN
 				containerType = "Container_Ext"
 			}
 			
-			sb.WriteString(fmt.Sprintf("        %s(%s, \"%s\", \"%s\", \"%s\"%s)\n",
+			sb.WriteString(fmt.Sprintf("        %s(%s, \"%s\", \"%s\", \"%s\"%s%s)\n",
 				containerType, g.sanitizeIdentifier(containerName), containerName,
-				container.Technology, container.Description, icon))
+				container.Technology, container.Description, icon, c4TagsArg(g.containerTag(containerName))))
 		}
 
 		sb.WriteString("    }\n")
@@ -809,9 +1232,9 @@ func (g *C4DiagramGenerator) buildDomainBoundaries(sb *strings.Builder, serviceN
 				dbContainerType = "ContainerDb_Ext"
 			}
 			
-			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s)\n",
+			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s%s)\n",
 				dbContainerType, g.sanitizeIdentifier(containerName), containerName,
-				container.Technology, container.Description, icon))
+				container.Technology, container.Description, icon, c4TagsArg(g.containerTag(containerName))))
 		}
 	}
 }
@@ -832,9 +1255,9 @@ func (g *C4DiagramGenerator) buildStandardContainers(sb *strings.Builder, system
 			if isExternal {
 				dbContainerType = "ContainerDb_Ext"
 			}
-			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s)\n",
+			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s%s)\n",
 				dbContainerType, g.sanitizeIdentifier(containerName), containerName,
-				container.Technology, container.Description, icon))
+				container.Technology, container.Description, icon, c4TagsArg(g.containerTag(containerName))))
 		} else if containerName == "Event_Queue" {
 			queueContainerType := "ContainerQueue"
 			if isExternal {
@@ -849,9 +1272,9 @@ func (g *C4DiagramGenerator) buildStandardContainers(sb *strings.Builder, system
 			if isExternal {
 				containerType = "Container_Ext"
 			}
-			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s)\n",
+			sb.WriteString(fmt.Sprintf("    %s(%s, \"%s\", \"%s\", \"%s\"%s%s)\n",
 				containerType, g.sanitizeIdentifier(containerName), containerName,
-				container.Technology, container.Description, icon))
+				container.Technology, container.Description, icon, c4TagsArg(g.containerTag(containerName))))
 		}
 	}
 }
@@ -887,20 +1310,22 @@ func (g *C4DiagramGenerator) buildComponentDiagram(sb *strings.Builder) {
 func (g *C4DiagramGenerator) addAllRelationships(sb *strings.Builder) {
 	// System-level relationships
 	for _, relation := range g.systemRelations {
-		sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\")\n",
+		sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\"%s)\n",
 			g.sanitizeIdentifier(relation.From),
 			g.sanitizeIdentifier(relation.To),
-			relation.Description))
+			relation.Description,
+			c4TagsArg(relation.Tag)))
 	}
 
 	// Container-level relationships
 	for _, relation := range g.relations {
 		if relation.From != "" && relation.To != "" {
-			sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\", \"%s\")\n",
+			sb.WriteString(fmt.Sprintf("Rel(%s, %s, \"%s\", \"%s\"%s)\n",
 				g.sanitizeIdentifier(relation.From),
 				g.sanitizeIdentifier(relation.To),
 				relation.Description,
-				relation.Technology))
+				relation.Technology,
+				c4TagsArg(relation.Tag)))
 		}
 	}
 }
@@ -985,12 +1410,13 @@ func (g *C4DiagramGenerator) getMainService() string {
 		}
 	}
 
+	// Ties go to the first service in declaration order
 	maxCount := 0
 	mainService := ""
-	for service, count := range serviceCount {
-		if count > maxCount {
+	for _, service := range g.model.Services {
+		if count := serviceCount[service.Name]; count > maxCount {
 			maxCount = count
-			mainService = service
+			mainService = service.Name
 		}
 	}
 
@@ -1104,12 +1530,7 @@ func (g *C4DiagramGenerator) getDatabaseIcon(technology string) string {
 
 // getActorInfo finds actor information from the DSL model
 func (g *C4DiagramGenerator) getActorInfo(actorName string) *parser.Actor {
-	for _, actor := range g.model.Actors {
-		if actor.Name == actorName {
-			return &actor
-		}
-	}
-	return nil
+	return g.model.Actor(actorName)
 }
 
 // getActorC4Element returns the appropriate C4 element type and description for an actor
@@ -1119,17 +1540,20 @@ func (g *C4DiagramGenerator) getActorC4Element(actor *parser.Actor) (string, str
 		return "Person", "External user"
 	}
 
+	elementType, description := "Person", "External user"
 	switch actor.Type {
-	case parser.ActorTypeUser:
-		return "Person", "External user"
 	case parser.ActorTypeSystem:
-		return "System_Ext", "External system"
+		elementType, description = "System_Ext", "External system"
 	case parser.ActorTypeService:
-		return "System_Ext", "External service"
-	default:
-		// Default fallback
-		return "Person", "External user"
+		elementType, description = "System_Ext", "External service"
+	}
+
+	// A declared description replaces the generic text
+	metadata := actor.Metadata
+	if actor.Description != "" {
+		metadata = parser.Metadata{parser.MetadataDescription: {actor.Description}}.Inherit(metadata)
 	}
+	return elementType, describe(description, metadata)
 }
 
 // sanitizeIdentifier replaces special characters for PlantUML
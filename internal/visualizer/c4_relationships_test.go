@@ -0,0 +1,143 @@
+package visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestDatabaseTechnology(t *testing.T) {
+	generator := NewC4DiagramGenerator(C4ModeBoundaries, true)
+	model, err := parser.ParseDSLToModel(`datastores {
+  session_cache {
+    type: memcached
+  }
+  account_db {
+    type: Postgres
+    engine_version: 15
+  }
+  ledger_store {
+    type: cockroachdb
+  }
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	generator.model = model
+
+	cases := map[string]string{
+		"session_cache": "Memcached Cache",
+		"account_db":    "PostgreSQL Database 15",
+		"ledger_store":  "cockroachdb",
+		"user_pg":       "PostgreSQL Database", // undeclared, inferred from the name
+	}
+	for dataStore, expected := range cases {
+		if got := generator.databaseTechnology(dataStore); got != expected {
+			t.Errorf("databaseTechnology(%q) = %q, expected %q", dataStore, got, expected)
+		}
+	}
+}
+
+// exposureModel builds a model whose presentation layer starts with the given web app component
+func exposureModel(t *testing.T, webApp string) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(`actor user Customer
+actor user Admin
+
+services {
+  OrderService {
+    domains: Orders
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+exposure PublicAPI {
+  to: Customer
+  of: Orders
+  through: PublicGW
+}
+
+exposure AdminAPI {
+  to: Admin, AdminPortal
+  of: Billing
+  through: AdminGW
+}
+
+arch {
+  presentation:
+    ` + webApp + `
+    AdminPortal
+  gateway:
+    PublicGW
+    AdminGW
+}
+
+use_case "Shop" {
+  when Customer places order
+    Orders asks Billing to charge
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func assertRels(t *testing.T, diagram string, expected, unexpected []string) {
+	t.Helper()
+	for _, rel := range expected {
+		if !strings.Contains(diagram, rel) {
+			t.Errorf("Expected %q in:\n%s", rel, diagram)
+		}
+	}
+	for _, rel := range unexpected {
+		if strings.Contains(diagram, rel) {
+			t.Errorf("Unexpected %q in:\n%s", rel, diagram)
+		}
+	}
+}
+
+func TestPresentationConnectsThroughItsExposures(t *testing.T) {
+	webApp := "WebApp[audience:Customer]"
+	diagram := GenerateC4ContainerDiagram(exposureModel(t, webApp), C4ModeBoundaries, false)
+
+	// WebApp serves Customer, AdminPortal is listed by name in AdminAPI
+	assertRels(t, diagram,
+		[]string{`Rel(WebApp, PublicGW, "API Requests", "HTTPS/REST")`, `Rel(AdminPortal, AdminGW, "API Requests", "HTTPS/REST")`},
+		[]string{`Rel(WebApp, AdminGW`, `Rel(AdminPortal, PublicGW`},
+	)
+}
+
+func TestPresentationWithoutAudienceReachesEveryExposedGateway(t *testing.T) {
+	webApp := "WebApp"
+	diagram := GenerateC4ContainerDiagram(exposureModel(t, webApp), C4ModeBoundaries, false)
+
+	assertRels(t, diagram,
+		[]string{`Rel(WebApp, PublicGW, "API Requests", "HTTPS/REST")`, `Rel(WebApp, AdminGW, "API Requests", "HTTPS/REST")`},
+		[]string{`Rel(AdminPortal, PublicGW`},
+	)
+}
+
+func TestExposureRoutesFollowOf(t *testing.T) {
+	webApp := "WebApp"
+
+	diagram := GenerateC4ContainerDiagram(exposureModel(t, webApp), C4ModeBoundaries, false)
+	assertRels(t, diagram,
+		[]string{`Rel(PublicGW, Orders, "Routes PublicAPI requests", "HTTP/gRPC")`, `Rel(AdminGW, Billing, "Routes AdminAPI requests", "HTTP/gRPC")`},
+		[]string{`Rel(PublicGW, Billing`, `Rel(AdminGW, Orders`},
+	)
+
+	// transparent mode routes to the applications of the services owning the domains
+	diagram = GenerateC4ContainerDiagram(exposureModel(t, webApp), C4ModeTransparent, false)
+	assertRels(t, diagram,
+		[]string{
+			`Rel(PublicGW, OrderService_Application, "Routes PublicAPI requests", "HTTP/gRPC")`,
+			`Rel(AdminGW, BillingService_Application, "Routes AdminAPI requests", "HTTP/gRPC")`,
+		},
+		[]string{`Rel(PublicGW, BillingService_Application`, `Rel(AdminGW, OrderService_Application`},
+	)
+}
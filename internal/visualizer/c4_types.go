@@ -25,13 +25,15 @@ type C4Relation struct {
 	Description string
 	Technology  string
 	Type        string // "uses", "reads", "writes", "triggers"
+	Tag         HighlightTag
 }
 
 // C4DiagramType determines the level of C4 diagram to generate
 type C4DiagramType string
 
 const (
-	C4Context    C4DiagramType = "context"   // System Context level
-	C4Containers C4DiagramType = "container" // Container level
-	C4Components C4DiagramType = "component" // Component level (domains as components)
+	C4Context    C4DiagramType = "context"    // System Context level
+	C4Containers C4DiagramType = "container"  // Container level
+	C4Components C4DiagramType = "component"  // Component level (domains as components)
+	C4Deployment C4DiagramType = "deployment" // Deployment level (arch block as an environment)
 )
@@ -0,0 +1,90 @@
+package visualizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/contextmap"
+)
+
+// GenerateContextMapPlantUML converts a context map to PlantUML. Each bounded context lists its
+// subdomains; arrows point from the downstream context to the upstream one and carry the DDD
+// patterns of the context_map along with the interactions behind them. Relations no context_map
+// entry describes are drawn grey and dashed, so the map shows what still needs a decision.
+func GenerateContextMapPlantUML(m *contextmap.Map) string {
+	var sb strings.Builder
+
+	sb.WriteString("@startuml\n")
+	sb.WriteString("skinparam backgroundColor white\n")
+	sb.WriteString("skinparam handwritten false\n")
+	sb.WriteString("skinparam rectangle {\n")
+	sb.WriteString("  BackgroundColor #E1BEE7\n")
+	sb.WriteString("  BorderColor #9370DB\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("title Context Map\n\n")
+
+	aliases := make(map[string]string)
+	for _, context := range m.Contexts {
+		aliases[context.Name] = plantUMLAlias("ctx", context.Name)
+
+		label := fmt.Sprintf("<b>%s</b>", context.Name)
+		if len(context.SubDomains) > 0 {
+			subDomains := append([]string(nil), context.SubDomains...)
+			sort.Strings(subDomains)
+			label += "\\n\\n" + strings.Join(subDomains, "\\n")
+		}
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s\n", label, aliases[context.Name]))
+	}
+	sb.WriteString("\n")
+
+	for _, relation := range m.Relations {
+		from, fromOK := aliases[relation.From]
+		to, toOK := aliases[relation.To]
+		if !fromOK || !toOK {
+			continue
+		}
+
+		arrow := "-->"
+		switch {
+		case !relation.Declared:
+			arrow = "-[#9E9E9E,dashed]->"
+		case contextmap.AllSymmetric(relation.Patterns):
+			arrow = "<-->"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s %s : %s\n", from, arrow, to, contextRelationLabel(relation)))
+	}
+
+	if len(m.Unmapped) > 0 {
+		sb.WriteString(fmt.Sprintf("\nnote as unmapped\n  Domains outside any bounded context:\n  %s\nend note\n", strings.Join(m.Unmapped, ", ")))
+	}
+
+	sb.WriteString("@enduml")
+	return sb.String()
+}
+
+// contextRelationLabel lists the patterns of a relation above the interactions it was derived from
+func contextRelationLabel(relation contextmap.Relation) string {
+	lines := make([]string, 0)
+	if len(relation.Patterns) > 0 {
+		patterns := make([]string, 0, len(relation.Patterns))
+		for _, pattern := range relation.Patterns {
+			patterns = append(patterns, string(pattern))
+		}
+		lines = append(lines, fmt.Sprintf("<b>%s</b>", strings.Join(patterns, ", ")))
+	}
+
+	if relation.SyncCalls == 1 {
+		lines = append(lines, "1 sync call")
+	} else if relation.SyncCalls > 1 {
+		lines = append(lines, fmt.Sprintf("%d sync calls", relation.SyncCalls))
+	}
+	if len(relation.Events) > 0 {
+		lines = append(lines, "events: "+strings.Join(relation.Events, ", "))
+	}
+	if relation.SyncCalls == 0 && len(relation.Events) == 0 {
+		lines = append(lines, "<i>no interactions</i>")
+	}
+
+	return strings.Join(lines, "\\n")
+}
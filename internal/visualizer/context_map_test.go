@@ -0,0 +1,73 @@
+package visualizer
+
+import (
+	"testing"
+
+	"github.com/tcarcao/craft/internal/contextmap"
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestGenerateContextMapPlantUML(t *testing.T) {
+	model, err := parser.ParseDSLToModel(`domains {
+  Sales {
+    Orders
+    Cart
+  }
+  Billing {
+    Invoicing
+  }
+  Shipping {
+    Dispatch
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Invoicing to issue invoice
+    Orders asks Invoicing to load invoice
+    Orders asks Fraud to check order
+    Orders notifies "order placed"
+
+  when Dispatch listens "order placed"
+    Dispatch books courier
+}
+
+context_map {
+  Sales -> Shipping : shared-kernel
+  Billing -> Shipping : ohs
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	// Sales -> Billing is missing from the context_map, and the shared kernel covers the
+	// event Shipping consumes from Sales even though it is declared the other way round
+	expected := `@startuml
+skinparam backgroundColor white
+skinparam handwritten false
+skinparam rectangle {
+  BackgroundColor #E1BEE7
+  BorderColor #9370DB
+}
+
+title Context Map
+
+rectangle "<b>Sales</b>\n\nCart\nOrders" as ctx_sales
+rectangle "<b>Billing</b>\n\nInvoicing" as ctx_billing
+rectangle "<b>Shipping</b>\n\nDispatch" as ctx_shipping
+
+ctx_billing --> ctx_shipping : <b>open-host-service</b>\n<i>no interactions</i>
+ctx_sales -[#9E9E9E,dashed]-> ctx_billing : 2 sync calls
+ctx_shipping <--> ctx_sales : <b>shared-kernel</b>\nevents: order placed
+
+note as unmapped
+  Domains outside any bounded context:
+  Fraud
+end note
+@enduml`
+
+	if diagram := GenerateContextMapPlantUML(contextmap.Build(model)); diagram != expected {
+		t.Errorf("Unexpected context map diagram.\nExpected:\n%s\nGot:\n%s", expected, diagram)
+	}
+}
@@ -2,9 +2,12 @@ package visualizer
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/tcarcao/craft/internal/contextmap"
+	"github.com/tcarcao/craft/internal/graph"
 	"github.com/tcarcao/craft/internal/parser"
 )
 
@@ -14,6 +17,8 @@ type DomainMode string
 const (
 	DomainModeDetailed     DomainMode = "detailed"
 	DomainModeArchitecture DomainMode = "architecture"
+	DomainModeContextMap   DomainMode = "context_map" // Bounded contexts and the DDD patterns between them
+	DomainModeExposure     DomainMode = "exposure"    // Paths from each audience through its gateways to the exposed domains
 )
 
 func (v *Visualizer) GenerateDomainDiagram(model *parser.DSLModel) ([]byte, error) {
@@ -32,6 +37,10 @@ func (v *Visualizer) GenerateDomainDiagramWithModeAndFormat(model *parser.DSLMod
 	case DomainModeArchitecture:
 		generator := NewPlantUMLArchitectureGenerator()
 		diagramTxt = generator.GenerateArchitecturePlantUML(model)
+	case DomainModeContextMap:
+		diagramTxt = GenerateContextMapPlantUML(contextmap.Build(model))
+	case DomainModeExposure:
+		diagramTxt = GenerateExposurePlantUML(model)
 	case DomainModeDetailed:
 		generator := NewPlantUMLGenerator()
 		diagramTxt = generator.GeneratePlantUML(model)
@@ -44,16 +53,25 @@ func (v *Visualizer) GenerateDomainDiagramWithModeAndFormat(model *parser.DSLMod
 	return generatePlantUMLWithFormat(diagramTxt, format)
 }
 
+// GenerateDomainDiagramWithHighlightsAndFormat renders the architecture domain view with tagged elements and connections coloured
+func (v *Visualizer) GenerateDomainDiagramWithHighlightsAndFormat(model *parser.DSLModel, highlights *Highlights, format SupportedFormat) ([]byte, string, error) {
+	generator := NewPlantUMLArchitectureGenerator()
+	generator.SetHighlights(highlights)
+	diagramTxt := generator.GenerateArchitecturePlantUML(model)
+
+	return generatePlantUMLWithFormat(diagramTxt, format)
+}
+
 // PlantUMLGenerator generates PlantUML diagrams from DSL models
 type PlantUMLGenerator struct {
-	model           *parser.DSLModel // Reference to the model for actor information
-	domains         map[string]bool
-	actors          map[string]bool
-	events          map[string]bool
-	eventPublishers map[string]string // event -> domain that publishes it
-	domainAliases   map[string]string // domain -> unique alias
-	flows           []FlowStep
-	stepCounter     int
+	model         *parser.DSLModel // Reference to the model for actor information
+	domains       map[string]bool
+	actors        map[string]bool
+	events        map[string]bool
+	graph         *graph.Graph      // Dependency graph of the model
+	domainAliases map[string]string // domain -> unique alias
+	flows         []FlowStep
+	stepCounter   int
 }
 
 // FlowStep represents a single step in the domain flow
@@ -69,14 +87,14 @@ type FlowStep struct {
 
 // PlantUMLArchitectureGenerator generates simplified PlantUML architecture diagrams from DSL models
 type PlantUMLArchitectureGenerator struct {
-	subDomains      map[string]bool
-	connections     map[string]bool // key: "from->to" to avoid duplicates
-	events          map[string]bool
-	eventPublishers map[string]string // event -> domain that publishes it
-	services        map[string]bool   // services that contain subdomains
-	domainToService map[string]string // subdomain -> service mapping
-	domainAliases   map[string]string
-	serviceAliases  map[string]string
+	model          *parser.DSLModel // Reference to the model for the domain hierarchy
+	subDomains     map[string]bool
+	connections    map[string]bool // key: "from->to" to avoid duplicates
+	graph          *graph.Graph    // Dependency graph of the model
+	domainAliases  map[string]string
+	serviceAliases map[string]string
+	connectionTags map[string]HighlightTag // key: "from->to", highlighted connections only
+	highlights     *Highlights
 }
 
 // ArchitectureConnection represents a connection between subdomains
@@ -88,30 +106,33 @@ type ArchitectureConnection struct {
 // NewPlantUMLGenerator creates a new generator instance
 func NewPlantUMLGenerator() *PlantUMLGenerator {
 	return &PlantUMLGenerator{
-		domains:         make(map[string]bool),
-		actors:          make(map[string]bool),
-		events:          make(map[string]bool),
-		eventPublishers: make(map[string]string),
-		domainAliases:   make(map[string]string),
-		flows:           make([]FlowStep, 0),
-		stepCounter:     0,
+		domains:       make(map[string]bool),
+		actors:        make(map[string]bool),
+		events:        make(map[string]bool),
+		graph:         graph.New(),
+		domainAliases: make(map[string]string),
+		flows:         make([]FlowStep, 0),
+		stepCounter:   0,
 	}
 }
 
 // NewPlantUMLArchitectureGenerator creates a new architecture generator instance
 func NewPlantUMLArchitectureGenerator() *PlantUMLArchitectureGenerator {
 	return &PlantUMLArchitectureGenerator{
-		subDomains:      make(map[string]bool),
-		connections:     make(map[string]bool),
-		events:          make(map[string]bool),
-		eventPublishers: make(map[string]string),
-		services:        make(map[string]bool),
-		domainToService: make(map[string]string),
-		domainAliases:   make(map[string]string),
-		serviceAliases:  make(map[string]string),
+		subDomains:     make(map[string]bool),
+		connections:    make(map[string]bool),
+		graph:          graph.New(),
+		domainAliases:  make(map[string]string),
+		serviceAliases: make(map[string]string),
+		connectionTags: make(map[string]HighlightTag),
 	}
 }
 
+// SetHighlights colours the given services, subdomains and connections in generated diagrams
+func (g *PlantUMLArchitectureGenerator) SetHighlights(highlights *Highlights) {
+	g.highlights = highlights
+}
+
 // GeneratePlantUML converts a DSL model to PlantUML code
 func (g *PlantUMLGenerator) GeneratePlantUML(model *parser.DSLModel) string {
 	// Reset state
@@ -119,17 +140,12 @@ func (g *PlantUMLGenerator) GeneratePlantUML(model *parser.DSLModel) string {
 	g.domains = make(map[string]bool)
 	g.actors = make(map[string]bool)
 	g.events = make(map[string]bool)
-	g.eventPublishers = make(map[string]string)
+	g.graph = graph.Build(model)
 	g.domainAliases = make(map[string]string)
 	g.flows = make([]FlowStep, 0)
 	g.stepCounter = 0
 
-	// First pass: collect all event publishers
-	for _, useCase := range model.UseCases {
-		g.collectEventPublishers(useCase)
-	}
-
-	// Second pass: process all use cases to extract domains, actors, and flows
+	// Process all use cases to extract domains, actors, and flows
 	for _, useCase := range model.UseCases {
 		g.processUseCase(useCase)
 	}
@@ -144,27 +160,16 @@ func (g *PlantUMLGenerator) GeneratePlantUML(model *parser.DSLModel) string {
 // GenerateArchitecturePlantUML converts a DSL model to simplified architecture PlantUML code
 func (g *PlantUMLArchitectureGenerator) GenerateArchitecturePlantUML(model *parser.DSLModel) string {
 	// Reset state
+	g.model = model
 	g.subDomains = make(map[string]bool)
 	g.connections = make(map[string]bool)
-	g.events = make(map[string]bool)
-	g.eventPublishers = make(map[string]string)
-	g.services = make(map[string]bool)
-	g.domainToService = make(map[string]string)
+	g.graph = graph.Build(model)
 	g.domainAliases = make(map[string]string)
 	g.serviceAliases = make(map[string]string)
+	g.connectionTags = make(map[string]HighlightTag)
 
-	// First pass: collect services and their domain mappings
-	g.collectServicesForArchitecture(model)
-
-	// Second pass: collect all event publishers
-	for _, useCase := range model.UseCases {
-		g.collectEventPublishersForArchitecture(useCase)
-	}
-
-	// Third pass: process all use cases to extract subdomain connections
-	for _, useCase := range model.UseCases {
-		g.processUseCaseForArchitecture(useCase)
-	}
+	// Extract the subdomains taking part in use cases and their connections
+	g.collectConnectionsForArchitecture()
 
 	// Generate unique aliases for all subdomains and services
 	g.generateUniqueAliasesForArchitecture()
@@ -173,17 +178,6 @@ func (g *PlantUMLArchitectureGenerator) GenerateArchitecturePlantUML(model *pars
 	return g.buildArchitecturePlantUMLContent()
 }
 
-// collectEventPublishers maps events to their publishing domains
-func (g *PlantUMLGenerator) collectEventPublishers(useCase parser.UseCase) {
-	for _, scenario := range useCase.Scenarios {
-		for _, action := range scenario.Actions {
-			if action.Type == parser.ActionTypeAsync && action.Domain != "" && action.Event != "" {
-				g.eventPublishers[action.Event] = action.Domain
-			}
-		}
-	}
-}
-
 // getDomainQueueName generates a consistent queue name for a domain
 func (g *PlantUMLGenerator) getDomainQueueName(domain string) string {
 	// Convert domain name to a queue identifier
@@ -192,14 +186,6 @@ func (g *PlantUMLGenerator) getDomainQueueName(domain string) string {
 	return queueName + "_queue"
 }
 
-// findEventPublisher returns the domain that publishes a given event
-func (g *PlantUMLGenerator) findEventPublisher(event string) string {
-	if publisher, exists := g.eventPublishers[event]; exists {
-		return publisher
-	}
-	return ""
-}
-
 // processUseCase extracts information from a single use case
 func (g *PlantUMLGenerator) processUseCase(useCase parser.UseCase) {
 	for _, scenario := range useCase.Scenarios {
@@ -268,9 +254,8 @@ func (g *PlantUMLGenerator) processTrigger(useCaseName string, scenario parser.S
 			g.events[trigger.Event] = true
 			g.stepCounter++
 
-			// Find which domain published this event
-			publishingDomain := g.findEventPublisher(trigger.Event)
-			if publishingDomain != "" {
+			// Deliver from the queue of every domain publishing this event
+			for _, publishingDomain := range g.graph.Publishers(trigger.Event) {
 				domainQueue := g.getDomainQueueName(publishingDomain)
 				g.flows = append(g.flows, FlowStep{
 					StepNumber:  g.stepCounter,
@@ -389,8 +374,8 @@ func (g *PlantUMLGenerator) processAction(useCaseName, scenarioID string, action
 				ScenarioID:  scenarioID,
 			})
 		}
-	case parser.ActionTypeInternal:
-		// Internal domain action - shown as self-loops
+	case parser.ActionTypeInternal, parser.ActionTypeDataAccess:
+		// Internal domain action and data access - shown as self-loops
 		if action.Domain != "" {
 			g.domains[action.Domain] = true
 			g.stepCounter++
@@ -438,6 +423,8 @@ func (g *PlantUMLGenerator) buildActionDescription(action parser.Action) string
 			return "returns " + phrase + " to " + action.TargetDomain
 		}
 		return "returns " + phrase
+	case parser.ActionTypeDataAccess:
+		return strings.TrimSpace(action.Verb + " " + action.Connector + " " + action.DataStore + " " + action.Phrase)
 	}
 	return ""
 }
@@ -446,7 +433,7 @@ func (g *PlantUMLGenerator) buildActionDescription(action parser.Action) string
 func (g *PlantUMLGenerator) generateUniqueAliases() {
 	usedAliases := make(map[string]bool)
 
-	for domain := range g.domains {
+	for _, domain := range g.getSortedDomains() {
 		baseAlias := g.createBaseAlias(domain)
 		finalAlias := baseAlias
 		counter := 1
@@ -541,16 +528,15 @@ func (g *PlantUMLGenerator) buildPlantUMLContent() string {
 	sb.WriteString("  BorderColor black\n")
 	sb.WriteString("}\n\n")
 
-	// Define domains as frames
+	// Define domains as frames, grouped under their parent domain
 	sb.WriteString("' Domains as frames\n")
-	domains := g.getSortedDomains()
-	for _, domain := range domains {
-		alias := g.domainAliases[domain]
+	domains, externalSystems := splitExternalSystems(g.model, g.getSortedDomains())
+	writeDomainFrames(&sb, g.model, domains, "", "domain", func(domain string) string {
 		// Format domain name for display (handle long names)
-		displayName := g.formatDomainName(domain)
-		sb.WriteString(fmt.Sprintf("frame \"%s\" as %s\n", displayName, alias))
-	}
+		return fmt.Sprintf("frame \"%s\" as %s", g.formatDomainName(domain), g.domainAliases[domain])
+	})
 	sb.WriteString("\n")
+	writeExternalSystemClouds(&sb, externalSystems, g.domainAliases)
 
 	// Define actors with proper types
 	if len(g.actors) > 0 {
@@ -565,15 +551,11 @@ func (g *PlantUMLGenerator) buildPlantUMLContent() string {
 	}
 
 	// Define event queues (domain-specific queues)
-	if len(g.eventPublishers) > 0 {
+	if publishers := publishingDomains(g.graph); len(publishers) > 0 {
 		sb.WriteString("' Domain queues\n")
-		domainQueues := make(map[string]bool)
-		for _, domain := range g.eventPublishers {
-			if !domainQueues[domain] {
-				queueName := g.getDomainQueueName(domain)
-				sb.WriteString(fmt.Sprintf("queue \"%s events\" as %s\n", domain, queueName))
-				domainQueues[domain] = true
-			}
+		for _, domain := range publishers {
+			queueName := g.getDomainQueueName(domain)
+			sb.WriteString(fmt.Sprintf("queue \"%s events\" as %s\n", domain, queueName))
 		}
 		sb.WriteString("\n")
 	}
@@ -646,14 +628,7 @@ func (g *PlantUMLGenerator) getElementAlias(element string) string {
 		return alias
 	}
 
-	// Check if it's a domain queue
-	for _, domain := range g.eventPublishers {
-		if g.getDomainQueueName(domain) == element {
-			return element
-		}
-	}
-
-	// Must be an actor or other element
+	// Domain queues and actors are referenced by name
 	return element
 }
 
@@ -664,109 +639,49 @@ func GenerateDomainFlowDiagram(model *parser.DSLModel) string {
 }
 
 // Architecture generator methods
-// collectServicesForArchitecture collects services and maps domains to services
-func (g *PlantUMLArchitectureGenerator) collectServicesForArchitecture(model *parser.DSLModel) {
-	for _, service := range model.Services {
-		g.services[service.Name] = true
-
-		// Map each domain in this service to the service
-		for _, domain := range service.Domains {
-			g.domainToService[domain] = service.Name
+// collectConnectionsForArchitecture extracts the subdomains of the use cases and the connections between them
+func (g *PlantUMLArchitectureGenerator) collectConnectionsForArchitecture() {
+	// External systems called in use cases are aliased like subdomains and drawn apart
+	for _, node := range append(g.graph.Nodes(graph.NodeDomain), g.graph.Nodes(graph.NodeExternal)...) {
+		if len(node.UseCases) > 0 {
+			g.subDomains[node.Name] = true
 		}
 	}
-}
 
-// collectEventPublishersForArchitecture maps events to their publishing domains
-func (g *PlantUMLArchitectureGenerator) collectEventPublishersForArchitecture(useCase parser.UseCase) {
-	for _, scenario := range useCase.Scenarios {
-		for _, action := range scenario.Actions {
-			if action.Type == parser.ActionTypeAsync && action.Domain != "" && action.Event != "" {
-				g.eventPublishers[action.Event] = action.Domain
-				g.events[action.Event] = true
-			}
-		}
-	}
-}
-
-// processUseCaseForArchitecture extracts subdomain connections from use cases
-func (g *PlantUMLArchitectureGenerator) processUseCaseForArchitecture(useCase parser.UseCase) {
-	for _, scenario := range useCase.Scenarios {
-		g.processScenarioForArchitecture(scenario)
-	}
-}
+	for _, edge := range g.graph.Edges() {
+		from, to := edge.From.Name(), edge.To.Name()
 
-// processScenarioForArchitecture extracts subdomain connections from a scenario
-func (g *PlantUMLArchitectureGenerator) processScenarioForArchitecture(scenario parser.Scenario) {
-	// Track all subdomains involved in actions
-	for _, action := range scenario.Actions {
-		switch action.Type {
-		case parser.ActionTypeSync:
+		switch edge.Kind {
+		case graph.EdgeSync:
 			// Synchronous call between subdomains
-			if action.Domain != "" && action.TargetDomain != "" {
-				g.subDomains[action.Domain] = true
-				g.subDomains[action.TargetDomain] = true
-
-				// Create connection key to avoid duplicates
-				connectionKey := action.Domain + "->" + action.TargetDomain
-				g.connections[connectionKey] = true
-			}
-		case parser.ActionTypeAsync:
+			connectionKey := from + "->" + to
+			g.connections[connectionKey] = true
+			g.tagConnection(connectionKey, g.highlights.RelationTag(from, to))
+		case graph.EdgePublish:
 			// Async events - domain publishes to its own queue
-			if action.Domain != "" && action.Event != "" {
-				g.subDomains[action.Domain] = true
-				g.events[action.Event] = true
-
-				// Create connection from domain to its queue
-				domainQueue := g.getDomainQueueNameForArchitecture(action.Domain)
-				connectionKey := action.Domain + "->" + domainQueue
-				g.connections[connectionKey] = true
-			}
-		case parser.ActionTypeInternal:
-			// Internal subdomain action - self-connection
-			if action.Domain != "" {
-				g.subDomains[action.Domain] = true
-				// Create self-connection for internal actions
-				connectionKey := action.Domain + "->" + action.Domain
+			connectionKey := from + "->" + g.getDomainQueueNameForArchitecture(from)
+			g.connections[connectionKey] = true
+			g.tagConnection(connectionKey, g.highlights.RelationTag(from, to))
+		case graph.EdgeConsume:
+			// Domain listening to event - flow from each publishing domain's queue to the listening domain
+			for _, publishingDomain := range g.graph.Publishers(from) {
+				connectionKey := g.getDomainQueueNameForArchitecture(publishingDomain) + "->" + to
 				g.connections[connectionKey] = true
+				g.tagConnection(connectionKey, g.highlights.RelationTag(from, to))
 			}
-		case parser.ActionTypeReturn:
-			// Return action - data flowing back
-			if action.Domain != "" {
-				g.subDomains[action.Domain] = true
-
-				if action.TargetDomain != "" {
-					g.subDomains[action.TargetDomain] = true
-					// Create return connection
-					connectionKey := action.Domain + "->" + action.TargetDomain
-					g.connections[connectionKey] = true
-				}
-			}
+		case graph.EdgeInternal, graph.EdgeReturn:
+			// Internal actions are self-connections, returns flow data back to the target
+			g.connections[from+"->"+to] = true
 		}
 	}
+}
 
-	// Handle triggers that involve domains
-	trigger := scenario.Trigger
-	switch trigger.Type {
-	case parser.TriggerTypeDomainListen:
-		// Domain listening to event - create flow from publishing domain's queue to listening domain
-		if trigger.Domain != "" && trigger.Event != "" {
-			g.subDomains[trigger.Domain] = true
-			g.events[trigger.Event] = true
-
-			// Find which domain published this event
-			publishingDomain := g.findEventPublisherForArchitecture(trigger.Event)
-			if publishingDomain != "" {
-				domainQueue := g.getDomainQueueNameForArchitecture(publishingDomain)
-				connectionKey := domainQueue + "->" + trigger.Domain
-				g.connections[connectionKey] = true
-			}
-		}
-	case parser.TriggerTypeEvent:
-		// Event-based trigger
-		if trigger.Event != "" {
-			g.events[trigger.Event] = true
-		}
+// tagConnection records the highlight of a connection; the first highlight seen for a connection wins
+func (g *PlantUMLArchitectureGenerator) tagConnection(connectionKey string, tag HighlightTag) {
+	if tag == "" || g.connectionTags[connectionKey] != "" {
+		return
 	}
+	g.connectionTags[connectionKey] = tag
 }
 
 // getDomainQueueNameForArchitecture generates a consistent queue name for a domain
@@ -777,20 +692,13 @@ func (g *PlantUMLArchitectureGenerator) getDomainQueueNameForArchitecture(domain
 	return queueName + "_queue"
 }
 
-// findEventPublisherForArchitecture returns the domain that publishes a given event
-func (g *PlantUMLArchitectureGenerator) findEventPublisherForArchitecture(event string) string {
-	if publisher, exists := g.eventPublishers[event]; exists {
-		return publisher
-	}
-	return ""
-}
-
 // generateUniqueAliasesForArchitecture creates unique aliases for all subdomains and services
 func (g *PlantUMLArchitectureGenerator) generateUniqueAliasesForArchitecture() {
 	usedAliases := make(map[string]bool)
 
 	// Generate service aliases first
-	for service := range g.services {
+	for _, serviceNode := range g.graph.Nodes(graph.NodeService) {
+		service := serviceNode.Name
 		baseAlias := g.createBaseAliasForArchitecture(service)
 		finalAlias := baseAlias + "_svc"
 		counter := 1
@@ -806,7 +714,7 @@ func (g *PlantUMLArchitectureGenerator) generateUniqueAliasesForArchitecture() {
 	}
 
 	// Generate subdomain aliases
-	for subDomain := range g.subDomains {
+	for _, subDomain := range g.getSortedSubDomains() {
 		baseAlias := g.createBaseAliasForArchitecture(subDomain)
 		finalAlias := baseAlias
 		counter := 1
@@ -907,30 +815,28 @@ func (g *PlantUMLArchitectureGenerator) buildArchitecturePlantUMLContent() strin
 
 	// Define service boundaries and subdomains
 	g.defineServiceBoundaries(&sb)
+	_, externalSystems := splitExternalSystems(g.model, g.getSortedSubDomains())
+	writeExternalSystemClouds(&sb, externalSystems, g.domainAliases)
 
 	// Define event queues (domain-specific queues)
-	if len(g.eventPublishers) > 0 {
+	if publishers := publishingDomains(g.graph); len(publishers) > 0 {
 		sb.WriteString("' Domain queues\n")
-		domainQueues := make(map[string]bool)
-		for _, domain := range g.eventPublishers {
-			if !domainQueues[domain] {
-				queueName := g.getDomainQueueNameForArchitecture(domain)
-				sb.WriteString(fmt.Sprintf("queue \"%s events\" as %s\n", domain, queueName))
-				domainQueues[domain] = true
-			}
+		for _, domain := range publishers {
+			queueName := g.getDomainQueueNameForArchitecture(domain)
+			sb.WriteString(fmt.Sprintf("queue \"%s events\" as %s\n", domain, queueName))
 		}
 		sb.WriteString("\n")
 	}
 
 	// Generate connections (unlabeled and unduplicated)
 	sb.WriteString("' Subdomain connections\n")
-	for connectionKey := range g.connections {
+	for _, connectionKey := range g.getSortedConnections() {
 		parts := strings.Split(connectionKey, "->")
 		if len(parts) == 2 {
 			fromAlias := g.getElementAliasForArchitecture(parts[0])
 			toAlias := g.getElementAliasForArchitecture(parts[1])
 			if fromAlias != "" && toAlias != "" {
-				sb.WriteString(fmt.Sprintf("%s --> %s\n", fromAlias, toAlias))
+				sb.WriteString(fmt.Sprintf("%s %s %s\n", fromAlias, plantUMLArrow(g.connectionTags[connectionKey]), toAlias))
 			}
 		}
 	}
@@ -939,9 +845,10 @@ func (g *PlantUMLArchitectureGenerator) buildArchitecturePlantUMLContent() strin
 	return sb.String()
 }
 
-// defineServiceBoundaries creates service boundary rectangles containing subdomains
+// defineServiceBoundaries creates service boundary rectangles containing subdomains, nested in
+// packages for their parent domains
 func (g *PlantUMLArchitectureGenerator) defineServiceBoundaries(sb *strings.Builder) {
-	if len(g.services) == 0 {
+	if len(g.graph.Nodes(graph.NodeService)) == 0 && len(g.model.Domains) == 0 {
 		return
 	}
 
@@ -951,8 +858,11 @@ func (g *PlantUMLArchitectureGenerator) defineServiceBoundaries(sb *strings.Buil
 	serviceToSubDomains := make(map[string][]string)
 	ungroupedSubDomains := make([]string, 0)
 
-	for subDomain := range g.subDomains {
-		if service, exists := g.domainToService[subDomain]; exists {
+	for _, subDomain := range g.getSortedSubDomains() {
+		if g.model.ExternalSystem(subDomain) != nil {
+			continue
+		}
+		if service := g.graph.ServiceOf(subDomain); service != "" {
 			serviceToSubDomains[service] = append(serviceToSubDomains[service], subDomain)
 		} else {
 			ungroupedSubDomains = append(ungroupedSubDomains, subDomain)
@@ -960,19 +870,17 @@ func (g *PlantUMLArchitectureGenerator) defineServiceBoundaries(sb *strings.Buil
 	}
 
 	// Create service boundary rectangles
-	for service, subDomains := range serviceToSubDomains {
-		if len(subDomains) > 0 {
+	for _, serviceNode := range g.graph.Nodes(graph.NodeService) {
+		service := serviceNode.Name
+		if subDomains := serviceToSubDomains[service]; len(subDomains) > 0 {
 			serviceAlias := g.serviceAliases[service]
 			displayName := g.formatSubDomainName(service)
 
-			sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s {\n", displayName, serviceAlias))
+			sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s%s {\n", displayName, serviceAlias,
+				plantUMLElementColor(g.highlights.Tag(HighlightService, service))))
 
 			// Add subdomains inside the service boundary
-			for _, subDomain := range subDomains {
-				alias := g.domainAliases[subDomain]
-				subDomainDisplayName := g.formatSubDomainName(subDomain)
-				sb.WriteString(fmt.Sprintf("  frame \"%s\" as %s\n", subDomainDisplayName, alias))
-			}
+			writeDomainFrames(sb, g.model, subDomains, "  ", serviceAlias, g.subDomainFrame)
 
 			sb.WriteString("}\n")
 		}
@@ -981,16 +889,18 @@ func (g *PlantUMLArchitectureGenerator) defineServiceBoundaries(sb *strings.Buil
 	// Add ungrouped subdomains (not part of any service) outside service boundaries
 	if len(ungroupedSubDomains) > 0 {
 		sb.WriteString("\n' Ungrouped subdomains\n")
-		for _, subDomain := range ungroupedSubDomains {
-			alias := g.domainAliases[subDomain]
-			displayName := g.formatSubDomainName(subDomain)
-			sb.WriteString(fmt.Sprintf("frame \"%s\" as %s\n", displayName, alias))
-		}
+		writeDomainFrames(sb, g.model, ungroupedSubDomains, "", "ungrouped", g.subDomainFrame)
 	}
 
 	sb.WriteString("\n")
 }
 
+// subDomainFrame declares the frame of a subdomain in the architecture view
+func (g *PlantUMLArchitectureGenerator) subDomainFrame(subDomain string) string {
+	return fmt.Sprintf("frame \"%s\" as %s%s", g.formatSubDomainName(subDomain), g.domainAliases[subDomain],
+		plantUMLElementColor(g.highlights.Tag(HighlightDomain, subDomain)))
+}
+
 // Helper methods for architecture generator
 func (g *PlantUMLArchitectureGenerator) getSortedSubDomains() []string {
 	subDomains := make([]string, 0, len(g.subDomains))
@@ -1001,6 +911,15 @@ func (g *PlantUMLArchitectureGenerator) getSortedSubDomains() []string {
 	return subDomains
 }
 
+func (g *PlantUMLArchitectureGenerator) getSortedConnections() []string {
+	connections := make([]string, 0, len(g.connections))
+	for connection := range g.connections {
+		connections = append(connections, connection)
+	}
+	sort.Strings(connections)
+	return connections
+}
+
 func (g *PlantUMLArchitectureGenerator) formatSubDomainName(subDomain string) string {
 	// Simple formatting for architecture view
 	if len(subDomain) > 20 {
@@ -1021,15 +940,46 @@ func (g *PlantUMLArchitectureGenerator) getElementAliasForArchitecture(element s
 		return alias
 	}
 
-	// Check if it's a domain queue
-	for _, domain := range g.eventPublishers {
-		if g.getDomainQueueNameForArchitecture(domain) == element {
-			return element
+	// Domain queues and actors are referenced by name
+	return element
+}
+
+// splitExternalSystems separates the declared external systems from the domains
+func splitExternalSystems(model *parser.DSLModel, names []string) ([]string, []string) {
+	domains := make([]string, 0, len(names))
+	externalSystems := make([]string, 0)
+	for _, name := range names {
+		if model.ExternalSystem(name) != nil {
+			externalSystems = append(externalSystems, name)
+		} else {
+			domains = append(domains, name)
 		}
 	}
+	return domains, externalSystems
+}
 
-	// Must be an actor or other element
-	return element
+// writeExternalSystemClouds declares external systems as clouds, outside any service or domain
+func writeExternalSystemClouds(sb *strings.Builder, externalSystems []string, aliases map[string]string) {
+	if len(externalSystems) == 0 {
+		return
+	}
+	sb.WriteString("' External systems\n")
+	for _, name := range externalSystems {
+		sb.WriteString(fmt.Sprintf("cloud \"%s\" as %s\n", name, aliases[name]))
+	}
+	sb.WriteString("\n")
+}
+
+// publishingDomains returns the distinct domains publishing events, sorted
+func publishingDomains(modelGraph *graph.Graph) []string {
+	domains := make([]string, 0)
+	for _, publish := range modelGraph.Edges(graph.EdgePublish) {
+		if domain := publish.From.Name(); !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	sort.Strings(domains)
+	return domains
 }
 
 // getActorInfoFromModel finds actor information from the DSL model
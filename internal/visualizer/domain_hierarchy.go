@@ -0,0 +1,40 @@
+package visualizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// writeDomainFrames writes a frame per subdomain, nesting the subdomains of each parent domain
+// declared in the model in a package named after it. Scope prefixes the package aliases so the
+// same parent can appear in several enclosing boundaries.
+func writeDomainFrames(sb *strings.Builder, model *parser.DSLModel, subDomains []string, indent, scope string, frame func(subDomain string) string) {
+	sorted := append([]string(nil), subDomains...)
+	sort.Strings(sorted)
+
+	byParent := make(map[string][]string)
+	parents := make([]string, 0)
+	for _, subDomain := range sorted {
+		parent := model.ParentDomain(subDomain)
+		if parent == "" {
+			sb.WriteString(indent + frame(subDomain) + "\n")
+			continue
+		}
+		if byParent[parent] == nil {
+			parents = append(parents, parent)
+		}
+		byParent[parent] = append(byParent[parent], subDomain)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		sb.WriteString(fmt.Sprintf("%spackage \"%s\" as %s {\n", indent, parent, plantUMLAlias(scope, parent)))
+		for _, subDomain := range byParent[parent] {
+			sb.WriteString(indent + "  " + frame(subDomain) + "\n")
+		}
+		sb.WriteString(indent + "}\n")
+	}
+}
@@ -0,0 +1,87 @@
+package visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// salesModel declares Orders and Carts under the parent domain Sales, while Billing has no parent
+func salesModel(t *testing.T) *parser.DSLModel {
+	t.Helper()
+	model, err := parser.ParseDSLToModel(`domains {
+  Sales {
+    Orders
+    Carts
+  }
+}
+
+services {
+  ShopService {
+    domains: Orders, Carts
+  }
+  BillingService {
+    domains: Billing
+  }
+}
+
+use_case "Checkout" {
+  when Customer places order
+    Orders asks Carts to load cart
+    Orders asks Billing to charge card
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+	return model
+}
+
+func TestDomainFlow_NestsSubdomainsInTheirParent(t *testing.T) {
+	diagram := GenerateDomainFlowDiagram(salesModel(t))
+
+	assertRels(t, diagram, []string{`frame "Billing" as bill
+package "Sales" as domain_sales {
+  frame "Carts" as cart
+  frame "Orders" as orde
+}
+`}, nil)
+	if count := strings.Count(diagram, `frame "Orders"`); count != 1 {
+		t.Errorf("Expected Orders to be declared once, got %d times in:\n%s", count, diagram)
+	}
+}
+
+func TestArchitecture_NestsSubdomainsInTheirParent(t *testing.T) {
+	diagram := NewPlantUMLArchitectureGenerator().GenerateArchitecturePlantUML(salesModel(t))
+
+	assertRels(t, diagram, []string{
+		`rectangle "BillingService" as bill_svc {
+  frame "Billing" as bill
+}
+`,
+		`rectangle "ShopService" as shop_svc {
+  package "Sales" as shop_svc_sales {
+    frame "Carts" as cart
+    frame "Orders" as orde
+  }
+}
+`,
+	}, nil)
+}
+
+func TestC4Boundaries_SubdomainsShareTheirParentBoundary(t *testing.T) {
+	diagram := GenerateC4ContainerDiagram(salesModel(t), C4ModeBoundaries, true)
+
+	assertRels(t, diagram, []string{
+		`    Container_Boundary(BillingService_Billing_boundary, "Billing Domain") {
+        Container(Billing, "Billing", "Application", "Billing domain logic")
+    }
+`,
+		`    Container_Boundary(ShopService_Sales_boundary, "Sales Domain") {
+        Container(Orders, "Orders", "Application", "Orders domain logic")
+        Container(Carts, "Carts", "Application", "Carts domain logic")
+    }
+`,
+	}, []string{"ShopService_Orders_boundary", "ShopService_Carts_boundary"})
+}
@@ -0,0 +1,109 @@
+package visualizer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+// exposureGenerator writes the exposure view of a model. Arrows between the same elements are
+// drawn once, labelled with every exposure taking them.
+type exposureGenerator struct {
+	arrows []string            // "from->to" in order of first use
+	labels map[string][]string // "from->to" -> exposure names
+}
+
+// GenerateExposurePlantUML converts the exposures of a model to PlantUML. Each audience gets a lane
+// holding its clients and the gateways its exposures go through, in the order of their through:
+// list; the last gateway routes to the exposed domains, which all lanes share.
+func GenerateExposurePlantUML(model *parser.DSLModel) string {
+	g := &exposureGenerator{labels: make(map[string][]string)}
+
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("left to right direction\n")
+	sb.WriteString("skinparam backgroundColor white\n")
+	sb.WriteString("skinparam handwritten false\n")
+	sb.WriteString("skinparam frame {\n")
+	sb.WriteString("  BackgroundColor #E1BEE7\n")
+	sb.WriteString("  BorderColor #9370DB\n")
+	sb.WriteString("}\n")
+	sb.WriteString("skinparam node {\n")
+	sb.WriteString("  BackgroundColor #FFE4B5\n")
+	sb.WriteString("  BorderColor #666666\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("title Exposures\n\n")
+
+	audiences := make([]string, 0)
+	domains := make([]string, 0)
+	for _, exposure := range model.Exposures {
+		for _, audience := range exposure.To {
+			if !slices.Contains(audiences, audience) {
+				audiences = append(audiences, audience)
+			}
+		}
+		for _, domain := range exposure.Of {
+			if !slices.Contains(domains, domain) {
+				domains = append(domains, domain)
+			}
+		}
+	}
+
+	for _, audience := range audiences {
+		lane := plantUMLAlias("audience", audience)
+		client := lane + "_client"
+		sb.WriteString(fmt.Sprintf("rectangle \"%s\" as %s {\n", audience, lane))
+		sb.WriteString(fmt.Sprintf("  actor \"%s\" as %s\n", audience, client))
+
+		gateways := make([]string, 0)
+		for _, exposure := range model.Exposures {
+			if !slices.Contains(exposure.To, audience) {
+				continue
+			}
+
+			from := client
+			for _, gateway := range exposure.Through {
+				if !slices.Contains(gateways, gateway) {
+					gateways = append(gateways, gateway)
+				}
+				to := plantUMLAlias(lane, gateway)
+				g.arrow(from, to, exposure.Name)
+				from = to
+			}
+			for _, domain := range exposure.Of {
+				g.arrow(from, plantUMLAlias("domain", domain), exposure.Name)
+			}
+		}
+
+		for _, gateway := range gateways {
+			sb.WriteString(fmt.Sprintf("  node \"%s\" as %s\n", gateway, plantUMLAlias(lane, gateway)))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	writeDomainFrames(&sb, model, domains, "", "exposed", func(domain string) string {
+		return fmt.Sprintf("frame \"%s\" as %s", domain, plantUMLAlias("domain", domain))
+	})
+	sb.WriteString("\n")
+
+	for _, arrow := range g.arrows {
+		from, to, _ := strings.Cut(arrow, "->")
+		sb.WriteString(fmt.Sprintf("%s --> %s : %s\n", from, to, strings.Join(g.labels[arrow], ", ")))
+	}
+
+	sb.WriteString("@enduml")
+	return sb.String()
+}
+
+// arrow records that an exposure goes from one element to another
+func (g *exposureGenerator) arrow(from, to, exposure string) {
+	key := from + "->" + to
+	if _, exists := g.labels[key]; !exists {
+		g.arrows = append(g.arrows, key)
+	}
+	if !slices.Contains(g.labels[key], exposure) {
+		g.labels[key] = append(g.labels[key], exposure)
+	}
+}
@@ -0,0 +1,57 @@
+package visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestGenerateExposurePlantUML(t *testing.T) {
+	model, err := parser.ParseDSLToModel(`exposure PublicAPI {
+  to: Customer
+  of: Orders
+  through: LoadBalancer, PublicGW
+}
+
+exposure PartnerAPI {
+  to: Partner, Customer
+  of: Orders, Billing
+  through: PublicGW
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	expected := `title Exposures
+
+rectangle "Customer" as audience_customer {
+  actor "Customer" as audience_customer_client
+  node "LoadBalancer" as audience_customer_loadbalancer
+  node "PublicGW" as audience_customer_publicgw
+}
+
+rectangle "Partner" as audience_partner {
+  actor "Partner" as audience_partner_client
+  node "PublicGW" as audience_partner_publicgw
+}
+
+frame "Billing" as domain_billing
+frame "Orders" as domain_orders
+
+audience_customer_client --> audience_customer_loadbalancer : PublicAPI
+audience_customer_loadbalancer --> audience_customer_publicgw : PublicAPI
+audience_customer_publicgw --> domain_orders : PublicAPI, PartnerAPI
+audience_customer_client --> audience_customer_publicgw : PartnerAPI
+audience_customer_publicgw --> domain_billing : PartnerAPI
+audience_partner_client --> audience_partner_publicgw : PartnerAPI
+audience_partner_publicgw --> domain_orders : PartnerAPI
+audience_partner_publicgw --> domain_billing : PartnerAPI
+@enduml`
+
+	diagram := GenerateExposurePlantUML(model)
+	if !strings.HasSuffix(diagram, expected) {
+		t.Errorf("Unexpected exposure view.\nExpected to end with:\n%s\nGot:\n%s", expected, diagram)
+	}
+}
@@ -0,0 +1,200 @@
+package visualizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/rules"
+)
+
+// HighlightTag marks a diagram element or relationship with a review status
+type HighlightTag string
+
+const (
+	HighlightAdded     HighlightTag = "added"
+	HighlightRemoved   HighlightTag = "removed"
+	HighlightChanged   HighlightTag = "changed"
+	HighlightViolation HighlightTag = "violation"
+	HighlightImpacted  HighlightTag = "impacted"
+)
+
+// HighlightKind identifies the model element a highlight applies to
+type HighlightKind string
+
+const (
+	HighlightService   HighlightKind = "service"
+	HighlightDomain    HighlightKind = "domain"
+	HighlightDataStore HighlightKind = "datastore"
+	HighlightEvent     HighlightKind = "event"
+)
+
+// highlightStyle holds the colours used to render a tag
+type highlightStyle struct {
+	Background string
+	Border     string
+	Line       string
+	Legend     string
+}
+
+var highlightStyles = map[HighlightTag]highlightStyle{
+	HighlightAdded:     {Background: "#C8E6C9", Border: "#2E7D32", Line: "#2E7D32", Legend: "added"},
+	HighlightRemoved:   {Background: "#FFCDD2", Border: "#C62828", Line: "#C62828", Legend: "removed"},
+	HighlightChanged:   {Background: "#FFE0B2", Border: "#EF6C00", Line: "#EF6C00", Legend: "changed"},
+	HighlightViolation: {Background: "#FFCDD2", Border: "#D50000", Line: "#D50000", Legend: "rule violation"},
+	HighlightImpacted:  {Background: "#FFF9C4", Border: "#F9A825", Line: "#F9A825", Legend: "impacted"},
+}
+
+// Highlights collects the elements and relationships to colour in a diagram.
+// Relationships are keyed by model names ("Domain->Domain", "Domain->Event", "Event->Domain").
+// A nil *Highlights is valid and highlights nothing.
+type Highlights struct {
+	elements  map[HighlightKind]map[string]HighlightTag
+	relations map[string]HighlightTag
+}
+
+// NewHighlights creates an empty highlight set
+func NewHighlights() *Highlights {
+	return &Highlights{
+		elements:  make(map[HighlightKind]map[string]HighlightTag),
+		relations: make(map[string]HighlightTag),
+	}
+}
+
+// Mark highlights a single element
+func (h *Highlights) Mark(kind HighlightKind, name string, tag HighlightTag) {
+	if h.elements[kind] == nil {
+		h.elements[kind] = make(map[string]HighlightTag)
+	}
+	h.elements[kind][name] = tag
+}
+
+// MarkRelation highlights the relationship between two model elements
+func (h *Highlights) MarkRelation(from, to string, tag HighlightTag) {
+	h.relations[from+"->"+to] = tag
+}
+
+// Tag returns the highlight of an element, or "" when it is not highlighted
+func (h *Highlights) Tag(kind HighlightKind, name string) HighlightTag {
+	if h == nil {
+		return ""
+	}
+	return h.elements[kind][name]
+}
+
+// RelationTag returns the highlight of a relationship, or "" when it is not highlighted
+func (h *Highlights) RelationTag(from, to string) HighlightTag {
+	if h == nil {
+		return ""
+	}
+	return h.relations[from+"->"+to]
+}
+
+// withViolations returns a copy of the highlights with the edges of rule violations marked.
+// Violations take precedence over any other tag on the same relationship.
+func (h *Highlights) withViolations(violations []rules.Violation) *Highlights {
+	if len(violations) == 0 {
+		return h
+	}
+
+	merged := NewHighlights()
+	if h != nil {
+		for kind, names := range h.elements {
+			for name, tag := range names {
+				merged.Mark(kind, name, tag)
+			}
+		}
+		for key, tag := range h.relations {
+			merged.relations[key] = tag
+		}
+	}
+
+	for _, violation := range violations {
+		if violation.From != "" && violation.To != "" {
+			merged.MarkRelation(violation.From, violation.To, HighlightViolation)
+		}
+	}
+	return merged
+}
+
+// IsEmpty reports whether nothing is highlighted
+func (h *Highlights) IsEmpty() bool {
+	if h == nil {
+		return true
+	}
+	for _, names := range h.elements {
+		if len(names) > 0 {
+			return false
+		}
+	}
+	return len(h.relations) == 0
+}
+
+// usedTags returns the distinct tags in use, sorted for stable output
+func (h *Highlights) usedTags() []HighlightTag {
+	seen := make(map[HighlightTag]bool)
+	for _, names := range h.elements {
+		for _, tag := range names {
+			seen[tag] = true
+		}
+	}
+	for _, tag := range h.relations {
+		seen[tag] = true
+	}
+
+	tags := make([]HighlightTag, 0, len(seen))
+	for tag := range seen {
+		if _, known := highlightStyles[tag]; known {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	return tags
+}
+
+// writeC4TagDefinitions declares the C4-PlantUML element, boundary and relationship tags in use
+func (h *Highlights) writeC4TagDefinitions(sb *strings.Builder) {
+	if h.IsEmpty() {
+		return
+	}
+
+	for _, tag := range h.usedTags() {
+		style := highlightStyles[tag]
+		sb.WriteString(fmt.Sprintf("AddElementTag(\"%s\", $bgColor=\"%s\", $borderColor=\"%s\", $fontColor=\"#000000\", $legendText=\"%s\")\n",
+			tag, style.Background, style.Border, style.Legend))
+		sb.WriteString(fmt.Sprintf("AddBoundaryTag(\"%s\", $borderColor=\"%s\", $fontColor=\"%s\", $legendText=\"%s\")\n",
+			tag, style.Border, style.Border, style.Legend))
+		sb.WriteString(fmt.Sprintf("AddRelTag(\"%s\", $textColor=\"%s\", $lineColor=\"%s\", $lineStyle=BoldLine(), $legendText=\"%s\")\n",
+			tag, style.Line, style.Line, style.Legend))
+	}
+	sb.WriteString("\n")
+}
+
+// c4TagsArg returns the ", $tags=..." argument for a C4 macro, or "" when untagged
+func c4TagsArg(tag HighlightTag) string {
+	if tag == "" {
+		return ""
+	}
+	return fmt.Sprintf(", $tags=\"%s\"", tag)
+}
+
+// plantUMLElementColor returns the inline colour suffix for a plain PlantUML element
+func plantUMLElementColor(tag HighlightTag) string {
+	style, ok := highlightStyles[tag]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" %s;line:%s;line.bold", style.Background, strings.TrimPrefix(style.Border, "#"))
+}
+
+// plantUMLArrow returns a plain PlantUML arrow, coloured when the relationship is highlighted
+func plantUMLArrow(tag HighlightTag) string {
+	style, ok := highlightStyles[tag]
+	if !ok {
+		return "-->"
+	}
+	if tag == HighlightRemoved {
+		return fmt.Sprintf("-[%s,dashed,thickness=2]->", style.Line)
+	}
+	return fmt.Sprintf("-[%s,bold]->", style.Line)
+}
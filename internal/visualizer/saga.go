@@ -0,0 +1,108 @@
+package visualizer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/saga"
+)
+
+// GenerateSagaDiagramWithFormat renders a saga's forward and compensation paths side by side
+func (v *Visualizer) GenerateSagaDiagramWithFormat(s parser.Saga, format SupportedFormat) ([]byte, string, error) {
+	return generatePlantUMLWithFormat(GenerateSagaPlantUML(s), format)
+}
+
+// GenerateSagaPlantUML converts a saga to PlantUML. Forward steps run down the left column and each
+// compensation sits to the right of its step; a failing step leads to the compensation of the
+// closest earlier step, and compensations then run back up. Steps that change state without a
+// compensation are coloured as violations.
+func GenerateSagaPlantUML(s parser.Saga) string {
+	var sb strings.Builder
+
+	sb.WriteString("@startuml\n")
+	sb.WriteString("skinparam backgroundColor white\n")
+	sb.WriteString("skinparam handwritten false\n")
+	sb.WriteString("skinparam rectangle {\n")
+	sb.WriteString("  BackgroundColor #E1BEE7\n")
+	sb.WriteString("  BorderColor #9370DB\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString(fmt.Sprintf("title Saga: %s\n\n", s.Name))
+
+	violation := highlightStyles[HighlightViolation]
+
+	sb.WriteString("rectangle \"Forward\" as forward {\n")
+	for i, step := range s.Steps {
+		label := fmt.Sprintf("%d. %s", i+1, sagaActionLabel(step.Action))
+		style := ""
+		if step.Compensation == nil && saga.NeedsCompensation(step) {
+			label += "\\n<i>no compensation</i>"
+			style = fmt.Sprintf(" %s;line:%s", violation.Background, strings.TrimPrefix(violation.Border, "#"))
+		}
+		sb.WriteString(fmt.Sprintf("  rectangle \"%s\" as step%d%s\n", label, i+1, style))
+	}
+	sb.WriteString("}\n\n")
+
+	if hasCompensations(s) {
+		sb.WriteString("rectangle \"Compensation\" as compensation #FFF3E0 {\n")
+		for i, step := range s.Steps {
+			if step.Compensation != nil {
+				sb.WriteString(fmt.Sprintf("  rectangle \"%s\" as comp%d #FFE0B2\n", sagaActionLabel(*step.Compensation), i+1))
+			}
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	for i := 1; i < len(s.Steps); i++ {
+		sb.WriteString(fmt.Sprintf("step%d -down-> step%d\n", i, i+1))
+	}
+
+	for i, step := range s.Steps {
+		if step.Compensation != nil {
+			// Keep each compensation level with its step
+			sb.WriteString(fmt.Sprintf("step%d -[hidden]right-> comp%d\n", i+1, i+1))
+		}
+
+		compensations := saga.Compensations(s, i)
+		if len(compensations) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("step%d -[%s,dashed]-> comp%d : fails\n", i+1, violation.Line, compensations[0]+1))
+
+		if step.Compensation != nil {
+			sb.WriteString(fmt.Sprintf("comp%d -up-> comp%d\n", i+1, compensations[0]+1))
+		}
+	}
+
+	sb.WriteString("@enduml")
+	return sb.String()
+}
+
+func hasCompensations(s parser.Saga) bool {
+	for _, step := range s.Steps {
+		if step.Compensation != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sagaActionLabel puts the domain of an action on its own line above what it does
+func sagaActionLabel(action parser.Action) string {
+	label := strings.TrimSpace(strings.TrimPrefix(action.Description, action.Domain))
+	if label == "" {
+		return action.Domain
+	}
+	if len(action.Modifiers) > 0 {
+		modifiers := make([]string, 0, len(action.Modifiers))
+		for _, modifier := range action.Modifiers {
+			if modifier.Value != "" {
+				modifiers = append(modifiers, modifier.Key+":"+modifier.Value)
+			} else {
+				modifiers = append(modifiers, modifier.Key)
+			}
+		}
+		label += " [" + strings.Join(modifiers, ", ") + "]"
+	}
+	return fmt.Sprintf("%s\\n%s", action.Domain, label)
+}
@@ -0,0 +1,64 @@
+package visualizer
+
+import (
+	"testing"
+
+	"github.com/tcarcao/craft/internal/parser"
+)
+
+func TestGenerateSagaPlantUML(t *testing.T) {
+	model, err := parser.ParseDSLToModel(`saga "Money Transfer" {
+  step Limits asks Ledger to check limits [readonly]
+  step Balances reserves funds
+    compensate with Balances releases funds
+  step Payments debits account
+  step Ledger records transfer
+    compensate with Ledger reverses transfer
+  step Notification sends confirmation [retriable]
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse DSL: %v", err)
+	}
+
+	// A failing step leads to the closest earlier compensation, and compensations run back up:
+	// step 5 undoes step 4 then step 2, and step 3 has nothing of its own to undo
+	expected := `@startuml
+skinparam backgroundColor white
+skinparam handwritten false
+skinparam rectangle {
+  BackgroundColor #E1BEE7
+  BorderColor #9370DB
+}
+
+title Saga: Money Transfer
+
+rectangle "Forward" as forward {
+  rectangle "1. Limits\nasks Ledger to check limits [readonly]" as step1
+  rectangle "2. Balances\nreserves funds" as step2
+  rectangle "3. Payments\ndebits account\n<i>no compensation</i>" as step3 #FFCDD2;line:D50000
+  rectangle "4. Ledger\nrecords transfer" as step4
+  rectangle "5. Notification\nsends confirmation [retriable]" as step5
+}
+
+rectangle "Compensation" as compensation #FFF3E0 {
+  rectangle "Balances\nreleases funds" as comp2 #FFE0B2
+  rectangle "Ledger\nreverses transfer" as comp4 #FFE0B2
+}
+
+step1 -down-> step2
+step2 -down-> step3
+step3 -down-> step4
+step4 -down-> step5
+step2 -[hidden]right-> comp2
+step3 -[#D50000,dashed]-> comp2 : fails
+step4 -[hidden]right-> comp4
+step4 -[#D50000,dashed]-> comp2 : fails
+comp4 -up-> comp2
+step5 -[#D50000,dashed]-> comp4 : fails
+@enduml`
+
+	if diagram := GenerateSagaPlantUML(model.Sagas[0]); diagram != expected {
+		t.Errorf("Unexpected saga diagram.\nExpected:\n%s\nGot:\n%s", expected, diagram)
+	}
+}
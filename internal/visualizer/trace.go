@@ -0,0 +1,173 @@
+package visualizer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tcarcao/craft/internal/parser"
+	"github.com/tcarcao/craft/internal/trace"
+)
+
+// GenerateTraceDiagramWithFormat renders an event trace as a single sequence diagram
+func (v *Visualizer) GenerateTraceDiagramWithFormat(t *trace.Trace, format SupportedFormat) ([]byte, string, error) {
+	return generatePlantUMLWithFormat(GenerateTracePlantUML(t), format)
+}
+
+// traceGenerator writes the sequence diagram of a trace. Participants are declared in order of
+// first appearance so the diagram reads left to right along the flow.
+type traceGenerator struct {
+	participants []string
+	aliases      map[string]string // participant -> unique alias
+	actors       map[string]bool
+	external     map[string]bool // Participants that are external systems
+	body         strings.Builder
+}
+
+// GenerateTracePlantUML converts an event trace to a PlantUML sequence diagram. Each scenario
+// reached through an event is drawn as a group after the scenario publishing it.
+func GenerateTracePlantUML(t *trace.Trace) string {
+	g := &traceGenerator{aliases: make(map[string]string), actors: make(map[string]bool), external: make(map[string]bool)}
+	for _, system := range t.ExternalSystems {
+		g.external[system] = true
+	}
+	g.writeStep(t.Root)
+
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("skinparam backgroundColor white\n")
+	sb.WriteString("skinparam handwritten false\n")
+	sb.WriteString("skinparam sequenceMessageAlign center\n")
+	sb.WriteString("skinparam participant {\n")
+	sb.WriteString("  BackgroundColor #E1BEE7\n")
+	sb.WriteString("  BorderColor #9370DB\n")
+	sb.WriteString("  BackgroundColor<<external>> #E0E0E0\n")
+	sb.WriteString("  BorderColor<<external>> #757575\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString(fmt.Sprintf("title %s: %s\n\n", t.Root.UseCase, t.Root.Trigger.Description))
+
+	for _, participant := range g.participants {
+		element, stereotype := "participant", ""
+		if g.actors[participant] {
+			element = "actor"
+		} else if g.external[participant] {
+			stereotype = " <<external>>"
+		}
+		sb.WriteString(fmt.Sprintf("%s \"%s\" as %s%s\n", element, participant, g.aliases[participant], stereotype))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(g.body.String())
+
+	if len(t.Cycles) > 0 {
+		sb.WriteString("\n")
+		for _, cycle := range t.Cycles {
+			sb.WriteString(fmt.Sprintf("note across : event cycle: %s\n", strings.Join(cycle, " -> ")))
+		}
+	}
+
+	sb.WriteString("@enduml")
+	return sb.String()
+}
+
+// writeStep writes the actions of a scenario, then the scenarios listening to the events it publishes
+func (g *traceGenerator) writeStep(step *trace.Step) {
+	callStack := make([]string, 0)
+
+	// The root may be started by an actor; other steps are started by the event arrows drawn before them
+	if trigger := step.Trigger; step.Event == "" && trigger.Type == parser.TriggerTypeExternal && trigger.Actor != "" && step.Entry != "" {
+		g.actors[trigger.Actor] = true
+		callStack = append(callStack, trigger.Actor)
+		g.message(trigger.Actor, "->", step.Entry, joinWords(trigger.Verb, trigger.Phrase))
+	}
+
+	for _, action := range step.Actions {
+		if action.Domain == "" {
+			continue
+		}
+		switch action.Type {
+		case parser.ActionTypeSync:
+			if action.TargetDomain != "" {
+				callStack = append(callStack, action.Domain)
+				g.message(action.Domain, "->", action.TargetDomain, joinWords(action.Connector, action.Phrase))
+			}
+		case parser.ActionTypeReturn:
+			to := action.TargetDomain
+			if to == "" && len(callStack) > 0 {
+				to = callStack[len(callStack)-1]
+				callStack = callStack[:len(callStack)-1]
+			}
+			if to != "" {
+				g.message(action.Domain, "-->", to, joinWords(action.Connector, action.Phrase))
+			}
+		case parser.ActionTypeInternal:
+			g.message(action.Domain, "->", action.Domain, joinWords(action.Verb, action.Connector, action.Phrase))
+		case parser.ActionTypeDataAccess:
+			g.message(action.Domain, "->", action.Domain, joinWords(action.Verb, action.Connector, action.DataStore, action.Phrase))
+		case parser.ActionTypeAsync:
+			delivered := false
+			for _, child := range step.Children {
+				if child.Event == action.Event && child.Publisher == action.Domain && child.Entry != "" {
+					g.message(action.Domain, "->>", child.Entry, action.Event)
+					delivered = true
+				}
+			}
+			if !delivered {
+				g.body.WriteString(fmt.Sprintf("%s ->] : %s\n", g.alias(action.Domain), action.Event))
+			}
+		}
+	}
+
+	for _, child := range step.Children {
+		header := fmt.Sprintf("%s: %s", child.UseCase, child.Trigger.Description)
+		if child.Cycle {
+			if child.Entry != "" {
+				g.body.WriteString(fmt.Sprintf("note over %s : %s\\n%s already delivered, not followed\n", g.alias(child.Entry), header, child.Event))
+			}
+			continue
+		}
+		g.body.WriteString(fmt.Sprintf("group %s\n", header))
+		g.writeStep(child)
+		g.body.WriteString("end\n")
+	}
+}
+
+// message writes an arrow between two participants
+func (g *traceGenerator) message(from, arrow, to, description string) {
+	g.body.WriteString(fmt.Sprintf("%s %s %s : %s\n", g.alias(from), arrow, g.alias(to), description))
+}
+
+// alias returns the alias of a participant, declaring it on first use
+func (g *traceGenerator) alias(participant string) string {
+	if alias, exists := g.aliases[participant]; exists {
+		return alias
+	}
+
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, participant)
+
+	alias := base
+	for counter := 1; g.aliasUsed(alias); counter++ {
+		alias = fmt.Sprintf("%s%d", base, counter)
+	}
+
+	g.aliases[participant] = alias
+	g.participants = append(g.participants, participant)
+	return alias
+}
+
+func (g *traceGenerator) aliasUsed(alias string) bool {
+	for _, used := range g.aliases {
+		if used == alias {
+			return true
+		}
+	}
+	return false
+}
+
+func joinWords(words ...string) string {
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"unicode"
 )
 
 type Visualizer struct{}
@@ -131,3 +132,13 @@ func generateGraphvizBinary(content string, format string) ([]byte, string, erro
 	
 	return out, contentType, nil
 }
+
+// plantUMLAlias turns a model name into a PlantUML alias under the given prefix
+func plantUMLAlias(prefix, name string) string {
+	return prefix + "_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, name)
+}
@@ -1,35 +1,67 @@
 grammar Craft;
 
-dsl: NEWLINE* (arch | services_def | service_def | exposure | use_case | domain_def | domains_def | actors_def | actor_def)* ;
+dsl: NEWLINE* (arch | services_def | service_def | exposure | use_case | domain_def | domains_def | actors_def | actor_def | rules_def | saga | context_map | environment | datastores_def | external_system)* ;
+
+// Metadata annotations before an element, e.g. @owner("team-payments") @tag(pci, gdpr) @description("Moves money")
+annotations: (annotation NEWLINE*)+;
+
+annotation: '@' identifier ('(' annotation_value (',' annotation_value)* ')')?;
+
+annotation_value: identifier | STRING;
 
 // Domain hierarchy definitions
-domain_def: 'domain' domain_name '{' NEWLINE* subdomain_list '}' NEWLINE*;
+domain_def: annotations? 'domain' domain_name '{' NEWLINE* subdomain_list '}' NEWLINE*;
 
 domains_def: 'domains' '{' NEWLINE* domain_block_list '}' NEWLINE*;
 
 domain_block_list: domain_block (NEWLINE+ domain_block)* NEWLINE*;
 
-domain_block: domain_name '{' NEWLINE* subdomain_list '}';
+domain_block: annotations? domain_name '{' NEWLINE* subdomain_list '}';
 
 domain_name: identifier;
 
 subdomain_list: subdomain (NEWLINE+ subdomain)* NEWLINE*;
 
-subdomain: identifier;
+subdomain: annotations? identifier;
+
+// Context map: DDD patterns on the relationships between bounded contexts (top-level domains).
+// "A -> B" reads A depends on B: A is downstream, B upstream.
+context_map: 'context_map' '{' NEWLINE* context_relation_list? '}' NEWLINE*;
+
+context_relation_list: context_relation (NEWLINE+ context_relation)* NEWLINE*;
+
+context_relation: identifier '->' identifier (':' context_pattern (',' context_pattern)*)?;
+
+context_pattern: identifier;  // customer-supplier, conformist, acl, open-host-service, published-language, shared-kernel, partnership
 
 // Actor definitions - similar pattern to domains
-actor_def: 'actor' actorType actor_name NEWLINE*;
+actor_def: annotations? 'actor' actorType actor_name actor_body? NEWLINE*;
 
 actors_def: 'actors' '{' NEWLINE* actor_definition_list '}' NEWLINE*;
 
 actor_definition_list: actor_definition (NEWLINE+ actor_definition)* NEWLINE*;
 
-actor_definition: actorType actor_name;
+actor_definition: annotations? actorType actor_name actor_body?;
 
 actorType: 'user' | 'system' | 'service';
 
 actor_name: identifier;
 
+// Optional actor details, e.g. actor user Customer { description: "Shops online" }
+actor_body: '{' NEWLINE* actor_properties? '}';
+
+actor_properties: actor_property (NEWLINE+ actor_property)* NEWLINE*;
+
+actor_property: 'description' ':' STRING
+              | 'persona' '{' NEWLINE* persona_attributes? '}'               // free-form attributes, e.g. goal: "Pay quickly"
+              | 'uses' ':' identifier (',' identifier)*                      // exposures the actor goes through
+              | 'roles' ':' identifier (',' identifier)*
+              | 'permissions' ':' identifier (',' identifier)*;
+
+persona_attributes: persona_attribute (NEWLINE+ persona_attribute)* NEWLINE*;
+
+persona_attribute: identifier ':' (identifier | STRING);
+
 // Architecture blocks
 arch: 'arch' arch_name? '{' NEWLINE* arch_sections '}' NEWLINE*;
 
@@ -61,8 +93,13 @@ modifier: identifier (':' identifier)?;
 
 simple_component: component_with_modifiers;
 
+// Environments: arch blocks and service properties overriding the top-level ones for one deployment target
+environment: 'environment' environment_name '{' NEWLINE* (arch | service_def | services_def)* '}' NEWLINE*;
+
+environment_name: identifier;
+
 // Exposure blocks
-exposure: 'exposure' exposure_name '{' NEWLINE+ exposure_properties '}' NEWLINE*;
+exposure: annotations? 'exposure' exposure_name '{' NEWLINE+ exposure_properties '}' NEWLINE*;
 
 exposure_name: identifier;
 
@@ -81,14 +118,14 @@ gateway_list: gateway (',' gateway)* ','?;
 gateway: identifier;
 
 // Single service definition
-service_def: 'service' service_name '{' NEWLINE* service_properties '}' NEWLINE*;
+service_def: annotations? 'service' service_name '{' NEWLINE* service_properties '}' NEWLINE*;
 
 // Multiple services definition
 services_def: 'services' '{' NEWLINE* service_block_list? '}' NEWLINE*;
 
 service_block_list: service_block (NEWLINE+ service_block)* NEWLINE*;
 
-service_block: service_name '{' NEWLINE* service_properties '}' NEWLINE*;
+service_block: annotations? service_name '{' NEWLINE* service_properties '}' NEWLINE*;
 
 service_name: identifier | STRING;
 
@@ -112,14 +149,63 @@ deployment_target: identifier;
 
 domain_list: domain_ref (',' domain_ref)* ','?;
 
-domain_ref: identifier;
+domain_ref: identifier component_modifiers?;  // [p99:30ms] sets a service domain's default latency
 
 datastore_list: datastore (',' datastore)* ','?;
 
 datastore: identifier;
 
+// Data store definitions: technology and sharing of the stores services list in data-stores
+datastores_def: 'datastores' '{' NEWLINE* datastore_block_list? '}' NEWLINE*;
+
+datastore_block_list: datastore_block (NEWLINE+ datastore_block)* NEWLINE*;
+
+datastore_block: annotations? datastore_name '{' NEWLINE* datastore_properties '}';
+
+datastore_name: identifier;
+
+datastore_properties: datastore_property ((',' | NEWLINE+) datastore_property)* ','? NEWLINE*;
+
+datastore_property: 'type' ':' identifier                                        // postgres, mysql, redis, mongodb, ...
+                  | 'engine_version' ':' identifier
+                  | 'shared_by' ':' '[' service_name (',' service_name)* ']';  // services using the store besides its owner
+
+// External systems: third parties domains call with asks, e.g. external_system Stripe { protocol: https, owner: vendor }
+external_system: annotations? 'external_system' external_system_name '{' NEWLINE* external_system_properties? '}' NEWLINE*;
+
+external_system_name: identifier;
+
+external_system_properties: external_system_property ((',' | NEWLINE+) external_system_property)* ','? NEWLINE*;
+
+external_system_property: 'protocol' ':' identifier
+                        | 'owner' ':' identifier;
+
+// Architecture fitness rules
+rules_def: 'rules' '{' NEWLINE* rule_list? '}' NEWLINE*;
+
+rule_list: fitness_rule (NEWLINE+ fitness_rule)* NEWLINE*;
+
+fitness_rule: 'no_sync' 'from' rule_scope 'to' rule_scope              // no sync calls between the scopes
+            | 'calls' 'to' rule_scope 'only' 'through' exposure_name  // entry into the scope only through the exposure
+            | 'max_sync_hops' rule_limit;                            // sync calls allowed per scenario
+
+rule_scope: 'any' | identifier;
+
+rule_limit: identifier;
+
+// Sagas: ordered steps of a distributed transaction, each undone by its compensation when a later step fails
+saga: 'saga' string '{' NEWLINE* saga_step* '}' NEWLINE*;
+
+saga_step: 'step' saga_action NEWLINE+ compensation?;
+
+compensation: 'compensate' 'with' saga_action NEWLINE+;
+
+saga_action: sync_action                           // modifiers such as [readonly] are part of the sync action
+           | internal_action component_modifiers?;
+
 // Use case blocks
-use_case: 'use_case' string '{' NEWLINE* scenario* '}' NEWLINE*;
+// [slo:200ms] after the name sets the use case's latency budget
+use_case: annotations? 'use_case' string component_modifiers? '{' NEWLINE* scenario* '}' NEWLINE*;
 
 scenario: trigger action_block;
 
@@ -134,15 +220,21 @@ action_block: action*;
 action: async_action NEWLINE+
       | sync_action NEWLINE+
       | return_action NEWLINE+
+      | data_access_action NEWLINE+  // before internal_action, which would also match it
       | internal_action NEWLINE+;
 
-sync_action : domain 'asks' domain connector_word phrase
-            | domain 'asks' domain phrase;
+sync_action : domain 'asks' domain connector_word phrase component_modifiers?
+            | domain 'asks' domain phrase component_modifiers?;
 
-async_action: domain 'notifies' quoted_event;
+async_action: domain 'notifies' quoted_event annotation*;   // annotations describe the event
 
 internal_action: domain verb connector_word? phrase;
 
+data_access_action: domain 'reads' 'from' datastore phrase?
+                  | domain 'writes' 'to' datastore phrase?     // an update in CRUD reports
+                  | domain 'creates' 'in' datastore phrase?
+                  | domain 'deletes' 'from' datastore phrase?;
+
 return_action: domain 'returns' 'to' domain connector_word? phrase
             | domain 'returns' connector_word? phrase;
 
@@ -198,6 +290,33 @@ identifier: IDENTIFIER
           | 'for'
           | 'with'
           | 'by'
+          | 'rules'
+          | 'no_sync'
+          | 'calls'
+          | 'only'
+          | 'any'
+          | 'max_sync_hops'
+          | 'saga'
+          | 'step'
+          | 'compensate'
+          | 'context_map'
+          | 'environment'
+          | 'datastores'
+          | 'type'
+          | 'engine_version'
+          | 'shared_by'
+          | 'reads'
+          | 'writes'
+          | 'creates'
+          | 'deletes'
+          | 'external_system'
+          | 'protocol'
+          | 'owner'
+          | 'description'
+          | 'persona'
+          | 'uses'
+          | 'roles'
+          | 'permissions'
           | DOMAINS      // 'domains' token
           | DATA_STORES  // 'data-stores' token
           | LANGUAGE     // 'language' token